@@ -2,6 +2,8 @@
 // sub-packages.
 package adapters
 
+import "database/sql"
+
 // Adapter interface is the minimal implementation for an adapter.
 type Adapter interface {
 	// DriverName must return the driver name to be used with sql.Open()
@@ -20,6 +22,9 @@ type Adapter interface {
 //
 // ReplacePlaceholders changes all given placeholders in given sql query with
 // the placeholder used by the database targeted by the adapter.
+//
+// Deprecated: set Capabilities.Placeholders instead, the method is only
+// called for the adapters not implementing CapabilitiesDescriber.
 type PlaceholdersReplacer interface {
 	ReplacePlaceholders(string, string) string
 }
@@ -40,6 +45,10 @@ type PlaceholdersReplacer interface {
 // have expressions returning new values. The purpose is to always get new
 // values when the database could either return the old or new values
 // (before/after execution of the sql statement).
+//
+// godb builds the clause according to Capabilities.ReturningPosition, the
+// interface is only used for the adapters not implementing
+// CapabilitiesDescriber, and returned by godb.DB.ReturningBuilder.
 type ReturningBuilder interface {
 	ReturningBuild([]string) string
 	FormatForNewValues([]string) []string
@@ -54,10 +63,12 @@ type ReturningBuilder interface {
 type ReturningPosition int
 
 const (
-	// ReturningPostgreSQL for PostgreSQL
+	// ReturningPostgreSQL for PostgreSQL, RETURNING at the end of the
+	// statements (the zero value is handled the same way)
 	ReturningPostgreSQL ReturningPosition = 1
-	// ReturningSQLServer for SQL Server
-	ReturningSQLServer = 2
+	// ReturningSQLServer for SQL Server, OUTPUT INSERTED.column before VALUES
+	// and WHERE
+	ReturningSQLServer ReturningPosition = 2
 )
 
 // SQLPart is a struct containing a custom part of SQL query builded by an
@@ -71,6 +82,9 @@ type SQLPart struct {
 //
 // BuildLimit get an integer and returns a string containing a LIMIT sql clause
 // or its equivalent for the adapter, and an array of sql arguments.
+//
+// Deprecated: set Capabilities.Pagination instead, the method is only
+// called for the adapters not implementing CapabilitiesDescriber.
 type LimitBuilder interface {
 	BuildLimit(int) *SQLPart
 }
@@ -79,6 +93,9 @@ type LimitBuilder interface {
 //
 // BuildOffset get an integer and returns a string containing an OFFSET sql
 // clause or its equivalent for the adapter, and an array of sql arguments.
+//
+// Deprecated: set Capabilities.Pagination instead, the method is only
+// called for the adapters not implementing CapabilitiesDescriber.
 type OffsetBuilder interface {
 	BuildOffset(int) *SQLPart
 }
@@ -88,6 +105,34 @@ type OffsetBuilder interface {
 //
 // The IsOffsetFirst returns true is the OFFSET clause has to precede the
 // LIMIT clause. By default the LIMIT is before the OFFSET.
+//
+// Deprecated: set Capabilities.Pagination instead, the method is only
+// called for the adapters not implementing CapabilitiesDescriber.
 type LimitOffsetOrderer interface {
 	IsOffsetFirst() bool
 }
+
+// MultiTableStyle specify how UPDATE and DELETE statements use other tables
+// (see Capabilities).
+type MultiTableStyle int
+
+const (
+	// MultiTableSubquery uses a correlated EXISTS subquery (default case)
+	MultiTableSubquery MultiTableStyle = 0
+	// MultiTablePostgreSQL uses UPDATE ... FROM and DELETE ... USING
+	MultiTablePostgreSQL MultiTableStyle = 1
+	// MultiTableSQLServer uses UPDATE ... FROM and DELETE ... FROM ... FROM
+	MultiTableSQLServer MultiTableStyle = 2
+	// MultiTableMySQL uses UPDATE ... JOIN and DELETE ... FROM ... JOIN
+	MultiTableMySQL MultiTableStyle = 3
+)
+
+// ServerDetector is an interface wrapping the optional DetectServer method.
+//
+// DetectServer queries the database server and returns the adapter to use
+// with it, according to its kind and version. It's called by godb.Open.
+// Unlike the capabilities, the adapter can't be described without the
+// connection.
+type ServerDetector interface {
+	DetectServer(*sql.DB) (Adapter, error)
+}
@@ -0,0 +1,198 @@
+package adapters
+
+import "strconv"
+
+// CapabilitiesDescriber is an interface wrapping the optional Capabilities
+// method.
+//
+// Capabilities returns the description of what the database supports. It's
+// consulted by the builders, the unsupported features are rejected before
+// the statements are executed. For adapters not implementing it, only the
+// RETURNING clause is supported, if they implement ReturningBuilder.
+type CapabilitiesDescriber interface {
+	Capabilities() Capabilities
+}
+
+// Capabilities describes the features supported by a database. Its zero
+// value describes a database supporting only the common SQL.
+type Capabilities struct {
+	// Placeholders is the style of the placeholders given to the driver, the
+	// ? placeholders of the statements are replaced if needed.
+	Placeholders PlaceholderStyle
+	// Pagination is the syntax of the LIMIT and OFFSET clauses.
+	Pagination PaginationStyle
+	// Returning tells which statements accept a RETURNING clause (or
+	// similar, see ReturningPosition).
+	Returning ReturningSupport
+	// ReturningPosition is the syntax of the clause returning values, a
+	// RETURNING clause at the end of the statements by default.
+	ReturningPosition ReturningPosition
+	// MultiTable is how UPDATE and DELETE statements use other tables (with
+	// From, Using or Join).
+	MultiTable MultiTableStyle
+	// Upsert is the syntax of INSERT statements handling the conflicts.
+	Upsert UpsertDialect
+	// UpsertStatement is true if the UPSERT INTO statement is supported
+	// (CockroachDB).
+	UpsertStatement bool
+	// MaxParameters is the maximum count of arguments of a statement, zero
+	// if there is no known limit.
+	MaxParameters int
+	// Savepoints is the syntax of the savepoints in transactions.
+	Savepoints SavepointDialect
+	// RowValueComparison is true if (a, b) > (?, ?) is supported, otherwise
+	// it's emulated with OR conditions.
+	RowValueComparison bool
+	// BooleanLiterals is the way booleans are written in SQL.
+	BooleanLiterals BooleanLiteralStyle
+	// ILike is true if the database has an ILIKE operator, otherwise it's
+	// emulated with LOWER(column) LIKE LOWER(pattern).
+	ILike bool
+	// Locking is the row locking clause accepted by SELECT statements.
+	Locking LockingSupport
+	// NullsOrder is true if NULLS FIRST and NULLS LAST are accepted in ORDER
+	// BY clauses, otherwise they're emulated with a CASE expression.
+	NullsOrder bool
+	// GroupingSets is true if ROLLUP, CUBE and GROUPING SETS are accepted in
+	// GROUP BY clauses.
+	GroupingSets bool
+	// WindowFunctions is true if the OVER and WINDOW clauses are accepted.
+	WindowFunctions bool
+	// AggregateFilter is true if the aggregate functions accept a FILTER
+	// (WHERE ...) clause.
+	AggregateFilter bool
+	// CTE is true if the statements accept a WITH clause (common table
+	// expressions).
+	CTE bool
+	// UpdateDeleteLimit is true if ORDER BY and LIMIT are accepted in UPDATE
+	// and DELETE statements.
+	UpdateDeleteLimit bool
+	// Syntax describes the literals and comments, to find the placeholders.
+	Syntax Syntax
+}
+
+// PlaceholderStyle specify the placeholders given to the driver.
+type PlaceholderStyle int
+
+const (
+	// PlaceholderQuestion uses ? (default case)
+	PlaceholderQuestion PlaceholderStyle = 0
+	// PlaceholderDollar uses $1, $2, ... (PostgreSQL)
+	PlaceholderDollar PlaceholderStyle = 1
+	// PlaceholderAtP uses @p1, @p2, ... (SQL Server)
+	PlaceholderAtP PlaceholderStyle = 2
+	// PlaceholderColon uses :1, :2, ...
+	PlaceholderColon PlaceholderStyle = 3
+)
+
+// PaginationStyle specify the syntax of the LIMIT and OFFSET clauses.
+type PaginationStyle int
+
+const (
+	// PaginationLimitOffset uses LIMIT ? OFFSET ? (default case)
+	PaginationLimitOffset PaginationStyle = 0
+	// PaginationOffsetFetch uses OFFSET ? ROWS FETCH NEXT ? ROWS ONLY (SQL
+	// Server)
+	PaginationOffsetFetch PaginationStyle = 1
+	// PaginationTop uses SELECT TOP (?), without offset
+	PaginationTop PaginationStyle = 2
+)
+
+// ReturningSupport specify which statements accept a RETURNING clause (or
+// similar).
+type ReturningSupport int
+
+const (
+	// ReturningNone means that RETURNING is not supported (default case)
+	ReturningNone ReturningSupport = 0
+	// ReturningInsertDelete means that RETURNING is accepted in INSERT and
+	// DELETE statements, but not in UPDATE statements (MariaDB)
+	ReturningInsertDelete ReturningSupport = 1
+	// ReturningAll means that RETURNING is accepted in INSERT, UPDATE and
+	// DELETE statements
+	ReturningAll ReturningSupport = 2
+)
+
+// UpsertDialect specify the syntax of the upserts.
+type UpsertDialect int
+
+const (
+	// UpsertNone means that upserts are not supported (default case)
+	UpsertNone UpsertDialect = 0
+	// UpsertOnConflict uses ON CONFLICT ... DO UPDATE / DO NOTHING
+	UpsertOnConflict UpsertDialect = 1
+	// UpsertOnDuplicateKey uses ON DUPLICATE KEY UPDATE with VALUES(column)
+	UpsertOnDuplicateKey UpsertDialect = 2
+	// UpsertOnDuplicateKeyRowAlias uses ON DUPLICATE KEY UPDATE with a row
+	// alias (MySQL 8.0.19 and later)
+	UpsertOnDuplicateKeyRowAlias UpsertDialect = 3
+)
+
+// SavepointDialect specify the syntax of the savepoints.
+type SavepointDialect int
+
+const (
+	// SavepointsNone means that savepoints are not supported (default case)
+	SavepointsNone SavepointDialect = 0
+	// SavepointsStandard uses SAVEPOINT, ROLLBACK TO SAVEPOINT and RELEASE
+	// SAVEPOINT
+	SavepointsStandard SavepointDialect = 1
+	// SavepointsSQLServer uses SAVE TRANSACTION and ROLLBACK TRANSACTION
+	SavepointsSQLServer SavepointDialect = 2
+)
+
+// BooleanLiteralStyle specify how booleans are written in SQL.
+type BooleanLiteralStyle int
+
+const (
+	// BooleanTrueFalse uses TRUE and FALSE (default case)
+	BooleanTrueFalse BooleanLiteralStyle = 0
+	// BooleanOneZero uses 1 and 0
+	BooleanOneZero BooleanLiteralStyle = 1
+)
+
+// LockingSupport specify the row locking clause accepted by SELECT
+// statements.
+type LockingSupport int
+
+const (
+	// LockingNone means that row locking is not supported (default case)
+	LockingNone LockingSupport = 0
+	// LockingForUpdate accepts FOR UPDATE
+	LockingForUpdate LockingSupport = 1
+	// LockingSkipLocked accepts FOR UPDATE and FOR UPDATE SKIP LOCKED
+	LockingSkipLocked LockingSupport = 2
+)
+
+// BooleanLiteral returns the SQL literal of the given boolean.
+func (c Capabilities) BooleanLiteral(value bool) string {
+	if c.BooleanLiterals == BooleanOneZero {
+		if value {
+			return "1"
+		}
+		return "0"
+	}
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// ReplacePlaceholders replaces the given placeholders of the sql string
+// with the ones of the database (see Placeholders), ignoring the literals and
+// comments (see Syntax). The sql string is unchanged with PlaceholderQuestion.
+func (c Capabilities) ReplacePlaceholders(placeholder string, sql string) string {
+	if c.Placeholders == PlaceholderQuestion {
+		return sql
+	}
+	return c.Syntax.ReplacePlaceholders(sql, placeholder, func(index int) string {
+		switch c.Placeholders {
+		case PlaceholderDollar:
+			return "$" + strconv.Itoa(index+1)
+		case PlaceholderAtP:
+			return "@p" + strconv.Itoa(index+1)
+		default:
+			return ":" + strconv.Itoa(index+1)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package cockroachdb
+
+import (
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+)
+
+// CockroachDB is the adapter for CockroachDB, using the PostgreSQL wire
+// protocol and driver. The errors are parsed as PostgreSQL ones, the retry
+// errors being serialization failures (40001).
+type CockroachDB struct {
+	postgresql.PostgreSQL
+}
+
+var Adapter = CockroachDB{}
+
+func (c CockroachDB) Capabilities() adapters.Capabilities {
+	capabilities := c.PostgreSQL.Capabilities()
+	capabilities.UpsertStatement = true
+	capabilities.Locking = adapters.LockingForUpdate
+	capabilities.GroupingSets = false
+	return capabilities
+}
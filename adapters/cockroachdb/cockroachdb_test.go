@@ -0,0 +1,49 @@
+package cockroachdb
+
+import (
+	"errors"
+	"testing"
+
+	pq "github.com/lib/pq"
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dberror"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseError(t *testing.T) {
+	Convey("Given a retry error", t, func() {
+		err := &pq.Error{Code: "40001", Message: "restart transaction: TransactionRetryWithProtoRefreshError: ReadWithinUncertaintyIntervalError"}
+		Convey("ParseError returns a SerializationFailure", func() {
+			var serializationFailure dberror.SerializationFailure
+			So(errors.As(Adapter.ParseError(err), &serializationFailure), ShouldBeTrue)
+			So(serializationFailure.Err, ShouldEqual, err)
+		})
+	})
+
+	Convey("Given a unique violation", t, func() {
+		err := &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "books_title_key"`}
+		Convey("ParseError returns a UniqueConstraint with the constraint name", func() {
+			parsedErr, ok := Adapter.ParseError(err).(dberror.UniqueConstraint)
+			So(ok, ShouldBeTrue)
+			So(parsedErr.Field, ShouldEqual, "books_title_key")
+		})
+	})
+
+	Convey("Other errors are parsed as PostgreSQL ones", t, func() {
+		err := &pq.Error{Code: "23503", Message: `insert on table "inventories" violates foreign key constraint "fk_book"`}
+		_, ok := Adapter.ParseError(err).(dberror.ForeignKeyConstraint)
+		So(ok, ShouldBeTrue)
+		So(Adapter.ParseError(nil), ShouldBeNil)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	Convey("The capabilities are the PostgreSQL ones, with UPSERT", t, func() {
+		capabilities := Adapter.Capabilities()
+		So(capabilities.UpsertStatement, ShouldBeTrue)
+		So(capabilities.Upsert, ShouldEqual, adapters.UpsertOnConflict)
+		So(capabilities.Returning, ShouldEqual, adapters.ReturningAll)
+		So(capabilities.GroupingSets, ShouldBeFalse)
+		So(Adapter.ReplacePlaceholders("?", "a = ? AND b = ?"), ShouldEqual, "a = $1 AND b = $2")
+	})
+}
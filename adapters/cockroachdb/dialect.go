@@ -0,0 +1,16 @@
+package cockroachdb
+
+import (
+	"github.com/samonzeweb/godb/adapters/generic"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+)
+
+// Dialect describes CockroachDB for the generic adapter.
+var Dialect = cockroachDBDialect()
+
+func cockroachDBDialect() generic.Dialect {
+	dialect := postgresql.Dialect
+	dialect.Capabilities = Adapter.Capabilities()
+	dialect.ParseError = Adapter.ParseError
+	return dialect
+}
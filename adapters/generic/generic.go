@@ -0,0 +1,59 @@
+// Package generic contains an adapter built from the description of a
+// dialect, for databases without a dedicated adapter.
+package generic
+
+import (
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Dialect describes the SQL dialect of a database.
+type Dialect struct {
+	// DriverName is the driver name to be used with sql.Open()
+	DriverName string
+	// QuoteStart and QuoteEnd surround the quoted identifiers, ie " and " for
+	// PostgreSQL, [ and ] for SQL Server.
+	QuoteStart, QuoteEnd string
+	// Capabilities describes the features supported by the database,
+	// including its placeholders, pagination and RETURNING syntax. The
+	// escaped placeholders are used only if the placeholders are replaced.
+	Capabilities adapters.Capabilities
+	// ParseError parses the driver errors, it could be nil.
+	ParseError func(error) error
+}
+
+// Generic is an adapter built from a Dialect.
+type Generic struct {
+	dialect Dialect
+}
+
+// New returns the adapter of the given dialect.
+func New(dialect Dialect) adapters.Adapter {
+	return Generic{dialect: dialect}
+}
+
+// Dialect returns the description of the dialect of the adapter.
+func (g Generic) Dialect() Dialect {
+	return g.dialect
+}
+
+func (g Generic) DriverName() string {
+	return g.dialect.DriverName
+}
+
+func (g Generic) Quote(identifier string) string {
+	return g.dialect.QuoteStart + identifier + g.dialect.QuoteEnd
+}
+
+func (g Generic) Capabilities() adapters.Capabilities {
+	capabilities := g.dialect.Capabilities
+	// The placeholders can be escaped only if they are replaced
+	capabilities.Syntax.PlaceholderEscapes = capabilities.Placeholders != adapters.PlaceholderQuestion
+	return capabilities
+}
+
+func (g Generic) ParseError(err error) error {
+	if g.dialect.ParseError == nil || err == nil {
+		return err
+	}
+	return g.dialect.ParseError(err)
+}
@@ -0,0 +1,66 @@
+package generic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQuote(t *testing.T) {
+	Convey("Quote surrounds the identifier with the quote characters", t, func() {
+		adapter := New(Dialect{QuoteStart: "[", QuoteEnd: "]"})
+		So(adapter.Quote("foo"), ShouldEqual, "[foo]")
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	Convey("Capabilities returns the ones of the dialect", t, func() {
+		capabilities := adapters.Capabilities{
+			Placeholders:      adapters.PlaceholderAtP,
+			Pagination:        adapters.PaginationOffsetFetch,
+			Returning:         adapters.ReturningAll,
+			ReturningPosition: adapters.ReturningSQLServer,
+			MultiTable:        adapters.MultiTableSQLServer,
+			Syntax:            adapters.Syntax{PlaceholderEscapes: true},
+		}
+		adapter := New(Dialect{Capabilities: capabilities}).(Generic)
+		So(adapter.Capabilities(), ShouldResemble, capabilities)
+	})
+
+	Convey("Given a statement with placeholders", t, func() {
+		sql := "SELECT * FROM foo WHERE bar = ? AND baz = '?' AND qux = ??"
+
+		Convey("PlaceholderQuestion keeps the placeholders and their escapes", func() {
+			adapter := New(Dialect{
+				Capabilities: adapters.Capabilities{Syntax: adapters.Syntax{PlaceholderEscapes: true}},
+			}).(Generic)
+			So(adapter.Capabilities().Syntax.PlaceholderEscapes, ShouldBeFalse)
+			So(adapter.Capabilities().ReplacePlaceholders("?", sql), ShouldEqual, sql)
+		})
+
+		Convey("PlaceholderDollar uses numbered placeholders and unescapes the others", func() {
+			adapter := New(Dialect{
+				Capabilities: adapters.Capabilities{Placeholders: adapters.PlaceholderDollar},
+			}).(Generic)
+			So(adapter.Capabilities().ReplacePlaceholders("?", sql), ShouldEqual, "SELECT * FROM foo WHERE bar = $1 AND baz = '?' AND qux = ?")
+		})
+	})
+}
+
+func TestParseError(t *testing.T) {
+	Convey("ParseError returns the error without parser", t, func() {
+		err := New(Dialect{}).ParseError(errDummy)
+		So(err, ShouldEqual, errDummy)
+	})
+
+	Convey("ParseError uses the parser of the dialect", t, func() {
+		parsed := errors.New("parsed")
+		adapter := New(Dialect{ParseError: func(error) error { return parsed }})
+		So(adapter.ParseError(errDummy), ShouldEqual, parsed)
+		So(adapter.ParseError(nil), ShouldBeNil)
+	})
+}
+
+var errDummy = errors.New("dummy")
@@ -0,0 +1,14 @@
+package mssql
+
+import (
+	"github.com/samonzeweb/godb/adapters/generic"
+)
+
+// Dialect describes SQL Server for the generic adapter.
+var Dialect = generic.Dialect{
+	DriverName:   "sqlserver",
+	QuoteStart:   "[",
+	QuoteEnd:     "]",
+	Capabilities: Adapter.Capabilities(),
+	ParseError:   Adapter.ParseError,
+}
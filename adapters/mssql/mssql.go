@@ -2,7 +2,8 @@ package mssql
 
 import (
 	"bytes"
-	"strconv"
+	"database/sql/driver"
+	"errors"
 	"strings"
 
 	"github.com/samonzeweb/godb/adapters"
@@ -29,22 +30,8 @@ func (MSSQL) Quote(identifier string) string {
 	return "[" + identifier + "]"
 }
 
-func (MSSQL) ReplacePlaceholders(originalPlaceholder string, sql string) string {
-	sqlBuffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
-	count := 1
-	for {
-		pp := strings.Index(sql, originalPlaceholder)
-		if pp == -1 {
-			break
-		}
-		sqlBuffer.WriteString(sql[:pp])
-		sqlBuffer.WriteString("@p")
-		sqlBuffer.WriteString(strconv.Itoa(count))
-		count++
-		sql = sql[pp+1:]
-	}
-	sqlBuffer.WriteString(sql)
-	return sqlBuffer.String()
+func (m MSSQL) ReplacePlaceholders(originalPlaceholder string, sql string) string {
+	return m.Capabilities().ReplacePlaceholders(originalPlaceholder, sql)
 }
 
 func (m MSSQL) ReturningBuild(columns []string) string {
@@ -71,6 +58,25 @@ func (m MSSQL) GetReturningPosition() adapters.ReturningPosition {
 	return adapters.ReturningSQLServer
 }
 
+func (MSSQL) Capabilities() adapters.Capabilities {
+	return adapters.Capabilities{
+		Placeholders:      adapters.PlaceholderAtP,
+		Pagination:        adapters.PaginationOffsetFetch,
+		Returning:         adapters.ReturningAll,
+		ReturningPosition: adapters.ReturningSQLServer,
+		MultiTable:        adapters.MultiTableSQLServer,
+		MaxParameters:     2100,
+		Savepoints:        adapters.SavepointsSQLServer,
+		BooleanLiterals:   adapters.BooleanOneZero,
+		GroupingSets:      true,
+		WindowFunctions:   true,
+		CTE:               true,
+		// The standard syntax, with the bracketed identifiers and the
+		// escaped placeholders
+		Syntax: adapters.Syntax{BracketIdentifiers: true, PlaceholderEscapes: true},
+	}
+}
+
 func (MSSQL) BuildLimit(limit int) *adapters.SQLPart {
 	sqlPart := adapters.SQLPart{}
 	sqlPart.Sql = "FETCH NEXT ? ROWS ONLY"
@@ -100,15 +106,53 @@ func (MSSQL) ParseError(err error) error {
 		return nil
 	}
 	if e, ok := err.(ErrorWithNumber); ok {
+		message := err.Error()
 		switch e.SQLErrorNumber() {
 		case 2601:
-			return dberror.UniqueConstraint{Message: err.Error(), Field: "", Err: err}
+			table := dberror.ExtractStr(message, "in object '", "'")
+			constraint := dberror.ExtractStr(message, "unique index '", "'")
+			return dberror.UniqueConstraint{Message: message, Field: "", Table: table, Constraint: constraint, Err: err}
 		case 2627:
-			return dberror.UniqueConstraint{Message: err.Error(), Field: "", Err: err}
+			table := dberror.ExtractStr(message, "in object '", "'")
+			constraint := dberror.ExtractStr(message, "constraint '", "'")
+			return dberror.UniqueConstraint{Message: message, Field: "", Table: table, Constraint: constraint, Err: err}
 		case 547:
-			return dberror.CheckConstraint{Message: err.Error(), Field: dberror.ExtractStr(err.Error(), "column '", "'"), Err: err}
+			// The same error is used for the foreign keys and the checks
+			column := dberror.ExtractStr(message, "column '", "'")
+			table := dberror.ExtractStr(message, "table \"", "\"")
+			if strings.Contains(message, "CHECK constraint") {
+				constraint := dberror.ExtractStr(message, "CHECK constraint \"", "\"")
+				return dberror.CheckConstraint{Message: message, Field: column, Table: table, Column: column, Constraint: constraint, Err: err}
+			}
+			constraint := dberror.ExtractStr(message, "KEY constraint \"", "\"")
+			if constraint == "" {
+				constraint = dberror.ExtractStr(message, "REFERENCE constraint \"", "\"")
+			}
+			return dberror.ForeignKeyConstraint{Message: message, Field: column, Table: table, Column: column, Constraint: constraint, Err: err}
+		case 515:
+			column := dberror.ExtractStr(message, "into column '", "'")
+			table := dberror.ExtractStr(message, "table '", "'")
+			return dberror.NotNullConstraint{Message: message, Field: column, Table: table, Column: column, Err: err}
+		case 1205:
+			return dberror.Deadlock{Message: message, Err: err}
+		case 3960:
+			return dberror.SerializationFailure{Message: message, Err: err}
+		case 1222:
+			return dberror.LockTimeout{Message: message, Err: err}
+		case 8152:
+			return dberror.StringTruncation{Message: message, Err: err}
+		case 2628:
+			table := dberror.ExtractStr(message, "in table '", "'")
+			column := dberror.ExtractStr(message, "column '", "'")
+			return dberror.StringTruncation{Message: message, Table: table, Column: column, Err: err}
+		case 220, 8115:
+			return dberror.NumericOverflow{Message: message, Err: err}
 		}
 	}
 
+	if errors.Is(err, driver.ErrBadConn) {
+		return dberror.ConnectionLost{Message: err.Error(), Err: err}
+	}
+
 	return err
 }
@@ -1,8 +1,12 @@
 package mssql
 
 import (
+	"database/sql/driver"
 	"testing"
 
+	mssqldb "github.com/denisenkom/go-mssqldb"
+	"github.com/samonzeweb/godb/dberror"
+
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -38,3 +42,52 @@ func TestFormatForNewValues(t *testing.T) {
 		})
 	})
 }
+
+func TestParseError(t *testing.T) {
+	Convey("Given SQL Server errors", t, func() {
+		uniqueIndex := mssqldb.Error{Number: 2601, Message: "Cannot insert duplicate key row in object 'dbo.books' with unique index 'ix_title'. The duplicate key value is (Foundation)."}
+		uniqueConstraint := mssqldb.Error{Number: 2627, Message: "Violation of UNIQUE KEY constraint 'uq_title'. Cannot insert duplicate key in object 'dbo.books'. The duplicate key value is (Foundation)."}
+		foreignKey := mssqldb.Error{Number: 547, Message: `The INSERT statement conflicted with the FOREIGN KEY constraint "fk_book". The conflict occurred in database "godb", table "dbo.books", column 'id'.`}
+		reference := mssqldb.Error{Number: 547, Message: `The DELETE statement conflicted with the REFERENCE constraint "fk_book". The conflict occurred in database "godb", table "dbo.inventories", column 'book_id'.`}
+		check := mssqldb.Error{Number: 547, Message: `The INSERT statement conflicted with the CHECK constraint "positive_pages". The conflict occurred in database "godb", table "dbo.books", column 'pages'.`}
+		notNull := mssqldb.Error{Number: 515, Message: "Cannot insert the value NULL into column 'title', table 'godb.dbo.books'; column does not allow nulls. INSERT fails."}
+		deadlock := mssqldb.Error{Number: 1205, Message: "Transaction (Process ID 52) was deadlocked on lock resources with another process and has been chosen as the deadlock victim. Rerun the transaction."}
+		snapshot := mssqldb.Error{Number: 3960, Message: "Snapshot isolation transaction aborted due to update conflict."}
+		lockTimeout := mssqldb.Error{Number: 1222, Message: "Lock request time out period exceeded."}
+		truncation := mssqldb.Error{Number: 8152, Message: "String or binary data would be truncated."}
+		detailedTruncation := mssqldb.Error{Number: 2628, Message: "String or binary data would be truncated in table 'godb.dbo.books', column 'title'. Truncated value: 'Foundation'."}
+		overflow := mssqldb.Error{Number: 8115, Message: "Arithmetic overflow error converting expression to data type int."}
+		other := mssqldb.Error{Number: 208, Message: "Invalid object name 'unknown'."}
+
+		cases := []struct {
+			name     string
+			err      error
+			expected error
+		}{
+			{"unique index violation", uniqueIndex, dberror.UniqueConstraint{Message: uniqueIndex.Error(), Table: "dbo.books", Constraint: "ix_title", Err: uniqueIndex}},
+			{"unique constraint violation", uniqueConstraint, dberror.UniqueConstraint{Message: uniqueConstraint.Error(), Table: "dbo.books", Constraint: "uq_title", Err: uniqueConstraint}},
+			{"foreign key violation", foreignKey, dberror.ForeignKeyConstraint{Message: foreignKey.Error(), Field: "id", Table: "dbo.books", Column: "id", Constraint: "fk_book", Err: foreignKey}},
+			{"reference violation", reference, dberror.ForeignKeyConstraint{Message: reference.Error(), Field: "book_id", Table: "dbo.inventories", Column: "book_id", Constraint: "fk_book", Err: reference}},
+			{"check violation", check, dberror.CheckConstraint{Message: check.Error(), Field: "pages", Table: "dbo.books", Column: "pages", Constraint: "positive_pages", Err: check}},
+			{"not null violation", notNull, dberror.NotNullConstraint{Message: notNull.Error(), Field: "title", Table: "godb.dbo.books", Column: "title", Err: notNull}},
+			{"deadlock", deadlock, dberror.Deadlock{Message: deadlock.Error(), Err: deadlock}},
+			{"snapshot update conflict", snapshot, dberror.SerializationFailure{Message: snapshot.Error(), Err: snapshot}},
+			{"lock timeout", lockTimeout, dberror.LockTimeout{Message: lockTimeout.Error(), Err: lockTimeout}},
+			{"bad connection", driver.ErrBadConn, dberror.ConnectionLost{Message: driver.ErrBadConn.Error(), Err: driver.ErrBadConn}},
+			{"string truncation", truncation, dberror.StringTruncation{Message: truncation.Error(), Err: truncation}},
+			{"detailed string truncation", detailedTruncation, dberror.StringTruncation{Message: detailedTruncation.Error(), Table: "godb.dbo.books", Column: "title", Err: detailedTruncation}},
+			{"numeric overflow", overflow, dberror.NumericOverflow{Message: overflow.Error(), Err: overflow}},
+			{"other error", other, other},
+		}
+		for _, c := range cases {
+			Convey("ParseError classifies a "+c.name, func() {
+				parsedErr := Adapter.ParseError(c.err)
+				So(parsedErr, ShouldResemble, c.expected)
+			})
+		}
+
+		Convey("ParseError returns nil without error", func() {
+			So(Adapter.ParseError(nil), ShouldBeNil)
+		})
+	})
+}
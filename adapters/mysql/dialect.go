@@ -0,0 +1,14 @@
+package mysql
+
+import (
+	"github.com/samonzeweb/godb/adapters/generic"
+)
+
+// Dialect describes MySQL for the generic adapter.
+var Dialect = generic.Dialect{
+	DriverName:   "mysql",
+	QuoteStart:   "`",
+	QuoteEnd:     "`",
+	Capabilities: Adapter.Capabilities(),
+	ParseError:   Adapter.ParseError,
+}
@@ -1,7 +1,12 @@
 package mysql
 
 import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+
 	"github.com/go-sql-driver/mysql"
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/dberror"
 )
 
@@ -17,6 +22,20 @@ func (MySQL) Quote(identifier string) string {
 	return "`" + identifier + "`"
 }
 
+func (MySQL) Capabilities() adapters.Capabilities {
+	return adapters.Capabilities{
+		MultiTable:         adapters.MultiTableMySQL,
+		Upsert:             adapters.UpsertOnDuplicateKey,
+		MaxParameters:      65535,
+		Savepoints:         adapters.SavepointsStandard,
+		RowValueComparison: true,
+		BooleanLiterals:    adapters.BooleanTrueFalse,
+		Locking:            adapters.LockingForUpdate,
+		UpdateDeleteLimit:  true,
+		Syntax:             adapters.MySQLSyntax,
+	}
+}
+
 func (MySQL) ParseError(err error) error {
 	if err == nil {
 		return nil
@@ -24,11 +43,40 @@ func (MySQL) ParseError(err error) error {
 	if e, ok := err.(*mysql.MySQLError); ok {
 		switch e.Number {
 		case 1062:
-			return dberror.UniqueConstraint{Message: e.Error(), Field: dberror.ExtractStr(e.Message, "key '", "'"), Err: e}
-		case 1452:
-			return dberror.CheckConstraint{Message: e.Error(), Field: dberror.ExtractStr(e.Message, "CONSTRAINT `", "`"), Err: e}
+			// The key is prefixed by the table since MySQL 8.0.19
+			key := dberror.ExtractStr(e.Message, "for key '", "'")
+			table, constraint := dberror.SplitName(key)
+			return dberror.UniqueConstraint{Message: e.Error(), Field: key, Table: table, Constraint: constraint, Err: e}
+		case 1451, 1452:
+			constraint := dberror.ExtractStr(e.Message, "CONSTRAINT `", "`")
+			_, table := dberror.SplitName(strings.ReplaceAll(dberror.ExtractStr(e.Message, "fails (", ","), "`", ""))
+			column := dberror.ExtractStr(e.Message, "FOREIGN KEY (`", "`")
+			return dberror.ForeignKeyConstraint{Message: e.Error(), Field: constraint, Table: table, Column: column, Constraint: constraint, Err: e}
+		case 3819:
+			constraint := dberror.ExtractStr(e.Message, "constraint '", "'")
+			return dberror.CheckConstraint{Message: e.Error(), Field: constraint, Constraint: constraint, Err: e}
+		case 1048:
+			column := dberror.ExtractStr(e.Message, "Column '", "'")
+			return dberror.NotNullConstraint{Message: e.Error(), Field: column, Column: column, Err: e}
+		case 1364:
+			column := dberror.ExtractStr(e.Message, "Field '", "'")
+			return dberror.NotNullConstraint{Message: e.Error(), Field: column, Column: column, Err: e}
+		case 1213:
+			return dberror.Deadlock{Message: e.Error(), Err: e}
+		case 1205:
+			return dberror.LockTimeout{Message: e.Error(), Err: e}
+		case 1406:
+			return dberror.StringTruncation{Message: e.Error(), Column: dberror.ExtractStr(e.Message, "column '", "'"), Err: e}
+		case 1264:
+			return dberror.NumericOverflow{Message: e.Error(), Column: dberror.ExtractStr(e.Message, "column '", "'"), Err: e}
+		case 1690:
+			return dberror.NumericOverflow{Message: e.Error(), Err: e}
 		}
 	}
 
+	if errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) {
+		return dberror.ConnectionLost{Message: err.Error(), Err: err}
+	}
+
 	return err
 }
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/samonzeweb/godb/dberror"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseError(t *testing.T) {
+	Convey("Given MySQL errors", t, func() {
+		unique := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Foundation' for key 'books.title'"}
+		uniqueWithoutTable := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Foundation' for key 'title'"}
+		foreignKey := &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`godb`.`inventories`, CONSTRAINT `fk_book` FOREIGN KEY (`book_id`) REFERENCES `books` (`id`))"}
+		parentRow := &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails (`godb`.`inventories`, CONSTRAINT `fk_book` FOREIGN KEY (`book_id`) REFERENCES `books` (`id`))"}
+		check := &mysql.MySQLError{Number: 3819, Message: "Check constraint 'positive_pages' is violated."}
+		notNull := &mysql.MySQLError{Number: 1048, Message: "Column 'title' cannot be null"}
+		noDefault := &mysql.MySQLError{Number: 1364, Message: "Field 'title' doesn't have a default value"}
+		deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}
+		lockTimeout := &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}
+		truncation := &mysql.MySQLError{Number: 1406, Message: "Data too long for column 'title' at row 1"}
+		overflow := &mysql.MySQLError{Number: 1264, Message: "Out of range value for column 'pages' at row 1"}
+		unsignedOverflow := &mysql.MySQLError{Number: 1690, Message: "BIGINT UNSIGNED value is out of range in '(`godb`.`books`.`pages` - 1000)'"}
+		other := &mysql.MySQLError{Number: 1146, Message: "Table 'godb.unknown' doesn't exist"}
+
+		cases := []struct {
+			name     string
+			err      error
+			expected error
+		}{
+			{"unique violation", unique, dberror.UniqueConstraint{Message: unique.Error(), Field: "books.title", Table: "books", Constraint: "title", Err: unique}},
+			{"unique violation without table", uniqueWithoutTable, dberror.UniqueConstraint{Message: uniqueWithoutTable.Error(), Field: "title", Constraint: "title", Err: uniqueWithoutTable}},
+			{"foreign key violation", foreignKey, dberror.ForeignKeyConstraint{Message: foreignKey.Error(), Field: "fk_book", Table: "inventories", Column: "book_id", Constraint: "fk_book", Err: foreignKey}},
+			{"foreign key violation of a parent row", parentRow, dberror.ForeignKeyConstraint{Message: parentRow.Error(), Field: "fk_book", Table: "inventories", Column: "book_id", Constraint: "fk_book", Err: parentRow}},
+			{"check violation", check, dberror.CheckConstraint{Message: check.Error(), Field: "positive_pages", Constraint: "positive_pages", Err: check}},
+			{"not null violation", notNull, dberror.NotNullConstraint{Message: notNull.Error(), Field: "title", Column: "title", Err: notNull}},
+			{"missing value", noDefault, dberror.NotNullConstraint{Message: noDefault.Error(), Field: "title", Column: "title", Err: noDefault}},
+			{"deadlock", deadlock, dberror.Deadlock{Message: deadlock.Error(), Err: deadlock}},
+			{"lock timeout", lockTimeout, dberror.LockTimeout{Message: lockTimeout.Error(), Err: lockTimeout}},
+			{"invalid connection", mysql.ErrInvalidConn, dberror.ConnectionLost{Message: mysql.ErrInvalidConn.Error(), Err: mysql.ErrInvalidConn}},
+			{"bad connection", driver.ErrBadConn, dberror.ConnectionLost{Message: driver.ErrBadConn.Error(), Err: driver.ErrBadConn}},
+			{"string truncation", truncation, dberror.StringTruncation{Message: truncation.Error(), Column: "title", Err: truncation}},
+			{"numeric overflow", overflow, dberror.NumericOverflow{Message: overflow.Error(), Column: "pages", Err: overflow}},
+			{"unsigned overflow", unsignedOverflow, dberror.NumericOverflow{Message: unsignedOverflow.Error(), Err: unsignedOverflow}},
+			{"other error", other, other},
+		}
+		for _, c := range cases {
+			Convey("ParseError classifies a "+c.name, func() {
+				parsedErr := Adapter.ParseError(c.err)
+				So(parsedErr, ShouldResemble, c.expected)
+				So(errors.Is(parsedErr, c.err), ShouldBeTrue)
+			})
+		}
+
+		Convey("ParseError returns nil without error", func() {
+			So(Adapter.ParseError(nil), ShouldBeNil)
+		})
+	})
+}
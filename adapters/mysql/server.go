@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Features describes what a MySQL or MariaDB server supports, according to
+// its kind and version.
+type Features struct {
+	// MariaDB is true for a MariaDB server, false for a MySQL one.
+	MariaDB bool
+	// Major, Minor and Patch are the server version numbers.
+	Major, Minor, Patch int
+	// Returning is true if INSERT and DELETE statements accept a RETURNING
+	// clause (MariaDB 10.5 and later). UPDATE statements never accept it.
+	Returning bool
+	// CTE is true if the WITH clause is supported (MySQL 8 and MariaDB 10.2).
+	CTE bool
+	// WindowFunctions is true if the OVER and WINDOW clauses are supported
+	// (MySQL 8 and MariaDB 10.2).
+	WindowFunctions bool
+	// RowAlias is true if INSERT ... ON DUPLICATE KEY UPDATE accepts a row
+	// alias, replacing the deprecated VALUES() function (MySQL 8.0.19).
+	RowAlias bool
+	// SkipLocked is true if SELECT ... FOR UPDATE accepts SKIP LOCKED
+	// (MySQL 8.0.1 and MariaDB 10.6).
+	SkipLocked bool
+}
+
+// atLeast returns true if the server version is at least the given one.
+func (f Features) atLeast(major, minor, patch int) bool {
+	if f.Major != major {
+		return f.Major > major
+	}
+	if f.Minor != minor {
+		return f.Minor > minor
+	}
+	return f.Patch >= patch
+}
+
+// ParseVersion returns the features of the server from the string given by
+// SELECT VERSION(), ie "8.0.32" or "10.6.12-MariaDB-log".
+func ParseVersion(version string) (Features, error) {
+	features := Features{
+		MariaDB: strings.Contains(strings.ToLower(version), "mariadb"),
+	}
+	// MariaDB could prefix its version for the replication with MySQL
+	if features.MariaDB {
+		version = strings.TrimPrefix(version, "5.5.5-")
+	}
+
+	if end := strings.IndexAny(version, "-+ "); end != -1 {
+		version = version[:end]
+	}
+	numbers := strings.Split(version, ".")
+	if len(numbers) < 2 || len(numbers) > 3 {
+		return Features{}, fmt.Errorf("invalid server version : %q", version)
+	}
+	parsed := make([]int, 3)
+	for i, number := range numbers {
+		value, err := strconv.Atoi(number)
+		if err != nil || value < 0 {
+			return Features{}, fmt.Errorf("invalid server version : %q", version)
+		}
+		parsed[i] = value
+	}
+	features.Major, features.Minor, features.Patch = parsed[0], parsed[1], parsed[2]
+
+	if features.MariaDB {
+		features.Returning = features.atLeast(10, 5, 0)
+		features.CTE = features.atLeast(10, 2, 1)
+		features.WindowFunctions = features.atLeast(10, 2, 0)
+		features.SkipLocked = features.atLeast(10, 6, 0)
+	} else {
+		features.CTE = features.atLeast(8, 0, 1)
+		features.WindowFunctions = features.atLeast(8, 0, 2)
+		features.RowAlias = features.atLeast(8, 0, 19)
+		features.SkipLocked = features.atLeast(8, 0, 1)
+	}
+	return features, nil
+}
+
+// Server is the adapter for a MySQL or MariaDB server of known version.
+// Build it with NewServer or Detect, or use AutoAdapter with godb.Open.
+type Server struct {
+	MySQL
+	features Features
+}
+
+// NewServer returns the adapter for the server of the given version, as
+// given by SELECT VERSION().
+func NewServer(version string) (Server, error) {
+	features, err := ParseVersion(version)
+	if err != nil {
+		return Server{}, err
+	}
+	return Server{features: features}, nil
+}
+
+// Detect queries the version of the server and returns its adapter.
+func Detect(db *sql.DB) (Server, error) {
+	var version string
+	if err := db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return Server{}, err
+	}
+	return NewServer(version)
+}
+
+// AutoDetect is an adapter replaced by the one of the server when used with
+// godb.Open.
+type AutoDetect struct {
+	MySQL
+}
+
+// AutoAdapter detects the MySQL or MariaDB server when used with godb.Open.
+var AutoAdapter = AutoDetect{}
+
+func (AutoDetect) DetectServer(db *sql.DB) (adapters.Adapter, error) {
+	server, err := Detect(db)
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// Features returns the description of what the server supports.
+func (s Server) Features() Features {
+	return s.features
+}
+
+func (s Server) Capabilities() adapters.Capabilities {
+	capabilities := s.MySQL.Capabilities()
+	if s.features.Returning {
+		capabilities.Returning = adapters.ReturningInsertDelete
+	}
+	if s.features.RowAlias {
+		capabilities.Upsert = adapters.UpsertOnDuplicateKeyRowAlias
+	}
+	if s.features.SkipLocked {
+		capabilities.Locking = adapters.LockingSkipLocked
+	}
+	capabilities.CTE = s.features.CTE
+	capabilities.WindowFunctions = s.features.WindowFunctions
+	return capabilities
+}
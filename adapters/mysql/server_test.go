@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseVersion(t *testing.T) {
+	Convey("Given a MySQL 8 version", t, func() {
+		features, err := ParseVersion("8.0.32-0ubuntu0.22.04.2")
+		So(err, ShouldBeNil)
+		Convey("ParseVersion unlocks the MySQL 8 features", func() {
+			So(features.MariaDB, ShouldBeFalse)
+			So(features.Major, ShouldEqual, 8)
+			So(features.Minor, ShouldEqual, 0)
+			So(features.Patch, ShouldEqual, 32)
+			So(features.CTE, ShouldBeTrue)
+			So(features.WindowFunctions, ShouldBeTrue)
+			So(features.RowAlias, ShouldBeTrue)
+			So(features.SkipLocked, ShouldBeTrue)
+			So(features.Returning, ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a MySQL 5.7 version", t, func() {
+		features, err := ParseVersion("5.7.42-log")
+		So(err, ShouldBeNil)
+		So(features, ShouldResemble, Features{Major: 5, Minor: 7, Patch: 42})
+	})
+
+	Convey("Given MariaDB versions", t, func() {
+		features, err := ParseVersion("5.5.5-10.6.12-MariaDB-1:10.6.12+maria~ubu2004")
+		So(err, ShouldBeNil)
+		Convey("ParseVersion ignores the replication prefix", func() {
+			So(features.MariaDB, ShouldBeTrue)
+			So(features.Major, ShouldEqual, 10)
+			So(features.Minor, ShouldEqual, 6)
+			So(features.Patch, ShouldEqual, 12)
+			So(features.Returning, ShouldBeTrue)
+			So(features.SkipLocked, ShouldBeTrue)
+			So(features.RowAlias, ShouldBeFalse)
+		})
+
+		features, err = ParseVersion("10.4.31-MariaDB")
+		So(err, ShouldBeNil)
+		So(features.WindowFunctions, ShouldBeTrue)
+		So(features.Returning, ShouldBeFalse)
+	})
+
+	Convey("Invalid versions are rejected", t, func() {
+		_, err := ParseVersion("")
+		So(err, ShouldNotBeNil)
+		_, err = ParseVersion("8.x.1")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	Convey("Given a MariaDB 10.5 server", t, func() {
+		server, err := NewServer("10.5.21-MariaDB")
+		So(err, ShouldBeNil)
+		Convey("RETURNING is supported by INSERT and DELETE statements", func() {
+			capabilities := server.Capabilities()
+			So(server.Features().MariaDB, ShouldBeTrue)
+			So(capabilities.Returning, ShouldEqual, adapters.ReturningInsertDelete)
+			So(capabilities.Upsert, ShouldEqual, adapters.UpsertOnDuplicateKey)
+			So(capabilities.Locking, ShouldEqual, adapters.LockingForUpdate)
+			So(capabilities.WindowFunctions, ShouldBeTrue)
+			So(capabilities.CTE, ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a MySQL 5.7 server", t, func() {
+		server, err := NewServer("5.7.42")
+		So(err, ShouldBeNil)
+		Convey("Window functions and CTE are not supported", func() {
+			capabilities := server.Capabilities()
+			So(capabilities.WindowFunctions, ShouldBeFalse)
+			So(capabilities.AggregateFilter, ShouldBeFalse)
+			So(capabilities.CTE, ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a MySQL 8.0.19 server", t, func() {
+		server, err := NewServer("8.0.19")
+		So(err, ShouldBeNil)
+		Convey("Upserts use a row alias and SKIP LOCKED is supported", func() {
+			capabilities := server.Capabilities()
+			So(capabilities.Returning, ShouldEqual, adapters.ReturningNone)
+			So(capabilities.Upsert, ShouldEqual, adapters.UpsertOnDuplicateKeyRowAlias)
+			So(capabilities.Locking, ShouldEqual, adapters.LockingSkipLocked)
+			So(capabilities.WindowFunctions, ShouldBeTrue)
+			So(capabilities.AggregateFilter, ShouldBeFalse)
+			So(capabilities.CTE, ShouldBeTrue)
+		})
+	})
+
+	Convey("Given the default adapter", t, func() {
+		Convey("Only the common features are supported", func() {
+			capabilities := Adapter.Capabilities()
+			So(capabilities.Returning, ShouldEqual, adapters.ReturningNone)
+			So(capabilities.Upsert, ShouldEqual, adapters.UpsertOnDuplicateKey)
+			So(capabilities.Locking, ShouldEqual, adapters.LockingForUpdate)
+			So(capabilities.WindowFunctions, ShouldBeFalse)
+			So(capabilities.CTE, ShouldBeFalse)
+		})
+	})
+}
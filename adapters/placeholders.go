@@ -0,0 +1,201 @@
+package adapters
+
+import (
+	"strings"
+)
+
+// Syntax describes how the string literals, quoted identifiers and comments
+// of a database are written, to find the placeholders outside of them (see
+// Capabilities). Its zero value is the standard syntax : 'literals',
+// "identifiers", -- line comments and /* block comments */, `identifiers`
+// are recognized too.
+type Syntax struct {
+	// BackslashEscapes is true if a backslash escapes the following
+	// character in the literals (MySQL)
+	BackslashEscapes bool
+	// EscapeStrings is true for the E'literals' having backslash escapes
+	// (PostgreSQL)
+	EscapeStrings bool
+	// DollarQuotes is true for the $tag$ literals $tag$ (PostgreSQL)
+	DollarQuotes bool
+	// HashComments is true if # starts a line comment (MySQL)
+	HashComments bool
+	// BracketIdentifiers is true for the [identifiers] (SQL Server)
+	BracketIdentifiers bool
+	// PlaceholderEscapes is true if a doubled placeholder (like ??) is an
+	// escaped one, unescaped when the placeholders are replaced. Use it only
+	// if the adapter replaces the placeholders, like with PostgreSQL where
+	// ?| is an operator.
+	PlaceholderEscapes bool
+}
+
+// PostgreSQLSyntax is the syntax of PostgreSQL.
+var PostgreSQLSyntax = Syntax{
+	EscapeStrings:      true,
+	DollarQuotes:       true,
+	PlaceholderEscapes: true,
+}
+
+// MySQLSyntax is the syntax of MySQL, without the NO_BACKSLASH_ESCAPES mode.
+var MySQLSyntax = Syntax{
+	BackslashEscapes: true,
+	HashComments:     true,
+}
+
+// SkipNonCode returns the position following the string literal, quoted
+// identifier or comment starting at the given position of the sql string.
+// It returns the given position if there is none.
+// An unterminated part extends to the end of the string.
+func (s Syntax) SkipNonCode(sql string, position int) int {
+	rest := sql[position:]
+	switch {
+	case strings.HasPrefix(rest, "'"):
+		return skipQuoted(sql, position, '\'', s.BackslashEscapes)
+	case s.EscapeStrings && (strings.HasPrefix(rest, "E'") || strings.HasPrefix(rest, "e'")) &&
+		(position == 0 || !isIdentifierByte(sql[position-1])):
+		return skipQuoted(sql, position+1, '\'', true)
+	case strings.HasPrefix(rest, "\""):
+		return skipQuoted(sql, position, '"', s.BackslashEscapes)
+	case strings.HasPrefix(rest, "`"):
+		return skipQuoted(sql, position, '`', false)
+	case s.BracketIdentifiers && strings.HasPrefix(rest, "["):
+		return skipQuoted(sql, position, ']', false)
+	case strings.HasPrefix(rest, "--"):
+		return skipLine(sql, position)
+	case s.HashComments && strings.HasPrefix(rest, "#"):
+		return skipLine(sql, position)
+	case strings.HasPrefix(rest, "/*"):
+		end := strings.Index(rest[2:], "*/")
+		if end == -1 {
+			return len(sql)
+		}
+		return position + 2 + end + 2
+	case s.DollarQuotes && strings.HasPrefix(rest, "$") && (position == 0 || !isIdentifierByte(sql[position-1])):
+		tag := dollarQuoteTag(rest)
+		if tag == "" {
+			return position
+		}
+		end := strings.Index(rest[len(tag):], tag)
+		if end == -1 {
+			return len(sql)
+		}
+		return position + len(tag) + end + len(tag)
+	default:
+		return position
+	}
+}
+
+// skipQuoted returns the position following the part starting at the given
+// position and ending with the quote. A doubled quote is an escaped one, as
+// well as a quote following a backslash if backslashEscapes is true.
+func skipQuoted(sql string, position int, quote byte, backslashEscapes bool) int {
+	for i := position + 1; i < len(sql); i++ {
+		if backslashEscapes && sql[i] == '\\' {
+			i++
+			continue
+		}
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sql)
+}
+
+// skipLine returns the position following the end of the line.
+func skipLine(sql string, position int) int {
+	end := strings.IndexByte(sql[position:], '\n')
+	if end == -1 {
+		return len(sql)
+	}
+	return position + end + 1
+}
+
+// dollarQuoteTag returns the tag ($$ or $name$) starting the given sql, or a
+// blank string. Positional parameters like $1 are not tags.
+func dollarQuoteTag(sql string) string {
+	for i := 1; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '$':
+			return sql[:i+1]
+		case isIdentifierByte(c) && (i > 1 || c < '0' || c > '9'):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// isIdentifierByte returns true if the byte could be a part of an unquoted
+// identifier.
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// FindPlaceholders returns the positions of the placeholders in the sql
+// string, ignoring the string literals, quoted identifiers and comments (see
+// SkipNonCode), and the escaped placeholders if the syntax has some.
+func (s Syntax) FindPlaceholders(sql string, placeholder string) []int {
+	var positions []int
+	s.scanPlaceholders(sql, placeholder, func(position int, escaped bool) {
+		if !escaped {
+			positions = append(positions, position)
+		}
+	})
+	return positions
+}
+
+// ReplacePlaceholders replaces the placeholders found by FindPlaceholders
+// with the string returned by replacement for the placeholder index
+// (starting at 0), and unescapes the escaped placeholders.
+func (s Syntax) ReplacePlaceholders(sql string, placeholder string, replacement func(index int) string) string {
+	var builder strings.Builder
+	builder.Grow(len(sql) + 16)
+	index := 0
+	previousEnd := 0
+	s.scanPlaceholders(sql, placeholder, func(position int, escaped bool) {
+		builder.WriteString(sql[previousEnd:position])
+		if escaped {
+			builder.WriteString(placeholder)
+			previousEnd = position + 2*len(placeholder)
+			return
+		}
+		builder.WriteString(replacement(index))
+		index++
+		previousEnd = position + len(placeholder)
+	})
+	builder.WriteString(sql[previousEnd:])
+	return builder.String()
+}
+
+// scanPlaceholders calls f for each placeholder, or escaped placeholder, of
+// the sql string.
+func (s Syntax) scanPlaceholders(sql string, placeholder string, f func(position int, escaped bool)) {
+	if placeholder == "" {
+		return
+	}
+
+	for i := 0; i < len(sql); {
+		if next := s.SkipNonCode(sql, i); next != i {
+			i = next
+			continue
+		}
+		if !strings.HasPrefix(sql[i:], placeholder) {
+			i++
+			continue
+		}
+		if s.PlaceholderEscapes && strings.HasPrefix(sql[i+len(placeholder):], placeholder) {
+			f(i, true)
+			i += 2 * len(placeholder)
+			continue
+		}
+		f(i, false)
+		i += len(placeholder)
+	}
+}
@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSkipNonCode(t *testing.T) {
+	Convey("SkipNonCode skips literals, quoted identifiers and comments", t, func() {
+		So(Syntax{}.SkipNonCode("a 'it''s ?' b", 2), ShouldEqual, 11)
+		So(Syntax{}.SkipNonCode(`a "col?" b`, 2), ShouldEqual, 8)
+		So(Syntax{}.SkipNonCode("a `col?` b", 2), ShouldEqual, 8)
+		So(Syntax{}.SkipNonCode("a -- why ?\nb", 2), ShouldEqual, 11)
+		So(Syntax{}.SkipNonCode("a /* why ? */ b", 2), ShouldEqual, 13)
+		So(Syntax{}.SkipNonCode("a 'unterminated", 2), ShouldEqual, 15)
+		So(PostgreSQLSyntax.SkipNonCode("a $tag$ why ? $tag$ b", 2), ShouldEqual, 19)
+	})
+
+	Convey("SkipNonCode returns the given position for code", t, func() {
+		So(Syntax{}.SkipNonCode("a = ?", 2), ShouldEqual, 2)
+		So(PostgreSQLSyntax.SkipNonCode("a = $1", 4), ShouldEqual, 4)
+		So(PostgreSQLSyntax.SkipNonCode("a$b$c", 1), ShouldEqual, 1)
+		So(Syntax{}.SkipNonCode("a $tag$ b", 2), ShouldEqual, 2)
+		So(Syntax{}.SkipNonCode("a # b", 2), ShouldEqual, 2)
+		So(Syntax{}.SkipNonCode("a [b] c", 2), ShouldEqual, 2)
+	})
+
+	Convey("SkipNonCode handles the syntax of the database", t, func() {
+		So(Syntax{}.SkipNonCode(`a 'C:\' b`, 2), ShouldEqual, 7)
+		So(MySQLSyntax.SkipNonCode(`a 'it\'s ?' b`, 2), ShouldEqual, 11)
+		So(MySQLSyntax.SkipNonCode(`a "it\"s ?" b`, 2), ShouldEqual, 11)
+		So(MySQLSyntax.SkipNonCode("a # why ?\nb", 2), ShouldEqual, 10)
+		So(PostgreSQLSyntax.SkipNonCode(`a E'it\'s ?' b`, 2), ShouldEqual, 12)
+		So(PostgreSQLSyntax.SkipNonCode(`aE'x'`, 1), ShouldEqual, 1)
+		So(Syntax{BracketIdentifiers: true}.SkipNonCode("a [col?] b", 2), ShouldEqual, 8)
+		So(Syntax{BracketIdentifiers: true}.SkipNonCode("a [a]]?] b", 2), ShouldEqual, 8)
+	})
+}
+
+func TestFindPlaceholders(t *testing.T) {
+	Convey("FindPlaceholders ignores literals, comments and escaped placeholders", t, func() {
+		sql := "a = ? AND b = '?' AND c ?? 'key' /* ? */ AND d = ? -- ?"
+		So(PostgreSQLSyntax.FindPlaceholders(sql, "?"), ShouldResemble, []int{4, 49})
+	})
+
+	Convey("FindPlaceholders finds doubled placeholders without escapes", t, func() {
+		So(MySQLSyntax.FindPlaceholders(`a = ?? AND b = 'it\'s ?' # ?`, "?"), ShouldResemble, []int{4, 5})
+	})
+}
+
+func TestReplacePlaceholders(t *testing.T) {
+	Convey("ReplacePlaceholders replaces placeholders and unescapes doubled ones", t, func() {
+		sql := "a = ? AND b = '?' AND c ??| array['k'] AND d = ?"
+		replaced := PostgreSQLSyntax.ReplacePlaceholders(sql, "?", func(index int) string {
+			return "$" + strconv.Itoa(index+1)
+		})
+		So(replaced, ShouldEqual, "a = $1 AND b = '?' AND c ?| array['k'] AND d = $2")
+	})
+}
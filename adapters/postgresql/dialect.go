@@ -0,0 +1,14 @@
+package postgresql
+
+import (
+	"github.com/samonzeweb/godb/adapters/generic"
+)
+
+// Dialect describes PostgreSQL for the generic adapter.
+var Dialect = generic.Dialect{
+	DriverName:   "postgres",
+	QuoteStart:   "\"",
+	QuoteEnd:     "\"",
+	Capabilities: Adapter.Capabilities(),
+	ParseError:   Adapter.ParseError,
+}
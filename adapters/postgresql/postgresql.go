@@ -2,8 +2,8 @@ package postgresql
 
 import (
 	"bytes"
-	"strconv"
-	"strings"
+	"database/sql/driver"
+	"errors"
 
 	pq "github.com/lib/pq"
 	"github.com/samonzeweb/godb/adapters"
@@ -22,22 +22,8 @@ func (PostgreSQL) Quote(identifier string) string {
 	return "\"" + identifier + "\""
 }
 
-func (PostgreSQL) ReplacePlaceholders(originalPlaceholder string, sql string) string {
-	sqlBuffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
-	count := 1
-	for {
-		pp := strings.Index(sql, originalPlaceholder)
-		if pp == -1 {
-			break
-		}
-		sqlBuffer.WriteString(sql[:pp])
-		sqlBuffer.WriteString("$")
-		sqlBuffer.WriteString(strconv.Itoa(count))
-		count++
-		sql = sql[pp+1:]
-	}
-	sqlBuffer.WriteString(sql)
-	return sqlBuffer.String()
+func (p PostgreSQL) ReplacePlaceholders(originalPlaceholder string, sql string) string {
+	return p.Capabilities().ReplacePlaceholders(originalPlaceholder, sql)
 }
 
 func (p PostgreSQL) ReturningBuild(columns []string) string {
@@ -64,21 +50,71 @@ func (p PostgreSQL) GetReturningPosition() adapters.ReturningPosition {
 	return adapters.ReturningPostgreSQL
 }
 
+func (PostgreSQL) Capabilities() adapters.Capabilities {
+	return adapters.Capabilities{
+		Placeholders:       adapters.PlaceholderDollar,
+		Returning:          adapters.ReturningAll,
+		ReturningPosition:  adapters.ReturningPostgreSQL,
+		MultiTable:         adapters.MultiTablePostgreSQL,
+		Upsert:             adapters.UpsertOnConflict,
+		MaxParameters:      65535,
+		Savepoints:         adapters.SavepointsStandard,
+		RowValueComparison: true,
+		BooleanLiterals:    adapters.BooleanTrueFalse,
+		ILike:              true,
+		Locking:            adapters.LockingSkipLocked,
+		NullsOrder:         true,
+		GroupingSets:       true,
+		WindowFunctions:    true,
+		AggregateFilter:    true,
+		CTE:                true,
+		Syntax:             adapters.PostgreSQLSyntax,
+	}
+}
+
 func (p PostgreSQL) ParseError(err error) error {
 	if err == nil {
 		return nil
 	}
 
 	if e, ok := err.(*pq.Error); ok {
-		switch e.Code {
-		case "23505":
-			return dberror.UniqueConstraint{Message: e.Error(), Field: dberror.ExtractStr(e.Message, "constraint \"", "\""), Err: e}
-		case "23503":
-			return dberror.ForeignKeyConstraint{Message: e.Error(), Field: dberror.ExtractStr(e.Message, "constraint \"", "\""), Err: e}
-		case "23514":
-			return dberror.CheckConstraint{Message: e.Error(), Field: dberror.ExtractStr(e.Message, "constraint \"", "\""), Err: e}
+		constraint := e.Constraint
+		if constraint == "" {
+			constraint = dberror.ExtractStr(e.Message, "constraint \"", "\"")
+		}
+		switch {
+		case e.Code == "23505":
+			return dberror.UniqueConstraint{Message: e.Error(), Field: constraint, Table: e.Table, Column: e.Column, Constraint: constraint, Err: e}
+		case e.Code == "23503":
+			return dberror.ForeignKeyConstraint{Message: e.Error(), Field: constraint, Table: e.Table, Column: e.Column, Constraint: constraint, Err: e}
+		case e.Code == "23514":
+			return dberror.CheckConstraint{Message: e.Error(), Field: constraint, Table: e.Table, Column: e.Column, Constraint: constraint, Err: e}
+		case e.Code == "23502":
+			column := e.Column
+			if column == "" {
+				column = dberror.ExtractStr(e.Message, "column \"", "\"")
+			}
+			return dberror.NotNullConstraint{Message: e.Error(), Field: column, Table: e.Table, Column: column, Err: e}
+		case e.Code == "23P01":
+			return dberror.ExclusionConstraint{Message: e.Error(), Table: e.Table, Constraint: constraint, Err: e}
+		case e.Code == "40P01":
+			return dberror.Deadlock{Message: e.Error(), Err: e}
+		case e.Code == "40001":
+			return dberror.SerializationFailure{Message: e.Error(), Err: e}
+		case e.Code == "55P03":
+			return dberror.LockTimeout{Message: e.Error(), Err: e}
+		case e.Code == "22001":
+			return dberror.StringTruncation{Message: e.Error(), Table: e.Table, Column: e.Column, Err: e}
+		case e.Code == "22003":
+			return dberror.NumericOverflow{Message: e.Error(), Table: e.Table, Column: e.Column, Err: e}
+		case e.Code.Class() == "08" || e.Code == "57P01":
+			return dberror.ConnectionLost{Message: e.Error(), Err: e}
 		}
 	}
 
+	if errors.Is(err, driver.ErrBadConn) {
+		return dberror.ConnectionLost{Message: err.Error(), Err: err}
+	}
+
 	return err
 }
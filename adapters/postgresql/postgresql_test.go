@@ -1,8 +1,13 @@
 package postgresql
 
 import (
+	"database/sql/driver"
+	"errors"
 	"testing"
 
+	pq "github.com/lib/pq"
+	"github.com/samonzeweb/godb/dberror"
+
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -38,3 +43,68 @@ func TestFormatForNewValues(t *testing.T) {
 		})
 	})
 }
+
+func TestReplacePlaceholdersIgnoresLiterals(t *testing.T) {
+	Convey("Given a SQL string containing literals and JSON operators", t, func() {
+		sql := "SELECT id FROM dummies WHERE data ??| array['a?'] AND id > ?"
+		Convey("ReplacePlaceholders only changes the placeholders", func() {
+			sqlWithNewPlaceholders := Adapter.ReplacePlaceholders("?", sql)
+			So(sqlWithNewPlaceholders, ShouldEqual, "SELECT id FROM dummies WHERE data ?| array['a?'] AND id > $1")
+		})
+
+		Convey("ReplacePlaceholders ignores the escape strings", func() {
+			sqlWithNewPlaceholders := Adapter.ReplacePlaceholders("?", `SELECT id FROM dummies WHERE a = E'it\'s ?' AND id > ?`)
+			So(sqlWithNewPlaceholders, ShouldEqual, `SELECT id FROM dummies WHERE a = E'it\'s ?' AND id > $1`)
+		})
+	})
+}
+
+func TestParseError(t *testing.T) {
+	Convey("Given PostgreSQL errors", t, func() {
+		unique := &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "books_title_key"`, Table: "books", Constraint: "books_title_key"}
+		uniqueWithoutDetails := &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "books_title_key"`}
+		foreignKey := &pq.Error{Code: "23503", Message: `insert or update on table "inventories" violates foreign key constraint "fk_book"`, Table: "inventories", Constraint: "fk_book"}
+		check := &pq.Error{Code: "23514", Message: `new row for relation "books" violates check constraint "positive_pages"`, Table: "books", Constraint: "positive_pages"}
+		notNull := &pq.Error{Code: "23502", Message: `null value in column "title" of relation "books" violates not-null constraint`, Table: "books", Column: "title"}
+		exclusion := &pq.Error{Code: "23P01", Message: `conflicting key value violates exclusion constraint "no_overlap"`, Table: "bookings", Constraint: "no_overlap"}
+		deadlock := &pq.Error{Code: "40P01", Message: "deadlock detected"}
+		serialization := &pq.Error{Code: "40001", Message: "could not serialize access due to concurrent update"}
+		lockTimeout := &pq.Error{Code: "55P03", Message: "canceling statement due to lock timeout"}
+		connectionLost := &pq.Error{Code: "08006", Message: "connection failure"}
+		truncation := &pq.Error{Code: "22001", Message: "value too long for type character varying(10)"}
+		overflow := &pq.Error{Code: "22003", Message: "integer out of range"}
+		other := &pq.Error{Code: "42P01", Message: `relation "unknown" does not exist`}
+
+		cases := []struct {
+			name     string
+			err      error
+			expected error
+		}{
+			{"unique violation", unique, dberror.UniqueConstraint{Message: unique.Error(), Field: "books_title_key", Table: "books", Constraint: "books_title_key", Err: unique}},
+			{"unique violation without details", uniqueWithoutDetails, dberror.UniqueConstraint{Message: uniqueWithoutDetails.Error(), Field: "books_title_key", Constraint: "books_title_key", Err: uniqueWithoutDetails}},
+			{"foreign key violation", foreignKey, dberror.ForeignKeyConstraint{Message: foreignKey.Error(), Field: "fk_book", Table: "inventories", Constraint: "fk_book", Err: foreignKey}},
+			{"check violation", check, dberror.CheckConstraint{Message: check.Error(), Field: "positive_pages", Table: "books", Constraint: "positive_pages", Err: check}},
+			{"not null violation", notNull, dberror.NotNullConstraint{Message: notNull.Error(), Field: "title", Table: "books", Column: "title", Err: notNull}},
+			{"exclusion violation", exclusion, dberror.ExclusionConstraint{Message: exclusion.Error(), Table: "bookings", Constraint: "no_overlap", Err: exclusion}},
+			{"deadlock", deadlock, dberror.Deadlock{Message: deadlock.Error(), Err: deadlock}},
+			{"serialization failure", serialization, dberror.SerializationFailure{Message: serialization.Error(), Err: serialization}},
+			{"lock timeout", lockTimeout, dberror.LockTimeout{Message: lockTimeout.Error(), Err: lockTimeout}},
+			{"connection failure", connectionLost, dberror.ConnectionLost{Message: connectionLost.Error(), Err: connectionLost}},
+			{"bad connection", driver.ErrBadConn, dberror.ConnectionLost{Message: driver.ErrBadConn.Error(), Err: driver.ErrBadConn}},
+			{"string truncation", truncation, dberror.StringTruncation{Message: truncation.Error(), Err: truncation}},
+			{"numeric overflow", overflow, dberror.NumericOverflow{Message: overflow.Error(), Err: overflow}},
+			{"other error", other, other},
+		}
+		for _, c := range cases {
+			Convey("ParseError classifies a "+c.name, func() {
+				parsedErr := Adapter.ParseError(c.err)
+				So(parsedErr, ShouldResemble, c.expected)
+				So(errors.Is(parsedErr, c.err), ShouldBeTrue)
+			})
+		}
+
+		Convey("ParseError returns nil without error", func() {
+			So(Adapter.ParseError(nil), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package puresqlite
+
+import (
+	"github.com/samonzeweb/godb/adapters/generic"
+)
+
+// Dialect describes SQLite with the pure Go driver for the generic adapter.
+var Dialect = generic.Dialect{
+	DriverName:   "sqlite",
+	QuoteStart:   "\"",
+	QuoteEnd:     "\"",
+	Capabilities: Adapter.Capabilities(),
+	ParseError:   Adapter.ParseError,
+}
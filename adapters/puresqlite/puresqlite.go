@@ -0,0 +1,84 @@
+// Package puresqlite contains the adapter for SQLite using the pure Go
+// driver modernc.org/sqlite, without cgo.
+package puresqlite
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dberror"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type PureSQLite struct{}
+
+var Adapter = PureSQLite{}
+
+func (PureSQLite) DriverName() string {
+	return "sqlite"
+}
+
+func (PureSQLite) Quote(identifier string) string {
+	return "\"" + identifier + "\""
+}
+
+// The driver bundles SQLite 3.46, with the RETURNING clause and the raised
+// limit of parameters.
+func (PureSQLite) Capabilities() adapters.Capabilities {
+	return adapters.Capabilities{
+		Returning:          adapters.ReturningAll,
+		Upsert:             adapters.UpsertOnConflict,
+		MaxParameters:      32766,
+		Savepoints:         adapters.SavepointsStandard,
+		RowValueComparison: true,
+		BooleanLiterals:    adapters.BooleanTrueFalse,
+		NullsOrder:         true,
+		WindowFunctions:    true,
+		AggregateFilter:    true,
+		CTE:                true,
+	}
+}
+
+func (PureSQLite) ParseError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(*sqlite.Error)
+	if !ok {
+		return err
+	}
+	// The messages are like "constraint failed: UNIQUE constraint failed:
+	// books.title (2067)"
+	message := strings.TrimSuffix(e.Error(), " ("+strconv.Itoa(e.Code())+")")
+	switch e.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		table, column := failedColumn(message)
+		return dberror.UniqueConstraint{Message: e.Error(), Field: column, Table: table, Column: column, Err: e}
+	case sqlite3.SQLITE_CONSTRAINT_CHECK:
+		constraint := dberror.ExtractStr(message, " constraint failed: ", "\n")
+		return dberror.CheckConstraint{Message: e.Error(), Field: constraint, Constraint: constraint, Err: e}
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return dberror.ForeignKeyConstraint{Message: e.Error(), Err: e}
+	case sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+		table, column := failedColumn(message)
+		return dberror.NotNullConstraint{Message: e.Error(), Field: column, Table: table, Column: column, Err: e}
+	}
+	switch e.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return dberror.LockTimeout{Message: e.Error(), Err: e}
+	case sqlite3.SQLITE_CANTOPEN:
+		return dberror.ConnectionLost{Message: e.Error(), Err: e}
+	}
+	return err
+}
+
+// failedColumn returns the table and the column named by a message like
+// "UNIQUE constraint failed: books.title". Only the first column of a
+// multi-column constraint is returned.
+func failedColumn(message string) (string, string) {
+	return dberror.SplitName(dberror.ExtractStr(message, " constraint failed: ", ", "))
+}
@@ -0,0 +1,94 @@
+package puresqlite
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/dberror"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseError(t *testing.T) {
+	Convey("Given a SQLite database with constraints", t, func() {
+		db, err := sql.Open(Adapter.DriverName(), ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+
+		_, err = db.Exec(`
+			PRAGMA foreign_keys = ON;
+			CREATE TABLE authors (id INTEGER PRIMARY KEY);
+			CREATE TABLE books (
+				id        INTEGER PRIMARY KEY,
+				title     TEXT NOT NULL UNIQUE,
+				pages     INTEGER CONSTRAINT positive_pages CHECK (pages > 0),
+				author_id INTEGER REFERENCES authors(id));
+			INSERT INTO authors (id) VALUES (1);
+			INSERT INTO books (id, title, pages, author_id) VALUES (1, 'Foundation', 255, 1);`)
+		So(err, ShouldBeNil)
+
+		cases := []struct {
+			name      string
+			statement string
+			expected  error
+		}{
+			{"duplicate value", "INSERT INTO books (title) VALUES ('Foundation')", dberror.UniqueConstraint{Field: "title", Table: "books", Column: "title"}},
+			{"duplicate primary key", "INSERT INTO books (id, title) VALUES (1, 'Dune')", dberror.UniqueConstraint{Field: "id", Table: "books", Column: "id"}},
+			{"failed check", "INSERT INTO books (title, pages) VALUES ('Dune', 0)", dberror.CheckConstraint{Field: "positive_pages", Constraint: "positive_pages"}},
+			{"missing reference", "INSERT INTO books (title, author_id) VALUES ('Dune', 2)", dberror.ForeignKeyConstraint{}},
+			{"null value", "INSERT INTO books (pages) VALUES (100)", dberror.NotNullConstraint{Field: "title", Table: "books", Column: "title"}},
+		}
+		for _, c := range cases {
+			Convey("ParseError classifies a "+c.name, func() {
+				_, err := db.Exec(c.statement)
+				So(err, ShouldNotBeNil)
+				parsedErr := Adapter.ParseError(err)
+				So(errors.Is(parsedErr, err), ShouldBeTrue)
+				So(withoutDriverError(parsedErr), ShouldResemble, c.expected)
+			})
+		}
+
+		Convey("Other errors are returned unchanged", func() {
+			_, err := db.Exec("SELECT * FROM unknown")
+			So(Adapter.ParseError(err), ShouldResemble, err)
+		})
+	})
+
+	Convey("ParseError classifies a database which can't be opened", t, func() {
+		db, err := sql.Open(Adapter.DriverName(), "file:/nonexistent/dir/godb.db?mode=ro")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		err = db.Ping()
+		So(err, ShouldNotBeNil)
+		var connectionLost dberror.ConnectionLost
+		So(errors.As(Adapter.ParseError(err), &connectionLost), ShouldBeTrue)
+	})
+
+	Convey("Errors of other drivers are returned unchanged", t, func() {
+		err := errors.New("dummy")
+		So(Adapter.ParseError(err), ShouldEqual, err)
+		So(Adapter.ParseError(nil), ShouldBeNil)
+	})
+}
+
+// withoutDriverError removes the message and the driver error, they're
+// specific to the SQLite version.
+func withoutDriverError(err error) error {
+	switch e := err.(type) {
+	case dberror.UniqueConstraint:
+		e.Message, e.Err = "", nil
+		return e
+	case dberror.CheckConstraint:
+		e.Message, e.Err = "", nil
+		return e
+	case dberror.ForeignKeyConstraint:
+		e.Message, e.Err = "", nil
+		return e
+	case dberror.NotNullConstraint:
+		e.Message, e.Err = "", nil
+		return e
+	}
+	return err
+}
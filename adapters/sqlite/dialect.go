@@ -0,0 +1,14 @@
+package sqlite
+
+import (
+	"github.com/samonzeweb/godb/adapters/generic"
+)
+
+// Dialect describes SQLite for the generic adapter.
+var Dialect = generic.Dialect{
+	DriverName:   "sqlite3",
+	QuoteStart:   "\"",
+	QuoteEnd:     "\"",
+	Capabilities: Adapter.Capabilities(),
+	ParseError:   Adapter.ParseError,
+}
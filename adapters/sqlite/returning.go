@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// SQLiteReturning is the SQLite adapter using the RETURNING clause, it needs
+// SQLite 3.35 or later.
+type SQLiteReturning struct {
+	SQLite
+}
+
+// ReturningAdapter is the SQLite adapter using the RETURNING clause, use it
+// instead of Adapter with SQLite 3.35 or later.
+//
+// godb.Open checks the version of the SQLite library used by the driver, and
+// fails with older versions. With godb.Wrap, check it with DetectServer.
+var ReturningAdapter = SQLiteReturning{}
+
+func (s SQLiteReturning) Capabilities() adapters.Capabilities {
+	capabilities := s.SQLite.Capabilities()
+	capabilities.Returning = adapters.ReturningAll
+	capabilities.MaxParameters = 32766
+	return capabilities
+}
+
+// DetectServer returns the adapter if the SQLite library supports the
+// RETURNING clause, and an error otherwise.
+func (s SQLiteReturning) DetectServer(db *sql.DB) (adapters.Adapter, error) {
+	var version string
+	if err := db.QueryRow("SELECT sqlite_version()").Scan(&version); err != nil {
+		return nil, err
+	}
+	supported, err := supportsReturning(version)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, fmt.Errorf("the RETURNING clause needs SQLite 3.35 or later, the driver uses SQLite %s, use sqlite.Adapter instead", version)
+	}
+	return s, nil
+}
+
+// supportsReturning returns true if the given SQLite version, like "3.35.5",
+// supports the RETURNING clause.
+func supportsReturning(version string) (bool, error) {
+	numbers := strings.Split(version, ".")
+	if len(numbers) < 2 {
+		return false, fmt.Errorf("invalid SQLite version : %q", version)
+	}
+	major, err := strconv.Atoi(numbers[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid SQLite version : %q", version)
+	}
+	minor, err := strconv.Atoi(numbers[1])
+	if err != nil {
+		return false, fmt.Errorf("invalid SQLite version : %q", version)
+	}
+	return major > 3 || (major == 3 && minor >= 35), nil
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSupportsReturning(t *testing.T) {
+	Convey("supportsReturning checks the SQLite version", t, func() {
+		cases := map[string]bool{
+			"3.31.1": false,
+			"3.34":   false,
+			"3.35.0": true,
+			"3.46.0": true,
+			"4.0.0":  true,
+		}
+		for version, expected := range cases {
+			supported, err := supportsReturning(version)
+			So(err, ShouldBeNil)
+			So(supported, ShouldEqual, expected)
+		}
+	})
+
+	Convey("supportsReturning rejects invalid versions", t, func() {
+		_, err := supportsReturning("3")
+		So(err, ShouldNotBeNil)
+		_, err = supportsReturning("x.35")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestDetectServer(t *testing.T) {
+	Convey("Given a SQLite database", t, func() {
+		db, err := sql.Open(ReturningAdapter.DriverName(), ":memory:")
+		So(err, ShouldBeNil)
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+
+		var version string
+		err = db.QueryRow("SELECT sqlite_version()").Scan(&version)
+		So(err, ShouldBeNil)
+		supported, err := supportsReturning(version)
+		So(err, ShouldBeNil)
+
+		adapter, err := ReturningAdapter.DetectServer(db)
+		if supported {
+			Convey("DetectServer accepts a version supporting RETURNING", func() {
+				So(err, ShouldBeNil)
+				So(adapter, ShouldResemble, ReturningAdapter)
+
+				_, err = db.Exec("CREATE TABLE books (id INTEGER PRIMARY KEY, title TEXT)")
+				So(err, ShouldBeNil)
+				var id int
+				err = db.QueryRow("INSERT INTO books (title) VALUES ('Dune') RETURNING id").Scan(&id)
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, 1)
+			})
+		} else {
+			Convey("DetectServer rejects a version without RETURNING", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "SQLite 3.35")
+				So(err.Error(), ShouldContainSubstring, version)
+				So(adapter, ShouldBeNil)
+			})
+		}
+	})
+}
@@ -1,8 +1,7 @@
 package sqlite
 
 import (
-	"strings"
-
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/dberror"
 
 	sqlite3 "github.com/mattn/go-sqlite3"
@@ -20,18 +19,54 @@ func (SQLite) Quote(identifier string) string {
 	return "\"" + identifier + "\""
 }
 
+func (SQLite) Capabilities() adapters.Capabilities {
+	return adapters.Capabilities{
+		Upsert:             adapters.UpsertOnConflict,
+		MaxParameters:      999, // SQLite before 3.32
+		Savepoints:         adapters.SavepointsStandard,
+		RowValueComparison: true,
+		BooleanLiterals:    adapters.BooleanTrueFalse,
+		NullsOrder:         true,
+		WindowFunctions:    true, // SQLite 3.25
+		AggregateFilter:    true, // SQLite 3.30
+		CTE:                true,
+	}
+}
+
 func (SQLite) ParseError(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	e, _ := err.(sqlite3.Error)
+	e, ok := err.(sqlite3.Error)
+	if !ok {
+		return err
+	}
 	switch e.ExtendedCode {
-	case sqlite3.ErrConstraintUnique:
-		return dberror.UniqueConstraint{Message: e.Error(), Field: strings.Split(strings.Split(e.Error(), "failed: ")[1], ".")[1], Err: e}
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		table, column := failedColumn(e.Error())
+		return dberror.UniqueConstraint{Message: e.Error(), Field: column, Table: table, Column: column, Err: e}
 	case sqlite3.ErrConstraintCheck:
-		return dberror.CheckConstraint{Message: e.Error(), Field: strings.Split(strings.Split(e.Error(), "failed: ")[1], ".")[1], Err: e}
-	default:
-		return err
+		constraint := dberror.ExtractStr(e.Error(), " constraint failed: ", "\n")
+		return dberror.CheckConstraint{Message: e.Error(), Field: constraint, Constraint: constraint, Err: e}
+	case sqlite3.ErrConstraintForeignKey:
+		return dberror.ForeignKeyConstraint{Message: e.Error(), Err: e}
+	case sqlite3.ErrConstraintNotNull:
+		table, column := failedColumn(e.Error())
+		return dberror.NotNullConstraint{Message: e.Error(), Field: column, Table: table, Column: column, Err: e}
+	}
+	switch e.Code {
+	case sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return dberror.LockTimeout{Message: e.Error(), Err: e}
+	case sqlite3.ErrCantOpen:
+		return dberror.ConnectionLost{Message: e.Error(), Err: e}
 	}
+	return err
+}
+
+// failedColumn returns the table and the column named by a message like
+// "UNIQUE constraint failed: books.title". Only the first column of a
+// multi-column constraint is returned.
+func failedColumn(message string) (string, string) {
+	return dberror.SplitName(dberror.ExtractStr(message, " constraint failed: ", ", "))
 }
@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"errors"
+	"strings"
+)
+
+// BuildOnConflictUpsert returns an ON CONFLICT clause, as used by PostgreSQL
+// and SQLite. The updated columns take the values of the EXCLUDED row.
+func BuildOnConflictUpsert(conflictColumns []string, updateColumns []string) (string, error) {
+	clause := "ON CONFLICT"
+	if len(conflictColumns) > 0 {
+		clause += " (" + strings.Join(conflictColumns, ", ") + ")"
+	}
+	if len(updateColumns) == 0 {
+		return clause + " DO NOTHING", nil
+	}
+	if len(conflictColumns) == 0 {
+		return "", errors.New("the conflicting columns are required to update the existing rows")
+	}
+
+	return clause + " DO UPDATE SET " + assignments(updateColumns, func(column string) string {
+		return "EXCLUDED." + column
+	}), nil
+}
+
+// BuildOnDuplicateKeyUpsert returns an ON DUPLICATE KEY UPDATE clause, as
+// used by MySQL and MariaDB. The conflicting columns are only used when
+// there is no column to update, one of them is then set to itself.
+// The updated columns take the inserted values with VALUES(column), or with
+// the given row alias if it's not empty (MySQL 8.0.19 and later).
+func BuildOnDuplicateKeyUpsert(conflictColumns []string, updateColumns []string, rowAlias string) (string, error) {
+	if len(updateColumns) == 0 {
+		if len(conflictColumns) == 0 {
+			return "", errors.New("a conflicting column is required to leave the existing rows unchanged")
+		}
+		return "ON DUPLICATE KEY UPDATE " + conflictColumns[0] + " = " + conflictColumns[0], nil
+	}
+
+	if rowAlias == "" {
+		return "ON DUPLICATE KEY UPDATE " + assignments(updateColumns, func(column string) string {
+			return "VALUES(" + column + ")"
+		}), nil
+	}
+	return "AS " + rowAlias + " ON DUPLICATE KEY UPDATE " + assignments(updateColumns, func(column string) string {
+		return rowAlias + "." + column
+	}), nil
+}
+
+// assignments returns the 'column = value' list of an upsert clause.
+func assignments(columns []string, value func(column string) string) string {
+	var builder strings.Builder
+	for i, column := range columns {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(column)
+		builder.WriteString(" = ")
+		builder.WriteString(value(column))
+	}
+	return builder.String()
+}
@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildOnConflictUpsert(t *testing.T) {
+	Convey("BuildOnConflictUpsert updates the columns with the excluded values", t, func() {
+		clause, err := BuildOnConflictUpsert([]string{`"id"`}, []string{`"a"`, `"b"`})
+		So(err, ShouldBeNil)
+		So(clause, ShouldEqual, `ON CONFLICT ("id") DO UPDATE SET "a" = EXCLUDED."a", "b" = EXCLUDED."b"`)
+	})
+
+	Convey("BuildOnConflictUpsert does nothing without columns to update", t, func() {
+		clause, err := BuildOnConflictUpsert(nil, nil)
+		So(err, ShouldBeNil)
+		So(clause, ShouldEqual, `ON CONFLICT DO NOTHING`)
+		_, err = BuildOnConflictUpsert(nil, []string{`"a"`})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestBuildOnDuplicateKeyUpsert(t *testing.T) {
+	Convey("BuildOnDuplicateKeyUpsert uses a row alias if given", t, func() {
+		clause, err := BuildOnDuplicateKeyUpsert([]string{"`id`"}, []string{"`a`", "`b`"}, "new")
+		So(err, ShouldBeNil)
+		So(clause, ShouldEqual, "AS new ON DUPLICATE KEY UPDATE `a` = new.`a`, `b` = new.`b`")
+	})
+
+	Convey("BuildOnDuplicateKeyUpsert uses the VALUES function otherwise", t, func() {
+		clause, err := BuildOnDuplicateKeyUpsert([]string{"`id`"}, []string{"`a`"}, "")
+		So(err, ShouldBeNil)
+		So(clause, ShouldEqual, "ON DUPLICATE KEY UPDATE `a` = VALUES(`a`)")
+	})
+
+	Convey("BuildOnDuplicateKeyUpsert leaves existing rows unchanged without columns to update", t, func() {
+		clause, err := BuildOnDuplicateKeyUpsert([]string{"`id`"}, nil, "")
+		So(err, ShouldBeNil)
+		So(clause, ShouldEqual, "ON DUPLICATE KEY UPDATE `id` = `id`")
+		_, err = BuildOnDuplicateKeyUpsert(nil, nil, "")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,135 @@
+package godb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// UnsupportedError is returned when a statement uses a feature which is not
+// supported by the database (see adapters.Capabilities). It matches
+// errors.ErrUnsupported with errors.Is.
+type UnsupportedError struct {
+	Feature string
+}
+
+func (e *UnsupportedError) Error() string {
+	return e.Feature + " is not supported by the database"
+}
+
+// Is returns true for errors.ErrUnsupported.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == errors.ErrUnsupported
+}
+
+// TooManyParametersError is returned when a statement has more arguments than
+// the database accepts (see adapters.Capabilities.MaxParameters).
+type TooManyParametersError struct {
+	Count int
+	Max   int
+}
+
+func (e *TooManyParametersError) Error() string {
+	return fmt.Sprintf("the statement has %d arguments, the database accepts %d at most", e.Count, e.Max)
+}
+
+// Capabilities returns the description of what the database supports.
+func (db *DB) Capabilities() adapters.Capabilities {
+	return capabilitiesOf(db.adapter)
+}
+
+// ReturningBuilder returns the builder of the RETURNING clause (or similar)
+// of INSERT and DELETE statements, or UPDATE statements if update is true. It
+// returns nil if the clause is not supported.
+func (db *DB) ReturningBuilder(update bool) adapters.ReturningBuilder {
+	return returningBuilderOf(db.adapter, update)
+}
+
+// capabilitiesOf returns the capabilities of the adapter. For adapters not
+// implementing adapters.CapabilitiesDescriber, only the RETURNING clause is
+// supported if the adapter implements adapters.ReturningBuilder.
+func capabilitiesOf(adapter adapters.Adapter) adapters.Capabilities {
+	if describer, ok := adapter.(adapters.CapabilitiesDescriber); ok {
+		return describer.Capabilities()
+	}
+
+	capabilities := adapters.Capabilities{}
+	if returningBuilder, ok := adapter.(adapters.ReturningBuilder); ok {
+		capabilities.Returning = adapters.ReturningAll
+		capabilities.ReturningPosition = returningBuilder.GetReturningPosition()
+	}
+	return capabilities
+}
+
+// legacyAdapter returns the adapter if it doesn't implement
+// adapters.CapabilitiesDescriber, the deprecated optional interfaces it
+// implements are then used. It returns nil otherwise.
+func legacyAdapter(adapter adapters.Adapter) adapters.Adapter {
+	if _, ok := adapter.(adapters.CapabilitiesDescriber); ok {
+		return nil
+	}
+	return adapter
+}
+
+// checkArguments returns an error if there are more arguments than the
+// database accepts.
+func (db *DB) checkArguments(arguments []interface{}) error {
+	maxParameters := db.Capabilities().MaxParameters
+	if maxParameters > 0 && len(arguments) > maxParameters {
+		return &TooManyParametersError{Count: len(arguments), Max: maxParameters}
+	}
+	return nil
+}
+
+// returningBuilderOf returns the builder of the RETURNING clause (or similar)
+// of INSERT and DELETE statements, or UPDATE statements if update is true.
+// It returns nil if the clause is not supported. The clause is built according
+// to the capabilities, only the adapters not implementing
+// adapters.CapabilitiesDescriber use their own adapters.ReturningBuilder.
+func returningBuilderOf(adapter adapters.Adapter, update bool) adapters.ReturningBuilder {
+	capabilities := capabilitiesOf(adapter)
+	support := capabilities.Returning
+	if support == adapters.ReturningNone || (update && support != adapters.ReturningAll) {
+		return nil
+	}
+
+	if returningBuilder, ok := legacyAdapter(adapter).(adapters.ReturningBuilder); ok {
+		return returningBuilder
+	}
+	return standardReturningBuilder{adapter: adapter, position: capabilities.ReturningPosition}
+}
+
+// standardReturningBuilder builds a RETURNING clause at the end of the
+// statements, or an OUTPUT clause with SQL Server.
+type standardReturningBuilder struct {
+	adapter  adapters.Adapter
+	position adapters.ReturningPosition
+}
+
+func (b standardReturningBuilder) ReturningBuild(columns []string) string {
+	if b.position == adapters.ReturningSQLServer {
+		return "OUTPUT " + strings.Join(columns, ", ")
+	}
+	return "RETURNING " + strings.Join(columns, ", ")
+}
+
+func (b standardReturningBuilder) FormatForNewValues(columns []string) []string {
+	prefix := ""
+	if b.position == adapters.ReturningSQLServer {
+		prefix = "INSERTED."
+	}
+	formatedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		formatedColumns = append(formatedColumns, prefix+b.adapter.Quote(column))
+	}
+	return formatedColumns
+}
+
+func (b standardReturningBuilder) GetReturningPosition() adapters.ReturningPosition {
+	if b.position == adapters.ReturningSQLServer {
+		return adapters.ReturningSQLServer
+	}
+	return adapters.ReturningPostgreSQL
+}
@@ -0,0 +1,125 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// minimalAdapter is an adapter implementing only the required methods.
+type minimalAdapter struct{}
+
+func (minimalAdapter) DriverName() string             { return "minimal" }
+func (minimalAdapter) Quote(identifier string) string { return `"` + identifier + `"` }
+func (minimalAdapter) ParseError(err error) error     { return err }
+
+// returningAdapter only declares the support of the RETURNING clause.
+type returningAdapter struct {
+	minimalAdapter
+}
+
+func (returningAdapter) Capabilities() adapters.Capabilities {
+	return adapters.Capabilities{Returning: adapters.ReturningAll}
+}
+
+func TestCapabilities(t *testing.T) {
+	Convey("Given an adapter without capabilities", t, func() {
+		db := &DB{adapter: minimalAdapter{}}
+
+		Convey("Only the common features are supported", func() {
+			So(db.Capabilities(), ShouldResemble, adapters.Capabilities{})
+			So(db.ReturningBuilder(false), ShouldBeNil)
+		})
+
+		Convey("Unsupported features fail early with typed errors", func() {
+			_, _, err := db.SelectFrom("jobs").Columns("id").ForUpdate().ToSQL()
+			So(errors.Is(err, errors.ErrUnsupported), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "FOR UPDATE is not supported by the database")
+
+			_, _, err = db.InsertInto("jobs").Columns("id").Values(1).OnConflictDoNothing("id").ToSQL()
+			So(errors.Is(err, errors.ErrUnsupported), ShouldBeTrue)
+
+			_, _, err = db.DeleteFrom("jobs").Returning("id").ToSQL()
+			var unsupportedError *UnsupportedError
+			So(errors.As(err, &unsupportedError), ShouldBeTrue)
+			So(unsupportedError.Feature, ShouldEqual, "RETURNING")
+		})
+
+	})
+
+	Convey("Given an adapter declaring the RETURNING support", t, func() {
+		db := &DB{adapter: returningAdapter{}}
+
+		Convey("A standard RETURNING clause is used", func() {
+			sql, _, err := db.UpdateTable("books").
+				Set("title", "foo").
+				Returning(db.ReturningBuilder(true).FormatForNewValues([]string{"id"})...).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `UPDATE books SET title=? RETURNING "id" `)
+		})
+	})
+
+	Convey("Given a MariaDB 10.5 server adapter", t, func() {
+		adapter, err := mysql.NewServer("10.5.21-MariaDB")
+		So(err, ShouldBeNil)
+		db := &DB{adapter: adapter}
+
+		Convey("RETURNING is only supported in INSERT and DELETE statements", func() {
+			So(db.ReturningBuilder(false), ShouldNotBeNil)
+			So(db.ReturningBuilder(true), ShouldBeNil)
+
+			sql, _, err := db.DeleteFrom("books").
+				Where("id = ?", 1).
+				Returning(db.ReturningBuilder(false).FormatForNewValues([]string{"id"})...).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM books WHERE id = ? RETURNING `id` ")
+
+			_, _, err = db.UpdateTable("books").Set("title", "foo").Returning("`id`").ToSQL()
+			So(errors.Is(err, errors.ErrUnsupported), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Statements with too many arguments are rejected before execution", func() {
+			ids := make([]interface{}, sqlite.Adapter.Capabilities().MaxParameters+1)
+			_, err := db.DeleteFrom("dummies").Where("id IN (?)", ids).Do()
+			var tooManyParametersError *TooManyParametersError
+			So(errors.As(err, &tooManyParametersError), ShouldBeTrue)
+			So(tooManyParametersError.Max, ShouldEqual, 999)
+		})
+
+		Convey("Savepoints rollback a part of a transaction", func() {
+			So(db.Savepoint("none"), ShouldNotBeNil)
+
+			So(db.Begin(), ShouldBeNil)
+			_, err := db.DeleteFrom("dummies").Where("id = ?", 1).Do()
+			So(err, ShouldBeNil)
+			So(db.Savepoint("before_second"), ShouldBeNil)
+			_, err = db.DeleteFrom("dummies").Where("id = ?", 2).Do()
+			So(err, ShouldBeNil)
+			So(db.RollbackToSavepoint("before_second"), ShouldBeNil)
+			So(db.ReleaseSavepoint("before_second"), ShouldBeNil)
+			So(db.Savepoint("a b"), ShouldNotBeNil)
+			So(db.Commit(), ShouldBeNil)
+
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Boolean literals follow the database style", t, func() {
+		So(sqlite.Adapter.Capabilities().BooleanLiteral(true), ShouldEqual, "TRUE")
+		So(mssql.Adapter.Capabilities().BooleanLiteral(false), ShouldEqual, "0")
+	})
+}
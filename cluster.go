@@ -0,0 +1,347 @@
+package godb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dberror"
+)
+
+// Balancer chooses the replica running a read query.
+//
+// Choose gets the healthy replicas (at least one), and returns the position
+// of the chosen one in the given slice. It could be called concurrently.
+type Balancer interface {
+	Choose(replicas []ReplicaState) int
+}
+
+// ReplicaState describes a replica of a cluster.
+type ReplicaState struct {
+	// Index is the position of the replica in the cluster, starting at 0
+	Index int
+	// Healthy is false if the replica failed, until a successful health check
+	Healthy bool
+	// Latency is the average duration of the last queries and health checks
+	Latency time.Duration
+}
+
+// RoundRobinBalancer uses the replicas one after another.
+type RoundRobinBalancer struct {
+	next atomic.Uint64
+}
+
+func (b *RoundRobinBalancer) Choose(replicas []ReplicaState) int {
+	return int((b.next.Add(1) - 1) % uint64(len(replicas)))
+}
+
+// RandomBalancer uses a random replica.
+type RandomBalancer struct{}
+
+func (RandomBalancer) Choose(replicas []ReplicaState) int {
+	return rand.Intn(len(replicas))
+}
+
+// LeastLatencyBalancer uses the replica having the lowest latency.
+type LeastLatencyBalancer struct{}
+
+func (LeastLatencyBalancer) Choose(replicas []ReplicaState) int {
+	chosen := 0
+	for i, replica := range replicas {
+		if replica.Latency < replicas[chosen].Latency {
+			chosen = i
+		}
+	}
+	return chosen
+}
+
+// cluster contains the replicas of the primary database.
+type cluster struct {
+	replicas []*replica
+
+	mutex       sync.Mutex
+	balancer    Balancer
+	stopChecks  chan struct{}
+	checksGroup sync.WaitGroup
+}
+
+// replica is a database receiving the read queries.
+type replica struct {
+	sqlDB     *sql.DB
+	unhealthy atomic.Bool
+	// Average latency in nanoseconds
+	latency atomic.Int64
+}
+
+// latencyWeight is the weight of the last duration in the average latency.
+const latencyWeight = 0.2
+
+// observe adds the given duration to the average latency.
+func (r *replica) observe(duration time.Duration) {
+	for {
+		latency := r.latency.Load()
+		newLatency := int64(duration)
+		if latency != 0 {
+			newLatency = int64(float64(latency)*(1-latencyWeight) + float64(duration)*latencyWeight)
+		}
+		if r.latency.CompareAndSwap(latency, newLatency) {
+			return
+		}
+	}
+}
+
+// check pings the replica and updates its state.
+func (r *replica) check() error {
+	startTime := time.Now()
+	err := r.sqlDB.Ping()
+	if err != nil {
+		r.unhealthy.Store(true)
+		return err
+	}
+	r.observe(timeElapsedSince(startTime))
+	r.unhealthy.Store(false)
+	return nil
+}
+
+// OpenCluster creates a new DB like Open, with read-only replicas of the
+// primary database. The read queries are run on a replica chosen by the
+// balancer (see SetBalancer), except inside transactions and for the
+// statements forced to run on the primary (see SelectStatement.OnPrimary).
+// The writes and the raw queries are run on the primary (see
+// RawSQL.OnReplica).
+func OpenCluster(adapter adapters.Adapter, primaryDataSourceName string, replicaDataSourceNames ...string) (*DB, error) {
+	db, err := Open(adapter, primaryDataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]*sql.DB, 0, len(replicaDataSourceNames))
+	for _, dataSourceName := range replicaDataSourceNames {
+		replica, err := sql.Open(adapter.DriverName(), dataSourceName)
+		if err != nil {
+			for _, replica := range replicas {
+				replica.Close()
+			}
+			db.Close()
+			return nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+
+	db.cluster = newCluster(replicas)
+	return db, nil
+}
+
+// WrapCluster creates a godb.DB like Wrap, with the given read-only replicas
+// of the primary database (see OpenCluster).
+func WrapCluster(adapter adapters.Adapter, primary *sql.DB, replicas ...*sql.DB) *DB {
+	db := Wrap(adapter, primary)
+	db.cluster = newCluster(replicas)
+	return db
+}
+
+// newCluster returns a cluster with the given replicas, using a
+// RoundRobinBalancer.
+func newCluster(replicaDBs []*sql.DB) *cluster {
+	c := &cluster{balancer: &RoundRobinBalancer{}}
+	for _, sqlDB := range replicaDBs {
+		c.replicas = append(c.replicas, &replica{sqlDB: sqlDB})
+	}
+	return c
+}
+
+// SetBalancer sets the balancer choosing the replica of the read queries
+// (RoundRobinBalancer by default). It does nothing without replicas.
+func (db *DB) SetBalancer(balancer Balancer) {
+	if db.cluster == nil {
+		return
+	}
+	db.cluster.mutex.Lock()
+	defer db.cluster.mutex.Unlock()
+	db.cluster.balancer = balancer
+}
+
+// Replicas returns the state of the replicas, nil without replicas.
+func (db *DB) Replicas() []ReplicaState {
+	if db.cluster == nil {
+		return nil
+	}
+	return db.cluster.states(false)
+}
+
+// CheckReplicas pings all replicas, the failing ones aren't used until a
+// successful check, and the other ones are used again. It returns the first
+// error encountered.
+func (db *DB) CheckReplicas() error {
+	if db.cluster == nil {
+		return nil
+	}
+
+	var firstErr error
+	for i, replica := range db.cluster.replicas {
+		if err := replica.check(); err != nil {
+			db.logPrintln("Replica", i, "is unhealthy :", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// StartHealthChecks checks the replicas at the given interval (see
+// CheckReplicas), until the DB is closed.
+func (db *DB) StartHealthChecks(interval time.Duration) {
+	if db.cluster == nil {
+		return
+	}
+
+	db.cluster.mutex.Lock()
+	defer db.cluster.mutex.Unlock()
+	if db.cluster.stopChecks != nil {
+		return
+	}
+	stop := make(chan struct{})
+	db.cluster.stopChecks = stop
+
+	checker := db.Clone()
+	db.cluster.checksGroup.Add(1)
+	go func() {
+		defer db.cluster.checksGroup.Done()
+		defer checker.Clear()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				checker.CheckReplicas()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// OnPrimary runs the statement on the primary database, even if there are
+// replicas. Use it when the rows have to be up to date.
+func (ss *SelectStatement) OnPrimary() *SelectStatement {
+	ss.onPrimary = true
+	return ss
+}
+
+// OnPrimary runs the statement on the primary database (see
+// SelectStatement.OnPrimary).
+func (ss *StructSelect) OnPrimary() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement.OnPrimary()
+	return ss
+}
+
+// OnReplica runs the query on a replica, if there are some. The raw queries
+// run on the primary by default, as they could change the data.
+func (raw *RawSQL) OnReplica() *RawSQL {
+	raw.onReplica = true
+	return raw
+}
+
+// states returns the state of the replicas, only the healthy ones if
+// onlyHealthy is true.
+func (c *cluster) states(onlyHealthy bool) []ReplicaState {
+	states := make([]ReplicaState, 0, len(c.replicas))
+	for i, replica := range c.replicas {
+		healthy := !replica.unhealthy.Load()
+		if onlyHealthy && !healthy {
+			continue
+		}
+		states = append(states, ReplicaState{
+			Index:   i,
+			Healthy: healthy,
+			Latency: time.Duration(replica.latency.Load()),
+		})
+	}
+	return states
+}
+
+// choose returns a healthy replica chosen by the balancer, or nil if there is
+// none.
+func (c *cluster) choose() *replica {
+	states := c.states(true)
+	if len(states) == 0 {
+		return nil
+	}
+
+	c.mutex.Lock()
+	balancer := c.balancer
+	c.mutex.Unlock()
+
+	chosen := balancer.Choose(states)
+	if chosen < 0 || chosen >= len(states) {
+		chosen = 0
+	}
+	return c.replicas[states[chosen].Index]
+}
+
+// close stops the health checks and closes the replicas.
+func (c *cluster) close() error {
+	c.mutex.Lock()
+	if c.stopChecks != nil {
+		close(c.stopChecks)
+		c.stopChecks = nil
+	}
+	c.mutex.Unlock()
+	c.checksGroup.Wait()
+
+	var firstErr error
+	for _, replica := range c.replicas {
+		if err := replica.sqlDB.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// replicaFor returns the replica running the query described by info, or nil
+// if it has to run on the primary.
+func (db *DB) replicaFor(info queryInfo) *replica {
+	if db.cluster == nil || !info.read || db.sqlTx != nil {
+		return nil
+	}
+	return db.cluster.choose()
+}
+
+// replicaFailed returns true if the replica failed to run a query with the
+// given error, and marks it as unhealthy. The replica is checked only for
+// connection errors, it returns false if the error comes from the query
+// itself.
+func (db *DB) replicaFailed(replica *replica, err error) bool {
+	if !db.isConnectionError(err) || replica.check() == nil {
+		return false
+	}
+	db.logPrintln("Replica is unhealthy, use the primary :", err)
+	return true
+}
+
+// isConnectionError returns true if the given error could come from a
+// broken connection, rather than from the query.
+func (db *DB) isConnectionError(err error) bool {
+	if err == nil || err == sql.ErrNoRows {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return true
+	}
+	var connectionLost dberror.ConnectionLost
+	return errors.As(db.adapter.ParseError(err), &connectionLost)
+}
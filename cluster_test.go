@@ -0,0 +1,249 @@
+package godb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type ClusterNode struct {
+	ID   int    `db:"id,key,auto"`
+	Name string `db:"name"`
+}
+
+func (*ClusterNode) TableName() string {
+	return "nodes"
+}
+
+// clusterFixturesSetup opens a primary and two replicas, each one in its own
+// SQLite file having a single node row with its name.
+func clusterFixturesSetup(t *testing.T) *DB {
+	dir := t.TempDir()
+	names := []string{"primary", "replica1", "replica2"}
+	dataSourceNames := make([]string, 0, len(names))
+	for _, name := range names {
+		dataSourceName := filepath.Join(dir, name+".db")
+		sqlDB, err := sql.Open("sqlite3", dataSourceName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		createTable :=
+			`create table nodes (
+			id                  integer not null primary key autoincrement,
+			name                text not null);
+		`
+		if _, err := sqlDB.Exec(createTable); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sqlDB.Exec("insert into nodes (name) values (?)", name); err != nil {
+			t.Fatal(err)
+		}
+		sqlDB.Close()
+		dataSourceNames = append(dataSourceNames, dataSourceName)
+	}
+
+	db, err := OpenCluster(sqlite.Adapter, dataSourceNames[0], dataSourceNames[1:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func nodeNames(db *DB, count int) []string {
+	names := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		node := ClusterNode{}
+		So(db.Select(&node).Do(), ShouldBeNil)
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func TestCluster(t *testing.T) {
+	Convey("Given a DB with a primary and two replicas", t, func() {
+		db := clusterFixturesSetup(t)
+
+		Convey("The read queries run on the replicas one after another", func() {
+			So(nodeNames(db, 4), ShouldResemble, []string{"replica1", "replica2", "replica1", "replica2"})
+		})
+
+		Convey("All kinds of read queries run on a replica", func() {
+			var name string
+			So(db.SelectFrom("nodes").Columns("name").Scanx(&name), ShouldBeNil)
+			So(name, ShouldEqual, "replica1")
+
+			nodes := make([]ClusterNode, 0)
+			So(db.SelectFrom("nodes").Do(&nodes), ShouldBeNil)
+			So(nodes[0].Name, ShouldEqual, "replica2")
+
+			node := ClusterNode{}
+			So(db.RawSQL("select * from nodes").OnReplica().Do(&node), ShouldBeNil)
+			So(node.Name, ShouldEqual, "replica1")
+
+			iter, err := db.SelectFrom("nodes").Columns("id", "name").DoWithIterator()
+			So(err, ShouldBeNil)
+			So(iter.Next(), ShouldBeTrue)
+			So(iter.Scan(&node), ShouldBeNil)
+			So(iter.Close(), ShouldBeNil)
+			So(node.Name, ShouldEqual, "replica2")
+		})
+
+		Convey("OnPrimary forces the primary", func() {
+			node := ClusterNode{}
+			So(db.Select(&node).OnPrimary().Do(), ShouldBeNil)
+			So(node.Name, ShouldEqual, "primary")
+
+			count, err := db.SelectFrom("nodes").Where("name = ?", "primary").OnPrimary().Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("The raw queries run on the primary unless OnReplica is called", func() {
+			node := ClusterNode{}
+			So(db.RawSQL("select * from nodes").Do(&node), ShouldBeNil)
+			So(node.Name, ShouldEqual, "primary")
+
+			iter, err := db.RawSQL("select id, name from nodes").DoWithIterator()
+			So(err, ShouldBeNil)
+			So(iter.Next(), ShouldBeTrue)
+			So(iter.Scan(&node), ShouldBeNil)
+			So(iter.Close(), ShouldBeNil)
+			So(node.Name, ShouldEqual, "primary")
+
+			So(db.RawSQL("select * from nodes").OnReplica().Do(&node), ShouldBeNil)
+			So(node.Name, ShouldEqual, "replica1")
+		})
+
+		Convey("The writes run on the primary", func() {
+			node := ClusterNode{Name: "new"}
+			So(db.Insert(&node).Do(), ShouldBeNil)
+
+			count, err := db.SelectFrom("nodes").OnPrimary().Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+			count, err = db.SelectFrom("nodes").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("The read queries run on the primary inside a transaction", func() {
+			So(db.Begin(), ShouldBeNil)
+			So(nodeNames(db, 2), ShouldResemble, []string{"primary", "primary"})
+			So(db.Rollback(), ShouldBeNil)
+		})
+
+		Convey("The clones share the replicas", func() {
+			So(nodeNames(db, 1), ShouldResemble, []string{"replica1"})
+			So(nodeNames(db.Clone(), 1), ShouldResemble, []string{"replica2"})
+		})
+
+		Convey("A failing replica is replaced by the primary, until it's healthy again", func() {
+			failing := db.cluster.replicas[0]
+			sqlDB := failing.sqlDB
+			failing.sqlDB, _ = sql.Open("sqlite3", "file:/nonexistent/dir/replica.db?mode=ro")
+
+			So(nodeNames(db, 3), ShouldResemble, []string{"primary", "replica2", "replica2"})
+			So(db.Replicas()[0].Healthy, ShouldBeFalse)
+			So(db.CheckReplicas(), ShouldNotBeNil)
+
+			failing.sqlDB.Close()
+			failing.sqlDB = sqlDB
+			So(db.CheckReplicas(), ShouldBeNil)
+			So(db.Replicas()[0].Healthy, ShouldBeTrue)
+		})
+
+		Convey("A replica is checked only after a connection error", func() {
+			failing := db.cluster.replicas[0]
+			sqlDB := failing.sqlDB
+			failing.sqlDB, _ = sql.Open("sqlite3", "file:/nonexistent/dir/replica.db?mode=ro")
+			defer func() {
+				failing.sqlDB.Close()
+				failing.sqlDB = sqlDB
+			}()
+
+			So(db.replicaFailed(failing, errors.New("no such column: unknown")), ShouldBeFalse)
+			So(db.Replicas()[0].Healthy, ShouldBeTrue)
+			So(db.replicaFailed(failing, sql.ErrNoRows), ShouldBeFalse)
+			So(db.Replicas()[0].Healthy, ShouldBeTrue)
+
+			So(db.replicaFailed(failing, fmt.Errorf("query : %w", driver.ErrBadConn)), ShouldBeTrue)
+			So(db.Replicas()[0].Healthy, ShouldBeFalse)
+		})
+
+		Convey("A connection error of a healthy replica comes from the query", func() {
+			So(db.replicaFailed(db.cluster.replicas[0], sql.ErrConnDone), ShouldBeFalse)
+			So(db.Replicas()[0].Healthy, ShouldBeTrue)
+		})
+
+		Convey("The primary is used if all replicas are unhealthy", func() {
+			for _, replica := range db.cluster.replicas {
+				replica.unhealthy.Store(true)
+			}
+			So(nodeNames(db, 2), ShouldResemble, []string{"primary", "primary"})
+		})
+
+		Convey("SetBalancer changes the choice of the replica", func() {
+			db.SetBalancer(LeastLatencyBalancer{})
+			db.cluster.replicas[0].latency.Store(int64(time.Second))
+			db.cluster.replicas[1].latency.Store(int64(time.Millisecond))
+			So(nodeNames(db, 2), ShouldResemble, []string{"replica2", "replica2"})
+		})
+
+		Convey("StartHealthChecks updates the state of the replicas", func() {
+			db.cluster.replicas[1].unhealthy.Store(true)
+			db.StartHealthChecks(time.Millisecond)
+			So(func() bool {
+				deadline := time.Now().Add(time.Second)
+				for time.Now().Before(deadline) {
+					if db.Replicas()[1].Healthy {
+						return true
+					}
+					time.Sleep(time.Millisecond)
+				}
+				return false
+			}(), ShouldBeTrue)
+		})
+	})
+}
+
+func TestBalancers(t *testing.T) {
+	replicas := []ReplicaState{
+		{Index: 0, Latency: 3 * time.Millisecond},
+		{Index: 2, Latency: time.Millisecond},
+		{Index: 3, Latency: 2 * time.Millisecond},
+	}
+
+	Convey("RoundRobinBalancer uses the replicas one after another", t, func() {
+		balancer := &RoundRobinBalancer{}
+		chosen := []int{}
+		for i := 0; i < 4; i++ {
+			chosen = append(chosen, balancer.Choose(replicas))
+		}
+		So(chosen, ShouldResemble, []int{0, 1, 2, 0})
+	})
+
+	Convey("RandomBalancer returns a position in the slice", t, func() {
+		for i := 0; i < 20; i++ {
+			chosen := RandomBalancer{}.Choose(replicas)
+			So(chosen, ShouldBeBetweenOrEqual, 0, len(replicas)-1)
+		}
+	})
+
+	Convey("LeastLatencyBalancer uses the fastest replica", t, func() {
+		So(LeastLatencyBalancer{}.Choose(replicas), ShouldEqual, 1)
+	})
+
+	Convey("A DB without replicas has no replica state", t, func() {
+		db := Wrap(sqlite.Adapter, nil)
+		So(db.Replicas(), ShouldBeNil)
+		So(db.CheckReplicas(), ShouldBeNil)
+	})
+}
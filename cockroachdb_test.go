@@ -0,0 +1,130 @@
+package godb_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters/cockroachdb"
+	"github.com/samonzeweb/godb/dbtests/common"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func fixturesSetupCockroachDB(t *testing.T) (*godb.DB, func()) {
+	if os.Getenv("GODB_COCKROACHDB") == "" {
+		t.Skip("Don't run CockroachDB test, GODB_COCKROACHDB not set")
+	}
+
+	db, err := godb.Open(cockroachdb.Adapter, os.Getenv("GODB_COCKROACHDB"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Enable logger if needed
+	//db.SetLogger(log.New(os.Stderr, "", 0))
+
+	createTable :=
+		`create table if not exists books (
+		id 						serial primary key,
+		title     		varchar(128) not null,
+		author    	  varchar(128) not null,
+		published			date not null,
+		version       int not null default 0);
+
+		create table if not exists inventories (
+		id             serial primary key,
+		book_id			   int not null,
+		last_inventory date,
+		counting       int not null default 0);
+	`
+	_, err = db.CurrentDB().Exec(createTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixturesTeardown := func() {
+		dropTable := "drop table if exists books; drop table if exists inventories;"
+		_, err := db.CurrentDB().Exec(dropTable)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return db, fixturesTeardown
+}
+
+func TestStatementsCockroachDB(t *testing.T) {
+	Convey("A DB for a CockroachDB database", t, func() {
+		db, teardown := fixturesSetupCockroachDB(t)
+		defer teardown()
+
+		Convey("The common statements tests must pass", func() {
+			common.StatementsTests(db, t)
+		})
+	})
+}
+
+func TestStructsCockroachDB(t *testing.T) {
+	Convey("A DB for a CockroachDB database", t, func() {
+		db, teardown := fixturesSetupCockroachDB(t)
+		defer teardown()
+
+		Convey("The common structs tests must pass", func() {
+			common.StructsTests(db, t)
+		})
+	})
+}
+
+func TestRawCockroachDB(t *testing.T) {
+	Convey("A DB for a CockroachDB database", t, func() {
+		db, teardown := fixturesSetupCockroachDB(t)
+		defer teardown()
+
+		Convey("The common raw tests must pass", func() {
+			common.RawSQLTests(db, t)
+		})
+	})
+}
+
+func TestUpsertAndRetryCockroachDB(t *testing.T) {
+	Convey("A DB for a CockroachDB database", t, func() {
+		db, teardown := fixturesSetupCockroachDB(t)
+		defer teardown()
+
+		published := time.Date(1937, 9, 21, 0, 0, 0, 0, time.UTC)
+
+		Convey("UPSERT replaces the row having the same key", func() {
+			_, err := db.UpsertInto("books").
+				Columns("id", "title", "author", "published").
+				Values(1, "The Hobit", "Tolkien", published).
+				Do()
+			So(err, ShouldBeNil)
+			_, err = db.UpsertInto("books").
+				Columns("id", "title", "author", "published").
+				Values(1, "The Hobbit", "Tolkien", published).
+				Do()
+			So(err, ShouldBeNil)
+
+			count, err := db.SelectFrom("books").Where("title = ?", "The Hobbit").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("A transaction is run with retries", func() {
+			err := db.RunInTransaction(func(tx *godb.DB) error {
+				_, err := tx.InsertInto("books").
+					Columns("title", "author", "published").
+					Values("The Hobbit", "Tolkien", published).
+					Do()
+				return err
+			})
+			So(err, ShouldBeNil)
+		})
+	})
+}
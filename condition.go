@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
 )
 
 // Condition is a struct allowing complex condition building, composing
 // SQL predicates, and managing associated arguments.
 type Condition struct {
-	err  error
-	sql  string
-	args []interface{}
+	err      error
+	sql      string
+	args     []interface{}
+	features sqlFeatures
+	// rebuild builds the condition again with the syntax of a database, it's
+	// nil if the condition doesn't depend on the syntax.
+	rebuild func(adapters.Syntax) *Condition
 }
 
 // Err returns the error of the given condition.
@@ -20,46 +27,104 @@ func (c *Condition) Err() error {
 	return c.err
 }
 
+// defaultSyntax is the syntax of the literals and comments used by Q, which
+// doesn't know the database. The condition is built again with the syntax of
+// the database when it's given to a statement (see adapters.Syntax).
+var defaultSyntax = adapters.PostgreSQLSyntax
+
 // Q builds a simple condition, managing slices in a particular way : it
 // replace the single placeholder with multiples ones according to the number
 // of arguments.
 func Q(sql string, args ...interface{}) *Condition {
+	c := buildCondition(defaultSyntax, sql, false, args)
+	c.rebuild = func(syntax adapters.Syntax) *Condition {
+		return buildCondition(syntax, sql, false, args)
+	}
+	return c
+}
+
+// QNullSafe builds a condition like Q, but also accepts nil and empty
+// slices as arguments :
+//   - "col = ?" with nil becomes "col IS NULL", and "col <> ?" (or "!=")
+//     becomes "col IS NOT NULL".
+//   - "col IN (?)" with an empty slice becomes an always false predicate,
+//     and "col NOT IN (?)" an always true one.
+//
+// Other uses of nil and empty slices are still errors.
+func QNullSafe(sql string, args ...interface{}) *Condition {
+	c := buildCondition(defaultSyntax, sql, true, args)
+	c.rebuild = func(syntax adapters.Syntax) *Condition {
+		return buildCondition(syntax, sql, true, args)
+	}
+	return c
+}
+
+// buildCondition builds the condition for Q and QNullSafe, finding the
+// placeholders with the given syntax.
+func buildCondition(syntax adapters.Syntax, sql string, nullSafe bool, args []interface{}) *Condition {
 	c := Condition{}
 
-	if strings.Count(sql, Placeholder) != len(args) {
+	// Placeholders in literals and comments are ignored
+	placeholderPositions := syntax.FindPlaceholders(sql, Placeholder)
+	if len(placeholderPositions) != len(args) {
 		c.err = fmt.Errorf("wrong number of arguments in condition %s", sql)
 		return &c
 	}
 
 	buffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
-	var placeholderPos int
+	previousEnd := 0
 	remainingSQL := sql[:]
 	// Search slice args to manage case like "WHERE id IN (?)"
-	for _, arg := range args {
+	for i, arg := range args {
+		beforePlaceholder := sql[previousEnd:placeholderPositions[i]]
+		previousEnd = placeholderPositions[i] + len(Placeholder)
+		remainingSQL = sql[previousEnd:]
+
 		if arg == nil {
-			c.err = fmt.Errorf("using nil as argument in condition %s", sql)
-			return &c
+			if !nullSafe {
+				c.err = fmt.Errorf("using nil as argument in condition %s", sql)
+				return &c
+			}
+			predicate, ok := nullPredicate(beforePlaceholder)
+			if !ok {
+				c.err = fmt.Errorf("using nil as argument outside of a comparison in condition %s", sql)
+				return &c
+			}
+			buffer.WriteString(predicate)
+			continue
 		}
 
-		placeholderPos = strings.Index(remainingSQL, Placeholder)
-		buffer.WriteString(remainingSQL[:placeholderPos])
-		remainingSQL = remainingSQL[placeholderPos+1:]
 		t := reflect.TypeOf(arg)
-		// t could be nil if arguments are not given (nil) to prepare a sql statement
-		if t != nil && t.Kind() == reflect.Slice {
-			// Slices. They can't be empty.
+		// []byte is a single value (blob), not a list.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+			// Slices. They can't be empty, except in null safe mode.
 			v := reflect.ValueOf(arg)
-			length := reflect.ValueOf(arg).Len()
+			length := v.Len()
 			if length == 0 {
-				c.err = fmt.Errorf("empty slice used as argument in condition %s", sql)
-				return &c
+				if !nullSafe {
+					c.err = fmt.Errorf("empty slice used as argument in condition %s", sql)
+					return &c
+				}
+				var predicate string
+				var ok bool
+				predicate, remainingSQL, ok = emptyInPredicate(beforePlaceholder, remainingSQL)
+				if !ok {
+					c.err = fmt.Errorf("empty slice used outside of IN clause in condition %s", sql)
+					return &c
+				}
+				buffer.WriteString(predicate)
+				// The closing parenthesis is consumed
+				previousEnd = len(sql) - len(remainingSQL)
+				continue
 			}
-			for i := 0; i < length; i++ {
-				c.args = append(c.args, v.Index(i).Interface())
+			buffer.WriteString(beforePlaceholder)
+			for j := 0; j < length; j++ {
+				c.args = append(c.args, v.Index(j).Interface())
 			}
 			buffer.WriteString(Placeholder + strings.Repeat(","+Placeholder, length-1))
 		} else {
 			// Not a slice
+			buffer.WriteString(beforePlaceholder)
 			buffer.WriteString(Placeholder)
 			c.args = append(c.args, arg)
 		}
@@ -70,6 +135,112 @@ func Q(sql string, args ...interface{}) *Condition {
 	return &c
 }
 
+// nullPredicate rewrites the SQL preceding a nil argument, like "col = " or
+// "col <> ", into "col IS NULL" or "col IS NOT NULL".
+func nullPredicate(beforePlaceholder string) (string, bool) {
+	trimmed := strings.TrimRight(beforePlaceholder, " ")
+	switch {
+	case strings.HasSuffix(trimmed, "<>") || strings.HasSuffix(trimmed, "!="):
+		return strings.TrimRight(trimmed[:len(trimmed)-2], " ") + " IS NOT NULL", true
+	case strings.HasSuffix(trimmed, "<=") || strings.HasSuffix(trimmed, ">="):
+		return "", false
+	case strings.HasSuffix(trimmed, "="):
+		return strings.TrimRight(trimmed[:len(trimmed)-1], " ") + " IS NULL", true
+	default:
+		return "", false
+	}
+}
+
+// emptyInPredicate rewrites "col IN (?)" with an empty slice into an always
+// false predicate, and "col NOT IN (?)" into an always true one. It returns
+// the SQL replacing the one preceding the placeholder, and the SQL following
+// the closing parenthesis.
+func emptyInPredicate(beforePlaceholder string, afterPlaceholder string) (string, string, bool) {
+	after := strings.TrimLeft(afterPlaceholder, " ")
+	if !strings.HasPrefix(after, ")") {
+		return "", "", false
+	}
+	after = after[1:]
+
+	before := strings.TrimRight(beforePlaceholder, " ")
+	if !strings.HasSuffix(before, "(") {
+		return "", "", false
+	}
+	before = strings.TrimRight(before[:len(before)-1], " ")
+	if !hasKeywordSuffix(before, "IN") {
+		return "", "", false
+	}
+	before = strings.TrimRight(before[:len(before)-2], " ")
+
+	predicate := "1 = 0"
+	if hasKeywordSuffix(before, "NOT") {
+		predicate = "1 = 1"
+		before = before[:len(before)-3]
+	}
+
+	// Remove the column (or expression) preceding IN
+	before = strings.TrimRight(before, " ")
+	operandStart := inOperandStart(before)
+	if operandStart < 0 {
+		return "", "", false
+	}
+
+	return before[:operandStart] + predicate, after, true
+}
+
+// inOperandStart returns the position of the operand ending the given sql,
+// like a column or a function call, or -1 if it can't be found or if it's a
+// part of a larger expression.
+func inOperandStart(sql string) int {
+	depth := 0
+	start := len(sql)
+	for ; start > 0; start-- {
+		c := sql[start-1]
+		if depth == 0 && (c == ' ' || c == '(' || c == ',') {
+			break
+		}
+		switch c {
+		case ')':
+			depth++
+		case '(':
+			depth--
+		case '"', '`', ']':
+			// Quoted identifiers could contain spaces or parentheses
+			opening := c
+			if c == ']' {
+				opening = '['
+			}
+			quoteStart := strings.LastIndexByte(sql[:start-1], opening)
+			if quoteStart < 0 {
+				return -1
+			}
+			start = quoteStart + 1
+		}
+	}
+	if start == len(sql) || depth != 0 {
+		return -1
+	}
+
+	// The operand must follow a keyword or a parenthesis, not an operator
+	preceding := strings.TrimRight(sql[:start], " ")
+	if preceding == "" || strings.HasSuffix(preceding, "(") || strings.HasSuffix(preceding, ",") {
+		return start
+	}
+	if c := preceding[len(preceding)-1]; (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return start
+	}
+	return -1
+}
+
+// hasKeywordSuffix returns true if the sql ends with the given keyword
+// (case insensitive), preceded by a space.
+func hasKeywordSuffix(sql string, keyword string) bool {
+	length := len(keyword)
+	return len(sql) > length &&
+		sql[len(sql)-length-1] == ' ' &&
+		strings.EqualFold(sql[len(sql)-length:], keyword)
+}
+
 // And combines two or more conditions inserting 'AND' between each
 // given conditions.
 func And(conditions ...*Condition) *Condition {
@@ -79,7 +250,7 @@ func And(conditions ...*Condition) *Condition {
 
 	sqlLength, argsLength, err := sumOfConditionsLengths(conditions)
 	if err != nil {
-		return &Condition{err: err}
+		return &Condition{err: err, rebuild: rebuildWith(conditions, And)}
 	}
 
 	// because len(" AND ") == 5
@@ -88,8 +259,10 @@ func And(conditions ...*Condition) *Condition {
 	joinedArgs := make([]interface{}, 0, argsLength)
 
 	return &Condition{
-		sql:  joinSQL(buffer, " AND ", conditions).String(),
-		args: joinArgs(joinedArgs, conditions),
+		sql:      joinSQL(buffer, " AND ", conditions).String(),
+		args:     joinArgs(joinedArgs, conditions),
+		features: joinFeatures(conditions),
+		rebuild:  rebuildWith(conditions, And),
 	}
 }
 
@@ -102,7 +275,7 @@ func Or(conditions ...*Condition) *Condition {
 
 	sqlLength, argsLength, err := sumOfConditionsLengths(conditions)
 	if err != nil {
-		return &Condition{err: err}
+		return &Condition{err: err, rebuild: rebuildWith(conditions, Or)}
 	}
 
 	// len(" OR ") == 4 , plus parentheses
@@ -115,15 +288,17 @@ func Or(conditions ...*Condition) *Condition {
 	joinedArgs := make([]interface{}, 0, argsLength)
 
 	return &Condition{
-		sql:  buffer.String(),
-		args: joinArgs(joinedArgs, conditions),
+		sql:      buffer.String(),
+		args:     joinArgs(joinedArgs, conditions),
+		features: joinFeatures(conditions),
+		rebuild:  rebuildWith(conditions, Or),
 	}
 }
 
 // Not negates a given condition surrounding it with 'NOT (' and ')'.
 func Not(condition *Condition) *Condition {
 	if condition.err != nil {
-		return condition
+		return &Condition{err: condition.err, rebuild: rebuildWith([]*Condition{condition}, not)}
 	}
 
 	// len("NOT (") == 5 , and closing parenthesis
@@ -134,9 +309,57 @@ func Not(condition *Condition) *Condition {
 	buffer.WriteString(")")
 
 	return &Condition{
-		sql:  buffer.String(),
-		args: condition.args,
+		sql:      buffer.String(),
+		args:     condition.args,
+		features: condition.features,
+		rebuild:  rebuildWith([]*Condition{condition}, not),
+	}
+}
+
+// not calls Not with the single given condition, for rebuildWith.
+func not(conditions ...*Condition) *Condition {
+	return Not(conditions[0])
+}
+
+// withSyntax returns the condition built with the given syntax, or the
+// condition itself if it doesn't depend on the syntax.
+func (c *Condition) withSyntax(syntax adapters.Syntax) *Condition {
+	if c.rebuild == nil {
+		return c
 	}
+	rebuilt := c.rebuild(syntax)
+	rebuilt.rebuild = nil
+	return rebuilt
+}
+
+// rebuildWith returns the function rebuilding a condition combining the given
+// ones with combine, or nil if none of them depends on the syntax.
+func rebuildWith(conditions []*Condition, combine func(...*Condition) *Condition) func(adapters.Syntax) *Condition {
+	if !slices.ContainsFunc(conditions, func(c *Condition) bool { return c.rebuild != nil }) {
+		return nil
+	}
+	return func(syntax adapters.Syntax) *Condition {
+		rebuilt := make([]*Condition, 0, len(conditions))
+		for _, c := range conditions {
+			rebuilt = append(rebuilt, c.withSyntax(syntax))
+		}
+		return combine(rebuilt...)
+	}
+}
+
+// cloneConditions returns a copy of the given conditions list.
+func cloneConditions(conditions []*Condition) []*Condition {
+	if conditions == nil {
+		return nil
+	}
+
+	clones := make([]*Condition, 0, len(conditions))
+	for _, c := range conditions {
+		clone := *c
+		clone.args = slices.Clone(c.args)
+		clones = append(clones, &clone)
+	}
+	return clones
 }
 
 // sumOfConditionsLengths returns the sum of all sql length, the sum of all
@@ -179,3 +402,13 @@ func joinArgs(args []interface{}, conditions []*Condition) []interface{} {
 
 	return args
 }
+
+// joinFeatures returns all the SQL features used by the given conditions.
+func joinFeatures(conditions []*Condition) sqlFeatures {
+	var features sqlFeatures
+	for _, c := range conditions {
+		features |= c.features
+	}
+
+	return features
+}
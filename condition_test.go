@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -30,6 +34,12 @@ func TestQ(t *testing.T) {
 			q := Q("id IN (?) AND is_deleted = ?", []int{123, 456}, 0)
 			So(q.sql, ShouldEqual, "id IN (?,?) AND is_deleted = ?")
 		})
+
+		Convey("A condition with a []byte argument does not expand it", func() {
+			q := Q("data = ?", []byte("foo"))
+			So(q.sql, ShouldEqual, "data = ?")
+			So(q.args, ShouldResemble, []interface{}{[]byte("foo")})
+		})
 	})
 
 	Convey("Q set error field ...", t, func() {
@@ -51,6 +61,152 @@ func TestQ(t *testing.T) {
 	})
 }
 
+func TestQNullSafe(t *testing.T) {
+	Convey("QNullSafe acts like Q", t, func() {
+		q := QNullSafe("id IN (?) AND is_deleted = ?", []int{123, 456}, 0)
+		So(q.err, ShouldBeNil)
+		So(q.sql, ShouldEqual, "id IN (?,?) AND is_deleted = ?")
+		So(q.args, ShouldResemble, []interface{}{123, 456, 0})
+	})
+
+	Convey("QNullSafe manages nil arguments", t, func() {
+		Convey("Equality with nil becomes IS NULL", func() {
+			q := QNullSafe("deleted_at = ? AND id = ?", nil, 1)
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "deleted_at IS NULL AND id = ?")
+			So(q.args, ShouldResemble, []interface{}{1})
+		})
+
+		Convey("Difference with nil becomes IS NOT NULL", func() {
+			q := QNullSafe("deleted_at <> ?", nil)
+			So(q.sql, ShouldEqual, "deleted_at IS NOT NULL")
+			q = QNullSafe("deleted_at!=?", nil)
+			So(q.sql, ShouldEqual, "deleted_at IS NOT NULL")
+		})
+
+		Convey("Other uses of nil are errors", func() {
+			So(QNullSafe("deleted_at >= ?", nil).err, ShouldNotBeNil)
+			So(QNullSafe("foo(?)", nil).err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("QNullSafe manages empty slices", t, func() {
+		Convey("IN with an empty slice is always false", func() {
+			q := QNullSafe("(a = ? OR id IN (?))", 1, []int{})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "(a = ? OR 1 = 0)")
+			So(q.args, ShouldResemble, []interface{}{1})
+		})
+
+		Convey("NOT IN with an empty slice is always true", func() {
+			q := QNullSafe("id not in ( ? ) AND a = ?", []int(nil), 1)
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "1 = 1 AND a = ?")
+		})
+
+		Convey("The operand of IN could be an expression", func() {
+			q := QNullSafe("a = ? AND LOWER(name) IN (?)", 1, []string{})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "a = ? AND 1 = 0")
+
+			q = QNullSafe(`("the name", COALESCE(b, 'x y')) NOT IN (?)`, []string{})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "1 = 1")
+
+			q = QNullSafe(`"the name" IN (?)`, []string{})
+			So(q.err, ShouldBeNil)
+			So(q.sql, ShouldEqual, "1 = 0")
+		})
+
+		Convey("Other uses of empty slices are errors", func() {
+			So(QNullSafe("id = ?", []int{}).err, ShouldNotBeNil)
+			So(QNullSafe("id = domain(?)", []int{}).err, ShouldNotBeNil)
+			So(QNullSafe("a + b IN (?)", []int{}).err, ShouldNotBeNil)
+			So(QNullSafe("f(?, ?) IN (?)", 1, 2, []int{}).err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestConditionSyntax(t *testing.T) {
+	Convey("Where finds the placeholders with the syntax of the database", t, func() {
+		db := &DB{adapter: mysql.Adapter}
+		sql, args, err := db.SelectFrom("dummies").
+			Columns("id").
+			Where(`a_text <> 'it\'s ?' AND id = ? # why ?`, 1).
+			ToSQL()
+		So(err, ShouldBeNil)
+		So(sql, ShouldEqual, "SELECT id FROM dummies WHERE a_text <> 'it\\'s ?' AND id = ? # why ?")
+		So(args, ShouldResemble, []interface{}{1})
+
+		_, _, err = db.SelectFrom("dummies").Columns("id").Where("id = ??", 1, 2).ToSQL()
+		So(err, ShouldBeNil)
+
+		db = &DB{adapter: postgresql.Adapter}
+		_, args, err = db.SelectFrom("dummies").Columns("id").Where("data ??| array['a'] AND id = ?", 1).ToSQL()
+		So(err, ShouldBeNil)
+		So(args, ShouldResemble, []interface{}{1})
+	})
+
+	Convey("Q is built again with the syntax of the database of the statement", t, func() {
+		Convey("MySQL has backslash escapes and no escaped placeholders", func() {
+			db := &DB{adapter: mysql.Adapter}
+			condition := Q(`a_text <> 'it\'s ?' AND id IN (?)`, []int{1, 2})
+			So(condition.sql, ShouldNotEqual, `a_text <> 'it\'s ?' AND id IN (?,?)`)
+			sql, args, err := db.SelectFrom("dummies").
+				Columns("id").
+				WhereQ(Not(condition)).
+				WhereQ(Q("an_integer IN (??)", 3, 4)).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE NOT (a_text <> 'it\'s ?' AND id IN (?,?)) AND an_integer IN (??)`)
+			So(args, ShouldResemble, []interface{}{1, 2, 3, 4})
+		})
+
+		Convey("SQLite has no escaped placeholders", func() {
+			db := &DB{adapter: sqlite.Adapter}
+			sql, args, err := db.UpdateTable("dummies").
+				Set("a_text", "foo").
+				WhereQ(And(Q("an_integer IN (??)", 2, 3), Col("id").Gt(1))).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `UPDATE dummies SET a_text=? WHERE an_integer IN (??) AND "id" > ?`)
+			So(args, ShouldResemble, []interface{}{"foo", 2, 3, 1})
+
+			_, _, err = db.DeleteFrom("dummies").WhereQ(Q("id = ??", 1)).ToSQL()
+			So(err, ShouldNotBeNil)
+
+			sql, args, err = db.SelectFrom("dummies").
+				ColumnsQ(Fn("COUNT", "*").Filter(Q("an_integer IN (??)", 2, 3)).As("total")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT COUNT(*) FILTER (WHERE an_integer IN (??)) AS total FROM dummies")
+			So(args, ShouldResemble, []interface{}{2, 3})
+		})
+
+		Convey("SQL Server skips the bracketed identifiers", func() {
+			db := &DB{adapter: mssql.Adapter}
+			sql, args, err := db.SelectFrom("dummies").
+				Columns("id").
+				WhereQ(Q("[why?] = ? AND id IN (?)", 1, []int{2, 3})).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(db.replacePlaceholders(sql), ShouldEqual, "SELECT id FROM dummies WHERE [why?] = @p1 AND id IN (@p2,@p3)")
+			So(args, ShouldResemble, []interface{}{1, 2, 3})
+		})
+
+		Convey("PostgreSQL unescapes the escaped placeholders", func() {
+			db := &DB{adapter: postgresql.Adapter}
+			sql, args, err := db.SelectFrom("dummies").
+				Columns("id").
+				WhereQ(Or(Q("data ?? 'key'"), QNamed("id = :id", map[string]interface{}{"id": 1}))).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(db.replacePlaceholders(sql), ShouldEqual, "SELECT id FROM dummies WHERE (data ? 'key' OR id = $1)")
+			So(args, ShouldResemble, []interface{}{1})
+		})
+	})
+}
+
 func TestErr(t *testing.T) {
 	Convey("Err returns the condition error", t, func() {
 		q := Q("?", 123, 456)
@@ -1,41 +1,216 @@
+// Package dberror contains the errors returned by the adapters when the error
+// parser is enabled (see godb.DB.UseErrorParser). All of them give access to
+// the driver error with Unwrap, for errors.Is and errors.As.
+//
+// The names of tables, columns and constraints are filled when the database
+// gives them, otherwise they're empty. Field is kept for compatibility, it's
+// either a column or a constraint name, depending on the database.
 package dberror
 
 import "strings"
 
 // UniqueConstraint error is for handling for unique constrait errors
 type UniqueConstraint struct {
-	Message string `json:"message"`
-	Field   string `json:"field"`
-	Err     error  `json:"err"`
+	Message    string `json:"message"`
+	Field      string `json:"field"`
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	Constraint string `json:"constraint"`
+	Err        error  `json:"err"`
 }
 
 func (e UniqueConstraint) Error() string {
 	return e.Message
 }
 
+func (e UniqueConstraint) Unwrap() error {
+	return e.Err
+}
+
 // CheckConstraint error is for handling for check constrait errors
 type CheckConstraint struct {
-	Message string `json:"message"`
-	Field   string `json:"field"`
-	Err     error  `json:"err"`
+	Message    string `json:"message"`
+	Field      string `json:"field"`
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	Constraint string `json:"constraint"`
+	Err        error  `json:"err"`
 }
 
 func (e CheckConstraint) Error() string {
 	return e.Message
 }
 
-// ForeignKeyConstraint error is for handling for check constrait errors
+func (e CheckConstraint) Unwrap() error {
+	return e.Err
+}
+
+// ForeignKeyConstraint error is for handling for foreign key constrait errors
 type ForeignKeyConstraint struct {
+	Message    string `json:"message"`
+	Field      string `json:"field"`
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	Constraint string `json:"constraint"`
+	Err        error  `json:"err"`
+}
+
+func (e ForeignKeyConstraint) Error() string {
+	return e.Message
+}
+
+func (e ForeignKeyConstraint) Unwrap() error {
+	return e.Err
+}
+
+// NotNullConstraint error is for handling for not null constrait errors
+type NotNullConstraint struct {
 	Message string `json:"message"`
 	Field   string `json:"field"`
+	Table   string `json:"table"`
+	Column  string `json:"column"`
 	Err     error  `json:"err"`
 }
 
-func (e ForeignKeyConstraint) Error() string {
+func (e NotNullConstraint) Error() string {
+	return e.Message
+}
+
+func (e NotNullConstraint) Unwrap() error {
+	return e.Err
+}
+
+// ExclusionConstraint error is for handling for exclusion constrait errors
+// (PostgreSQL)
+type ExclusionConstraint struct {
+	Message    string `json:"message"`
+	Table      string `json:"table"`
+	Constraint string `json:"constraint"`
+	Err        error  `json:"err"`
+}
+
+func (e ExclusionConstraint) Error() string {
+	return e.Message
+}
+
+func (e ExclusionConstraint) Unwrap() error {
+	return e.Err
+}
+
+// Deadlock error is for transactions chosen as victim of a deadlock, they
+// could be retried
+type Deadlock struct {
+	Message string `json:"message"`
+	Err     error  `json:"err"`
+}
+
+func (e Deadlock) Error() string {
+	return e.Message
+}
+
+func (e Deadlock) Unwrap() error {
+	return e.Err
+}
+
+// SerializationFailure error is for transactions which failed because of
+// concurrent transactions, they could be retried
+type SerializationFailure struct {
+	Message string `json:"message"`
+	Err     error  `json:"err"`
+}
+
+func (e SerializationFailure) Error() string {
+	return e.Message
+}
+
+func (e SerializationFailure) Unwrap() error {
+	return e.Err
+}
+
+// LockTimeout error is for statements which failed to acquire a lock in time
+type LockTimeout struct {
+	Message string `json:"message"`
+	Err     error  `json:"err"`
+}
+
+func (e LockTimeout) Error() string {
+	return e.Message
+}
+
+func (e LockTimeout) Unwrap() error {
+	return e.Err
+}
+
+// ConnectionLost error is for statements which failed because the connection
+// to the database is broken
+type ConnectionLost struct {
+	Message string `json:"message"`
+	Err     error  `json:"err"`
+}
+
+func (e ConnectionLost) Error() string {
+	return e.Message
+}
+
+func (e ConnectionLost) Unwrap() error {
+	return e.Err
+}
+
+// StringTruncation error is for strings too long for their column
+type StringTruncation struct {
+	Message string `json:"message"`
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Err     error  `json:"err"`
+}
+
+func (e StringTruncation) Error() string {
 	return e.Message
 }
 
-// ExtractStr is used to extract error message
+func (e StringTruncation) Unwrap() error {
+	return e.Err
+}
+
+// NumericOverflow error is for numbers out of the range of their column or
+// expression
+type NumericOverflow struct {
+	Message string `json:"message"`
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Err     error  `json:"err"`
+}
+
+func (e NumericOverflow) Error() string {
+	return e.Message
+}
+
+func (e NumericOverflow) Unwrap() error {
+	return e.Err
+}
+
+// ExtractStr is used to extract error message, it returns the part of s
+// between left and right, or the end of s if right is missing. It returns an
+// empty string if left is missing.
 func ExtractStr(s, left, right string) string {
-	return strings.Split(strings.Split(s, left)[1], right)[0]
+	start := strings.Index(s, left)
+	if start == -1 {
+		return ""
+	}
+	s = s[start+len(left):]
+	if end := strings.Index(s, right); end != -1 {
+		s = s[:end]
+	}
+	return s
+}
+
+// SplitName splits a qualified name like "books.title" into the qualifier
+// ("books") and the name ("title"). The qualifier is empty for an
+// unqualified name.
+func SplitName(qualifiedName string) (qualifier string, name string) {
+	dot := strings.LastIndex(qualifiedName, ".")
+	if dot == -1 {
+		return "", qualifiedName
+	}
+	return qualifiedName[:dot], qualifiedName[dot+1:]
 }
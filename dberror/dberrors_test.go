@@ -0,0 +1,74 @@
+package dberror
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExtractStr(t *testing.T) {
+	Convey("ExtractStr returns the part between the delimiters", t, func() {
+		cases := []struct {
+			s, left, right, expected string
+		}{
+			{`violates unique constraint "books_title_key"`, "constraint \"", "\"", "books_title_key"},
+			{"UNIQUE constraint failed: books.title", "failed: ", ",", "books.title"},
+			{"duplicate key value", "constraint \"", "\"", ""},
+			{"", "constraint \"", "\"", ""},
+		}
+		for _, c := range cases {
+			So(ExtractStr(c.s, c.left, c.right), ShouldEqual, c.expected)
+		}
+	})
+}
+
+func TestSplitName(t *testing.T) {
+	Convey("SplitName splits the qualifier and the name", t, func() {
+		cases := []struct {
+			qualifiedName, qualifier, name string
+		}{
+			{"books.title", "books", "title"},
+			{"library.books.title", "library.books", "title"},
+			{"title", "", "title"},
+			{"", "", ""},
+		}
+		for _, c := range cases {
+			qualifier, name := SplitName(c.qualifiedName)
+			So(qualifier, ShouldEqual, c.qualifier)
+			So(name, ShouldEqual, c.name)
+		}
+	})
+}
+
+func TestUnwrap(t *testing.T) {
+	Convey("Given a driver error", t, func() {
+		driverErr := errors.New("driver error")
+
+		Convey("All errors give access to it with errors.Is", func() {
+			parsedErrors := []error{
+				UniqueConstraint{Err: driverErr},
+				CheckConstraint{Err: driverErr},
+				ForeignKeyConstraint{Err: driverErr},
+				NotNullConstraint{Err: driverErr},
+				ExclusionConstraint{Err: driverErr},
+				Deadlock{Err: driverErr},
+				SerializationFailure{Err: driverErr},
+				LockTimeout{Err: driverErr},
+				ConnectionLost{Err: driverErr},
+				StringTruncation{Err: driverErr},
+				NumericOverflow{Err: driverErr},
+			}
+			for _, parsedErr := range parsedErrors {
+				So(errors.Is(parsedErr, driverErr), ShouldBeTrue)
+			}
+		})
+
+		Convey("A wrapped error is found with errors.As", func() {
+			err := errors.Join(errors.New("context"), UniqueConstraint{Constraint: "books_title_key", Err: driverErr})
+			var uniqueConstraint UniqueConstraint
+			So(errors.As(err, &uniqueConstraint), ShouldBeTrue)
+			So(uniqueConstraint.Constraint, ShouldEqual, "books_title_key")
+		})
+	})
+}
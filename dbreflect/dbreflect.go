@@ -13,12 +13,14 @@ const optionKey = "key"
 const optionAuto = "auto"
 const optionOpLock = "oplock"
 const optionRelation = "rel"
+const optionTenant = "tenant"
 
 // StructMapping contains the relation between a struct and database columns.
 type StructMapping struct {
 	Name          string
 	structMapping structMappingDetails
 	opLockSQLName string
+	tenantSQLName string
 	fieldCount    int
 	keyCount      int
 	autoCount     int
@@ -40,6 +42,7 @@ type fieldMapping struct {
 	isKey    bool
 	isAuto   bool
 	isOpLock bool
+	isTenant bool
 }
 
 // subStructMapping contrains nested structs.
@@ -68,6 +71,11 @@ func NewStructMapping(structInfo reflect.Type) (*StructMapping, error) {
 		return nil, err
 	}
 
+	err = sm.setTenantField()
+	if err != nil {
+		return nil, err
+	}
+
 	return sm, nil
 }
 
@@ -142,6 +150,7 @@ func (smd *structMappingDetails) newFieldMapping(structField reflect.StructField
 	_, fieldMapping.isAuto = options[optionAuto]
 	_, fieldMapping.isKey = options[optionKey]
 	_, fieldMapping.isOpLock = options[optionOpLock]
+	_, fieldMapping.isTenant = options[optionTenant]
 
 	return fieldMapping, nil
 }
@@ -245,6 +254,33 @@ func (sm *StructMapping) setOpLockField() error {
 	return err
 }
 
+// setTenantField searches the tenant field an update the struct mapping
+// with the tenant field data.
+// It returns an error if there is more then one tenant field.
+func (sm *StructMapping) setTenantField() error {
+	tenantFieldCount := 0
+
+	f := func(fullName string, fieldMapping *fieldMapping, _ *reflect.Value) (stop bool, err error) {
+		if fieldMapping.isTenant {
+			tenantFieldCount++
+			if tenantFieldCount > 1 {
+				sm.tenantSQLName = ""
+				return true, fmt.Errorf("there is more than one tenant field in %s", sm.Name)
+			}
+
+			if fieldMapping.isAuto {
+				return true, fmt.Errorf("the tenant field %s in the struct %s can't be an auto field", fieldMapping.name, sm.Name)
+			}
+
+			sm.tenantSQLName = fullName
+		}
+		return false, nil
+	}
+
+	_, err := sm.structMapping.traverseTree("", "", nil, f)
+	return err
+}
+
 // isValidNonAutoOpLockFieldType check if a field type (Kind) is valid for an
 // optimistic locking field, non automatic.
 func isValidNonAutoOpLockFieldType(fieldMapping *fieldMapping) bool {
@@ -407,6 +443,27 @@ func (sm *StructMapping) GetNonAutoFieldsValuesFiltered(s interface{}, filterCol
 	return columns, values
 }
 
+// GetNonZeroFieldsValues returns the columns names and values of the fields
+// not having the zero value of their type.
+func (sm *StructMapping) GetNonZeroFieldsValues(s interface{}) ([]string, []interface{}) {
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+
+	var columns []string
+	var values []interface{}
+
+	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		if !value.IsZero() {
+			columns = append(columns, fullName)
+			values = append(values, value.Interface())
+		}
+		return false, nil
+	}
+	sm.structMapping.traverseTree("", "", &v, f)
+
+	return columns, values
+}
+
 // GetKeyFieldsValues returns values of key fields, in the same order
 // as TestGetKeyColumnsNames.
 func (sm *StructMapping) GetKeyFieldsValues(s interface{}) []interface{} {
@@ -547,6 +604,41 @@ func (sm *StructMapping) GetAndUpdateOpLockFieldValue(s interface{}) (interface{
 	return currentFieldValue, nil
 }
 
+// GetTenantSQLFieldName returns the sql name of the tenant field, or a blank
+// string if there is none tenant field.
+func (sm *StructMapping) GetTenantSQLFieldName() string {
+	return sm.tenantSQLName
+}
+
+// SetTenantFieldValue sets the value of the tenant field. The value has to be
+// convertible to the field type.
+func (sm *StructMapping) SetTenantFieldValue(s interface{}, tenantValue interface{}) error {
+	if sm.tenantSQLName == "" {
+		return fmt.Errorf("struct %s can't set tenant field, there is no such field", sm.Name)
+	}
+
+	v := reflect.ValueOf(s)
+	v = reflect.Indirect(v)
+
+	f := func(fullName string, fieldMapping *fieldMapping, value *reflect.Value) (stop bool, err error) {
+		if fullName != sm.tenantSQLName {
+			return false, nil
+		}
+		newValue := reflect.ValueOf(tenantValue)
+		// Don't convert numbers to string (as runes)
+		if !newValue.IsValid() ||
+			!newValue.Type().ConvertibleTo(value.Type()) ||
+			(value.Kind() == reflect.String && newValue.Kind() != reflect.String) {
+			return true, fmt.Errorf("invalid tenant value %v for the field %s in the struct %s", tenantValue, fieldMapping.name, sm.Name)
+		}
+		value.Set(newValue.Convert(value.Type()))
+		return true, nil
+	}
+
+	_, err := sm.structMapping.traverseTree("", "", &v, f)
+	return err
+}
+
 // updateNonAutoOpLockField updates the value of the optimistic locking field.
 // It manages only types accepted by isValidNonAutoOpLockFieldType, and of
 // course only non-auto oplock fields.
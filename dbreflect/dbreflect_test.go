@@ -60,6 +60,18 @@ type StructWithInvalidOptimiticLocking struct {
 	BadVersion string `db:"version,oplock"`
 }
 
+type StructWithTenant struct {
+	ID       int    `db:"id,key,auto"`
+	TenantID int64  `db:"tenant_id,tenant"`
+	Text     string `db:"my_text"`
+}
+
+type BadStructMultipleTenants struct {
+	ID       int `db:"id,key,auto"`
+	TenantID int `db:"tenant_id,tenant"`
+	OtherID  int `db:"other_id,tenant"`
+}
+
 type ComplexStructsWithRelations struct {
 	// no prefix but a relation
 	SimpleStruct `db:",rel=firsttable"`
@@ -279,6 +291,26 @@ func TestGetNonAutoFieldsValues(t *testing.T) {
 	})
 }
 
+func TestGetNonZeroFieldsValues(t *testing.T) {
+	Convey("Given a StructMapping and a struct instance (nested)", t, func() {
+		structInstance := ComplexStruct{
+			SimpleStruct: SimpleStruct{
+				ID: 1,
+			},
+			Foobar: SubStruct{
+				Bar: "BAR",
+			},
+		}
+		structMap, _ := NewStructMapping(reflect.TypeOf(&structInstance))
+
+		Convey("GetNonZeroFieldsValues return non zero fields columns and values", func() {
+			columns, values := structMap.GetNonZeroFieldsValues(&structInstance)
+			So(columns, ShouldResemble, []string{"id", "nested_bar"})
+			So(values, ShouldResemble, []interface{}{1, "BAR"})
+		})
+	})
+}
+
 func TestGetKeyFieldsValues(t *testing.T) {
 	Convey("Given a StructMapping and a struct instance (nested)", t, func() {
 		structInstance := SimpleStruct{
@@ -402,3 +434,37 @@ func TestInvalidOpLockFieldType(t *testing.T) {
 
 	})
 }
+
+func TestTenantField(t *testing.T) {
+	Convey("Given a StructMapping with a tenant field", t, func() {
+		structWithTenant := StructWithTenant{}
+		structMap, err := NewStructMapping(reflect.TypeOf(structWithTenant))
+		So(err, ShouldBeNil)
+
+		Convey("NewStructMapping detects the tenant field", func() {
+			So(structMap.GetTenantSQLFieldName(), ShouldEqual, "tenant_id")
+		})
+
+		Convey("SetTenantFieldValue sets the tenant field converting the value", func() {
+			err := structMap.SetTenantFieldValue(&structWithTenant, 123)
+			So(err, ShouldBeNil)
+			So(structWithTenant.TenantID, ShouldEqual, 123)
+		})
+
+		Convey("SetTenantFieldValue returns an error with a value of the wrong type", func() {
+			err := structMap.SetTenantFieldValue(&structWithTenant, "foo")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a struct with multiple tenant fields", t, func() {
+		_, err := NewStructMapping(reflect.TypeOf(BadStructMultipleTenants{}))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Given a struct without tenant field", t, func() {
+		structMap, err := NewStructMapping(reflect.TypeOf(SimpleStruct{}))
+		So(err, ShouldBeNil)
+		So(structMap.GetTenantSQLFieldName(), ShouldEqual, "")
+	})
+}
@@ -5,7 +5,6 @@ import (
 	"github.com/samonzeweb/godb/adapters"
 )
 
-func getReturningBuilder(db *godb.DB) adapters.ReturningBuilder {
-	returningBuilder, _ := db.Adapter().(adapters.ReturningBuilder)
-	return returningBuilder
+func isReturningSupported(db *godb.DB) bool {
+	return db.Capabilities().Returning != adapters.ReturningNone
 }
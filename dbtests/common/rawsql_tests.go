@@ -16,7 +16,7 @@ func RawSQLTests(db *godb.DB, t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if getReturningBuilder(db) != nil {
+	if isReturningSupported(db) {
 		for _, book := range booksToInsert {
 			if book.Id == 0 {
 				t.Fatalf("Id was not set for the book %v", book)
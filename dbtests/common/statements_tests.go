@@ -22,7 +22,7 @@ func StatementsTests(db *godb.DB, t *testing.T) {
 }
 
 func statementInsertTest(db *godb.DB, t *testing.T) {
-	returningBuilder := getReturningBuilder(db)
+	returningBuilder := db.ReturningBuilder(false)
 
 	// Simple insert
 	query := db.InsertInto("books").
@@ -280,7 +280,7 @@ func statementSelectTest(db *godb.DB, t *testing.T) {
 }
 
 func statementUpdateTest(db *godb.DB, t *testing.T) {
-	returningBuilder := getReturningBuilder(db)
+	returningBuilder := db.ReturningBuilder(true)
 
 	db.Begin()
 
@@ -337,7 +337,7 @@ func statementUpdateTest(db *godb.DB, t *testing.T) {
 }
 
 func statementDeleteTest(db *godb.DB, t *testing.T) {
-	returningBuilder := getReturningBuilder(db)
+	returningBuilder := db.ReturningBuilder(false)
 
 	db.Begin()
 
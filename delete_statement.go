@@ -1,19 +1,36 @@
 package godb
 
-import "github.com/samonzeweb/godb/adapters"
+import (
+	"slices"
+
+	"github.com/samonzeweb/godb/adapters"
+)
 
 // DeleteStatement is a DELETE sql statement builder.
 // Initialize it with the DeleteFrom method.
 //
 // Example :
 // 	count, err := db.DeleteFrom("bar").Where("foo = 1").Do()
+//
+// Other tables are used with Using and Join, the statement is written
+// according to the database (see adapters.MultiTableStyle) :
+// 	count, err := db.DeleteFrom("books").
+// 		Join("authors", "", godb.Q("authors.id = books.author_id")).
+// 		Where("authors.deleted = ?", true).
+// 		Do()
 type DeleteStatement struct {
-	db *DB
+	db    *DB
+	error error
 
 	fromTable        string
+	usingTables      []string
+	joins            []*joinPart
 	where            []*Condition
+	orderBy          []string
+	limit            *int
 	returningColumns []string
 	suffixes         []string
+	withoutTenant    bool
 }
 
 // DeleteFrom initializes a DELETE statement builder.
@@ -23,15 +40,64 @@ func (db *DB) DeleteFrom(tableName string) *DeleteStatement {
 	return ds
 }
 
+// Clone returns a deep copy of the statement.
+func (ds *DeleteStatement) Clone() *DeleteStatement {
+	clone := *ds
+	clone.usingTables = slices.Clone(ds.usingTables)
+	clone.joins = cloneJoins(ds.joins)
+	clone.where = cloneConditions(ds.where)
+	clone.orderBy = slices.Clone(ds.orderBy)
+	if ds.limit != nil {
+		clone.Limit(*ds.limit)
+	}
+	clone.returningColumns = slices.Clone(ds.returningColumns)
+	clone.suffixes = slices.Clone(ds.suffixes)
+	return &clone
+}
+
+// Using adds other tables to the statement, the conditions using them are
+// given with Where.
+func (ds *DeleteStatement) Using(tableNames ...string) *DeleteStatement {
+	ds.usingTables = append(ds.usingTables, tableNames...)
+	return ds
+}
+
+// Join adds another table to the statement with an INNER JOIN condition (see
+// Using).
+func (ds *DeleteStatement) Join(tableName string, as string, on *Condition) *DeleteStatement {
+	ds.joins = append(ds.joins, &joinPart{
+		joinType:  "INNER JOIN",
+		tableName: tableName,
+		as:        as,
+		on:        on.withSyntax(ds.db.syntax()),
+	})
+	return ds
+}
+
 // Where adds a condition using string and arguments.
 func (ds *DeleteStatement) Where(sql string, args ...interface{}) *DeleteStatement {
-	return ds.WhereQ(Q(sql, args...))
+	return ds.WhereQ(ds.db.q(sql, args...))
 }
 
 // WhereQ adds a simple or complex predicate generated with Q and
 // confunctions.
 func (ds *DeleteStatement) WhereQ(condition *Condition) *DeleteStatement {
-	ds.where = append(ds.where, condition)
+	ds.where = append(ds.where, condition.withSyntax(ds.db.syntax()))
+	return ds
+}
+
+// OrderBy adds an expression for the ORDER BY clause. Use it with MySQL and
+// SQLite.
+func (ds *DeleteStatement) OrderBy(orderBy string) *DeleteStatement {
+	ds.orderBy = append(ds.orderBy, orderBy)
+	return ds
+}
+
+// Limit specifies the value for the LIMIT clause. Use it with MySQL and
+// SQLite.
+func (ds *DeleteStatement) Limit(limit int) *DeleteStatement {
+	ds.limit = new(int)
+	*ds.limit = limit
 	return ds
 }
 
@@ -52,7 +118,17 @@ func (ds *DeleteStatement) Suffix(suffix string) *DeleteStatement {
 // ToSQL returns a string with the SQL statement (containing placeholders),
 // the arguments slices, and an error.
 func (ds *DeleteStatement) ToSQL() (string, []interface{}, error) {
-	sqlWhereLength, argsWhereLength, err := sumOfConditionsLengths(ds.where)
+	if ds.error != nil {
+		return "", nil, ds.error
+	}
+
+	multiTable := len(ds.usingTables) > 0 || len(ds.joins) > 0
+	if err := ds.db.checkUpdateDeleteLimit(ds.orderBy, ds.limit, multiTable); err != nil {
+		return "", nil, err
+	}
+
+	where := ds.tenantWhere()
+	sqlWhereLength, argsWhereLength, err := sumOfConditionsLengths(where)
 	if err != nil {
 		return "", nil, err
 	}
@@ -63,16 +139,57 @@ func (ds *DeleteStatement) ToSQL() (string, []interface{}, error) {
 		argsWhereLength,
 	)
 
+	style := ds.db.multiTableStyle()
 	sqlBuffer.Write("DELETE")
-	sqlBuffer.writeFrom(ds.fromTable).
-		writeReturningForPosition(ds.returningColumns, adapters.ReturningSQLServer).
-		writeWhere(ds.where).
-		writeReturningForPosition(ds.returningColumns, adapters.ReturningPostgreSQL).
+	if multiTable && style == adapters.MultiTableMySQL {
+		// The target is named before the FROM clause
+		table, alias, ok := splitTableAlias(ds.fromTable)
+		if !ok {
+			table = ds.fromTable
+		} else if alias != "" {
+			table = alias
+		}
+		sqlBuffer.Write(" ").
+			Write(table)
+	}
+	if multiTable && style == adapters.MultiTableSubquery {
+		sqlBuffer.writeFrom(subqueryTarget(ds.fromTable))
+	} else {
+		sqlBuffer.writeFrom(ds.fromTable)
+	}
+	if multiTable && style == adapters.MultiTableMySQL {
+		sqlBuffer.writeMultiTableJoins(ds.usingTables, ds.joins)
+	}
+	sqlBuffer.writeReturningForPosition(ds.returningColumns, adapters.ReturningSQLServer, false)
+	if multiTable {
+		switch style {
+		case adapters.MultiTablePostgreSQL:
+			sqlBuffer.Write(" USING ")
+			sqlBuffer.writeNameList(otherTables(ds.usingTables, ds.joins))
+			where = multiTableWhere(ds.joins, where)
+		case adapters.MultiTableSQLServer:
+			sqlBuffer.writeFrom(otherTables(ds.usingTables, ds.joins)...)
+			where = multiTableWhere(ds.joins, where)
+		case adapters.MultiTableMySQL:
+			// Already written as joins
+		default:
+			where = []*Condition{existsCondition(ds.usingTables, ds.joins, where)}
+		}
+	}
+	sqlBuffer.writeWhere(where).
+		writeReturningForPosition(ds.returningColumns, adapters.ReturningPostgreSQL, false).
+		writeOrderBy(ds.orderBy).
+		writeLimit(ds.limit).
 		writeStringsWithSpaces(ds.suffixes)
 
 	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
 }
 
+// queryInfo describes the statement for the errors.
+func (ds *DeleteStatement) queryInfo() queryInfo {
+	return queryInfo{op: "DELETE", table: ds.fromTable}
+}
+
 // Do executes the builded query, and return thr rows affected count.
 func (ds *DeleteStatement) Do() (int64, error) {
 	query, args, err := ds.ToSQL()
@@ -80,7 +197,7 @@ func (ds *DeleteStatement) Do() (int64, error) {
 		return 0, err
 	}
 
-	result, err := ds.db.do(query, args)
+	result, err := ds.db.do(ds.queryInfo(), query, args)
 	if err != nil {
 		return 0, err
 	}
@@ -114,5 +231,5 @@ func (ds *DeleteStatement) doWithReturning(recordDescription *recordDescription,
 		return 0, err
 	}
 
-	return ds.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter)
+	return ds.db.doSelectOrWithReturning(ds.queryInfo(), query, args, recordDescription, pointersGetter)
 }
@@ -3,6 +3,10 @@ package godb
 import (
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -41,7 +45,8 @@ func TestDeleteWhereQ(t *testing.T) {
 			qc := Q("id = ?", 123)
 			q.WhereQ(qc)
 			So(len(q.where), ShouldEqual, 1)
-			So(q.where[0], ShouldEqual, qc)
+			So(q.where[0].sql, ShouldEqual, qc.sql)
+			So(q.where[0].args, ShouldResemble, qc.args)
 		})
 	})
 }
@@ -105,3 +110,106 @@ func TestDeleteToSQLErrors(t *testing.T) {
 }
 
 // TODO Do !!!!!!!!
+
+func TestDeleteClone(t *testing.T) {
+	Convey("Given a delete statement", t, func() {
+		db := &DB{}
+		q := db.DeleteFrom("dummies").Where("id = ?", 1)
+
+		Convey("Changes to the clone does not alter the original", func() {
+			q.Clone().Where("foo = ?", 2)
+			sql, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM dummies WHERE id = ?")
+			So(args, ShouldResemble, []interface{}{1})
+		})
+	})
+}
+
+func TestDeleteMultiTable(t *testing.T) {
+	Convey("Given a delete statement with other tables", t, func() {
+		build := func(db *DB) *DeleteStatement {
+			return db.DeleteFrom("dummies d").
+				Using("others").
+				Join("relatedtodummies", "r", Q("r.dummies_id = d.id")).
+				Where("r.a_text = ?", "foo")
+		}
+
+		Convey("PostgreSQL uses DELETE ... USING", func() {
+			sql, args, err := build(&DB{adapter: postgresql.Adapter}).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM dummies d USING others, relatedtodummies AS r WHERE (r.dummies_id = d.id) AND (r.a_text = ?)")
+			So(args, ShouldResemble, []interface{}{"foo"})
+		})
+
+		Convey("SQL Server uses a second FROM clause", func() {
+			sql, _, err := build(&DB{adapter: mssql.Adapter}).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM dummies d FROM others, relatedtodummies AS r WHERE (r.dummies_id = d.id) AND (r.a_text = ?)")
+		})
+
+		Convey("MySQL names the target and uses joins", func() {
+			sql, _, err := build(&DB{adapter: mysql.Adapter}).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE d FROM dummies d CROSS JOIN others INNER JOIN relatedtodummies AS r ON r.dummies_id = d.id WHERE r.a_text = ?")
+		})
+
+		Convey("SQLite uses a correlated subquery", func() {
+			sql, _, err := build(&DB{adapter: sqlite.Adapter}).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM dummies AS d WHERE EXISTS (SELECT 1 FROM others, relatedtodummies AS r WHERE (r.dummies_id = d.id) AND (r.a_text = ?))")
+		})
+	})
+
+	Convey("Given a delete statement with ORDER BY and LIMIT", t, func() {
+		Convey("MySQL writes the clauses", func() {
+			sql, args, err := (&DB{adapter: mysql.Adapter}).DeleteFrom("dummies").
+				OrderBy("id").
+				Limit(2).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM dummies ORDER BY id LIMIT ?")
+			So(args, ShouldResemble, []interface{}{2})
+		})
+
+		Convey("Other databases return an error", func() {
+			_, _, err := (&DB{adapter: mssql.Adapter}).DeleteFrom("dummies").OrderBy("id").ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Do deletes the rows matching the other tables", func() {
+			count, err := db.DeleteFrom("relatedtodummies").
+				Join("dummies", "", Q("dummies.id = relatedtodummies.dummies_id")).
+				Where("dummies.an_integer > ?", 11).
+				Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Do deletes the rows of an aliased table matching the other tables", func() {
+			count, err := db.DeleteFrom("dummies d").
+				Join("relatedtodummies", "r", Q("r.dummies_id = d.id")).
+				Where("r.a_text = ?", "REL_First").
+				Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			count, err = db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Do returns an error with ORDER BY and LIMIT", func() {
+			_, err := db.DeleteFrom("dummies").OrderBy("id").Limit(1).Do()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+	})
+}
@@ -7,8 +7,9 @@ between Go structs and databases tables.
 
 godb needs adapters to use databases, some are packaged with godb for :
 
-	* SQLite
+	* SQLite (sqlite.Adapter with cgo, or puresqlite.Adapter in pure Go)
 	* PostgreSQL
+	* CockroachDB
 	* MySQL
 	* SQL Server
 
@@ -78,6 +79,32 @@ Example :
 		.Do()
 	…
 
+InsertStatement also inserts the rows of a select statement with FromSelect,
+or the non auto fields of structs with ValuesFromStruct and ValuesFromSlice
+(only the fields of the columns given with Columns, if any) :
+
+	_, err = db.InsertInto("archived_books").
+		Columns("title", "author").
+		FromSelect(db.SelectFrom("books").Columns("title", "author").Where("published < ?", limit)).
+		Do()
+	…
+
+	_, err = db.InsertInto("books").ValuesFromSlice(&newBooks).Do()
+	…
+
+UpdateStatement and DeleteStatement use other tables with From, Using and
+Join. The statement is written according to the database : UPDATE ... FROM
+and DELETE ... USING with PostgreSQL, joins with MySQL, and a correlated
+EXISTS subquery with SQLite (the SET clause can't use the other tables then).
+With MySQL, OrderBy and Limit restrict the changed rows (SQLite is usually
+built without this option, they return an error) :
+
+	count, err := db.DeleteFrom("books").
+		Join("authors", "", godb.Q("authors.id = books.author_id")).
+		Where("authors.deleted = ?", true).
+		Do()
+	…
+
 The SelectStatement type could also build a query using columns from a structs. It facilitates the build of queries returning values from multiple table (or views). See struct mapping explanations, in particular the `rel` part.
 
 Example :
@@ -107,6 +134,17 @@ Example :
 		LeftJoin("inventories", "inventories", godb.Q("inventories.book_id = books.id")).
 		Do(&booksWithInventories)
 
+Executing a statement does not change it, it can be executed again. All
+statements have a Clone method returning a deep copy, allowing to define a
+base statement and extend it without altering the original one :
+
+	tolkiensBooks := db.SelectFrom("books").Where("author = ?", authorTolkien)
+	…
+
+	err = tolkiensBooks.Clone().
+		Where("published > ?", someDate).
+		Do(&books)
+
 
 Structs tools
 
@@ -154,6 +192,44 @@ Examples :
 	err = db.Select(&multipleBooks).Do()
 	…
 
+Reusable query modifiers are defined with the Scope type, and applied with the
+Scopes method of SelectStatement, StructSelect, UpdateStatement and
+DeleteStatement. A struct can also define default scopes with a DefaultScopes
+method, they are applied by Select unless Unscoped is called :
+
+	func (*Book) DefaultScopes() []godb.Scope {
+		return []godb.Scope{NotDeleted}
+	}
+
+	func NotDeleted(ss *godb.SelectStatement) *godb.SelectStatement {
+		return ss.Where("deleted_at IS NULL")
+	}
+	…
+
+	err = db.Select(&multipleBooks).Do()            // not deleted books
+	err = db.Select(&multipleBooks).Unscoped().Do() // all books
+
+For a shared schema with many tenants, tag the tenant field with the tenant
+option, and use a DB returned by WithTenant. All statements on the tables of
+such structs get a tenant predicate, and inserted rows get the tenant value.
+Without a tenant, these statements return ErrMissingTenant unless
+WithoutTenant is called (Unscoped only disables the default scopes) :
+
+	type Book struct {
+		Id       int    `db:"id,key,auto"`
+		TenantId int    `db:"tenant_id,tenant"`
+		Title    string `db:"title"`
+	}
+
+	err = db.RegisterTenantModels(&Book{})
+	tenantDB := db.WithTenant("tenant_id", tenantId)
+	err = tenantDB.Select(&multipleBooks).Do() // books of the tenant only
+
+The tables have to be registered with RegisterTenantModels before using
+WithTenant, the statements return ErrTenantModelsNotRegistered otherwise. The
+predicate is added for all tables of the statements (joins and other tables of
+UPDATE and DELETE included), and the tenant column can't be updated.
+
 
 Raw queries
 
@@ -167,6 +243,22 @@ Example :
 	books := make([]Book, 0, 0)
 	err = db.RawSQL("select * from books where author = ?", authorAssimov).Do(&books)
 
+Named parameters (:name or @name) are bound from a map or a struct pointer
+with RawSQLNamed, and QNamed for conditions :
+
+	params := map[string]interface{}{"author": authorAssimov}
+	err = db.RawSQLNamed("select * from books where author = :author", params).Do(&books)
+
+The placeholders (and named parameters) in string literals, quoted
+identifiers and comments are ignored, according to the syntax of the database
+(see adapters.Syntax). Q doesn't know the database, its condition is built
+again with the syntax of the database when it's given to a statement (Err
+reports the errors found with the PostgreSQL syntax). With the adapters
+replacing the placeholders, double the placeholder to
+write a question mark operator, like the PostgreSQL JSON operator ?| written
+??| . With MySQL and SQLite the placeholders are given unchanged to the
+driver, ?? are two placeholders.
+
 
 Structs mapping
 
@@ -238,6 +330,14 @@ this simply by simplementing a TableName method :
 		return "books"
 	}
 
+A default schema and a table prefix can be set with the SetSchema and
+SetTablePrefix methods of DB. A struct could also give its schema by
+implementing the SchemaNamer interface :
+
+	db.SetSchema("billing")
+	db.SetTablePrefix("app_")
+	err := db.Select(&book).Do() // SELECT ... FROM "billing"."app_books"
+
 
 Conditions
 
@@ -265,6 +365,65 @@ multiple ones. This allows code like :
 
 	count, err := db.SelectFrom("bar").Where("foo in (?)", fooSlice).Count()
 
+Q returns an error for nil arguments and empty slices. QNullSafe accepts them,
+turning "foo = ?" with nil into "foo IS NULL", and "foo IN (?)" with an empty
+slice into an always false predicate (always true for NOT IN) :
+
+	q := godb.QNullSafe("foo IN (?) AND deleted_at = ?", []int{}, nil)
+
+Conditions can also be built without raw SQL with the Col function. The
+column names are quoted by the adapter, and ILike is emulated with LOWER if
+the database has no ILIKE operator :
+
+	q := godb.And(godb.Col("age").Gt(18), godb.Col("name").ILike("foo%"))
+	count, err := db.SelectFrom("bar").WhereQ(q).Count()
+
+Equality conditions can be built from a map with Eq, or from the non zero
+fields of a struct with WhereStruct :
+
+	q := godb.Eq(map[string]interface{}{"status": "open", "owner_id": []int{1, 2}})
+	err := db.Select(&books).WhereStruct(&Book{Author: "Tolkien"}).Do()
+
+
+Aggregate and window functions
+
+
+Columns with arguments are added with ColumnsQ. The Function type builds
+aggregate and window function calls, with FILTER and OVER clauses. Named
+windows are defined with the Window method of SelectStatement :
+
+	byAuthor := godb.NewWindow().PartitionBy("author").OrderBy("published")
+	err := db.SelectFrom("books").
+		Columns("title").
+		ColumnsQ(
+			godb.RowNumber().Over(byAuthor).As("position"),
+			godb.Lag("title", 1, "").OverWindow("w").As("previous"),
+		).
+		Window("w", godb.NewWindow().OrderBy("published")).
+		Do(&rankedBooks)
+
+Window functions and the FILTER clause are not supported by all databases
+(FILTER is supported by PostgreSQL and SQLite, window functions are not
+supported by MySQL 5.7), the statements using them fail with an
+*UnsupportedError otherwise.
+
+
+Ordering and grouping
+
+
+OrderBy and GroupBy take raw SQL. OrderByAsc and OrderByDesc take a column
+name, checked and quoted, and the optional position of NULL values (emulated
+with a CASE expression if NULLS FIRST / NULLS LAST is not supported).
+OrderByQ takes an expression with arguments :
+
+	err := db.SelectFrom("books").
+		OrderByQ(godb.Q("CASE WHEN author = ? THEN 0 ELSE 1 END", authorTolkien)).
+		OrderByDesc("published", godb.NullsLast).
+		Do(&books)
+
+GroupByRollup, GroupByCube and GroupingSets add grouping sets to the GROUP BY
+clause, with PostgreSQL and SQL Server.
+
 
 SQLBuffer
 
@@ -361,20 +520,178 @@ logger :
 RETURNING and OUTPUT Clauses
 
 
-godb takes advantage of PostgreSQL RETURNING clause, and SQL Server OUTPUT clause.
+godb takes advantage of PostgreSQL and SQLite (3.35 or later, use
+sqlite.ReturningAdapter) RETURNING clause, and SQL Server OUTPUT clause. With
+sqlite.ReturningAdapter, godb.Open fails if the driver uses an older SQLite
+version (the mattn driver bundles SQLite 3.31, the puresqlite adapter always
+uses RETURNING).
 
 With statements tools you have to add a RETURNING clause with the Suffix method
 and call DoWithReturning method instead of Do(). It's optionnal.
 
 With StructInsert it's transparent, the RETURNING or OUTPUT clause is added
 for all 'auto' columns and it's managed for you. One of the big advantage is
-with BulkInsert : for others databases the rows are inserted but the new keys
-are unkonwns. With PostgreSQL and SQL Server the slice is updated for all inserted
-rows.
+with BulkInsert : the slice is updated for all inserted rows.
+
+With MySQL (except MariaDB 10.5 or later, see below) and SQLite
+(sqlite.Adapter) it's emulated : the keys are set with the last inserted id,
+and the other 'auto' columns of inserted and updated structs are selected
+again by key, in the same transaction (the current one, or a new one). The
+ids of a bulk insert aren't always consecutive, so with an auto key the rows
+of a BulkInsert are inserted one by one.
 
 It also enables optimistic locking with *automatic* columns.
 
 
+Upserts and row locking
+
+
+InsertStatement handles the conflicts on a unique key with OnConflictUpdate and
+OnConflictDoNothing. It's built as ON CONFLICT with PostgreSQL and SQLite, and
+as ON DUPLICATE KEY UPDATE with MySQL (MySQL ignores the conflicting columns) :
+
+	_, err := db.InsertInto("stocks").
+		Columns("product_id", "quantity").
+		Values(42, 10).
+		OnConflictUpdate([]string{"product_id"}, "quantity").
+		Do()
+
+SelectStatement and StructSelect lock the selected rows with ForUpdate, and
+skip the rows locked by other transactions with SkipLocked :
+
+	err := db.Select(&jobs).Where("state = ?", "pending").Limit(10).SkipLocked().Do()
+
+
+MySQL and MariaDB servers
+
+
+The mysql.Adapter adapter works with all MySQL and MariaDB versions, using
+only their common features. With mysql.AutoAdapter, godb.Open queries the
+server version and uses an adapter unlocking the features of the server :
+
+	db, err := godb.Open(mysql.AutoAdapter, dataSourceName)
+	features := db.Adapter().(mysql.Server).Features()
+
+With an existing sql.DB use mysql.Detect, and godb.Wrap. The detected adapter
+uses the RETURNING clause with MariaDB 10.5 or later (except in UPDATE
+statements), SKIP LOCKED with MySQL 8 and MariaDB 10.6, and a row alias instead
+of VALUES() in upserts with MySQL 8.0.19 or later. Window functions are
+accepted with MySQL 8 and MariaDB 10.2, and the capabilities tell if CTE are
+supported.
+
+
+Capabilities and savepoints
+
+
+Adapters describe what the database supports with an adapters.Capabilities
+struct : placeholders, LIMIT and OFFSET syntax, RETURNING clause, upsert
+syntax, maximum count of arguments, savepoints, row values comparison, boolean
+literals, ILIKE, row locking, window functions, CTE, ...
+It's returned by db.Capabilities(). The builders consult it, and the
+unsupported features fail before any execution with an *UnsupportedError
+(matching errors.ErrUnsupported), or a *TooManyParametersError :
+
+	_, err := db.InsertInto("stocks").Columns("id").Values(42).OnConflictDoNothing("id").Do()
+	if errors.Is(err, errors.ErrUnsupported) {
+		...
+	}
+
+A third-party adapter only needs the Adapter interface and a Capabilities
+method. The other optional interfaces of the adapters package are deprecated,
+and only used for the adapters without a Capabilities method, except
+ServerDetector choosing the adapter according to the server version.
+
+Savepoints are used in transactions with Savepoint, RollbackToSavepoint and
+ReleaseSavepoint.
+
+
+Errors
+
+
+When the execution of a statement fails, the error of the driver is returned
+unchanged. With UseErrorParser, the returned error is a *QueryError giving the
+kind of statement, its main table and the SQL query. It wraps the error parsed
+by the adapter (see the dberror package), which is found with errors.As :
+
+	db.UseErrorParser()
+	...
+	_, err := db.InsertInto("books").Columns("title").Values(title).Do()
+	var uniqueConstraint dberror.UniqueConstraint
+	if errors.As(err, &uniqueConstraint) {
+		...
+	}
+
+sql.ErrNoRows is never wrapped.
+
+
+Generic adapter
+
+
+For a database without dedicated adapter, the generic.New function builds one
+from the description of the dialect : quote characters and capabilities, with
+the placeholders style (?, $n, @pn or :n), LIMIT and OFFSET syntax
+(LIMIT/OFFSET, OFFSET/FETCH NEXT or TOP) and RETURNING position :
+
+	adapter := generic.New(generic.Dialect{
+		DriverName: "oracle",
+		QuoteStart: "\"",
+		QuoteEnd:   "\"",
+		Capabilities: adapters.Capabilities{
+			Placeholders: adapters.PlaceholderColon,
+			Pagination:   adapters.PaginationOffsetFetch,
+		},
+	})
+	db, err := godb.Open(adapter, dataSourceName)
+
+The packaged adapters describe their dialect with a Dialect variable (ie
+postgresql.Dialect), a starting point to describe a similar database.
+
+
+Transactions retries
+
+
+RunInTransaction runs a function in a transaction, and retries the whole
+transaction if it fails with a serialization failure (dberror.SerializationFailure,
+see SetMaxTransactionRetries). It's the way to handle the retry errors of
+CockroachDB (cockroachdb.Adapter) :
+
+	err := db.RunInTransaction(func(tx *godb.DB) error {
+		if err := tx.Select(&account).Where("id = ?", id).Do(); err != nil {
+			return err
+		}
+		account.Balance -= amount
+		return tx.Update(&account).Do()
+	})
+
+CockroachDB also supports the UPSERT statement, built with db.UpsertInto.
+
+
+Read replicas
+
+
+OpenCluster (or WrapCluster) creates a DB with a primary database and read-only
+replicas. Outside transactions, the select statements and the iterators run on
+a replica, the writes always run on the primary. The raw queries run on the
+primary unless OnReplica is called :
+
+	db, err := godb.OpenCluster(postgresql.Adapter, primaryDSN, replicaDSN1, replicaDSN2)
+	...
+	err = db.Select(&books).Do()               // on a replica
+	err = db.Select(&books).OnPrimary().Do()   // on the primary
+	err = db.RawSQL(insertReturning).Do(&book)          // on the primary
+	err = db.RawSQL(reportQuery).OnReplica().Do(&rows)  // on a replica
+
+The replica is chosen by a Balancer, RoundRobinBalancer by default, or
+RandomBalancer and LeastLatencyBalancer (see SetBalancer). The locking reads
+(FOR UPDATE) run on the primary.
+
+A replica failing to run a query with a connection error, and failing to
+answer a ping, is marked as unhealthy. The query is run on the primary, and the
+replica isn't used until it's checked with CheckReplicas. The other errors are
+returned, they come from the query itself.
+StartHealthChecks checks them periodically until the DB is closed.
+
+
 Prepared statements cache
 
 
@@ -391,6 +708,9 @@ Enabling the non transaction cache could improve performances with single
 goroutine batch. With multiple goroutines accessing the same database : it
 depends ! A benchmark would be wise.
 
+The caches can be used by multiple goroutines : a statement removed from a
+cache while a query is using it is closed only once the query is started.
+
 
 Iterator
 
@@ -431,17 +751,43 @@ longer useful, especially if you don't scan all the resultset.
 		t.Fatal(err)
 	}
 
+The generic helpers Stream, ForEach, All and One are built on iterators. Stream
+returns a Go iterator (iter.Seq2) mapping each row to a new instance of the given
+type, and closes the rows when the loop ends, even with a break :
+
+	query := db.SelectFrom("books").Columns("id", "title", "author", "published")
+	for book, err := range godb.Stream[Book](query) {
+		if err != nil {
+			...
+		}
+		// do something with the book
+		...
+	}
+
+	books, err := godb.All[Book](db.RawSQL("select * from books"))
+
 
 Concurrency
 
-To avoid performance cost godb.DB does not implement synchronization. So a given
-instance of godb.DB should not be used by multiple goroutines. But a godb.DB
-instance can be created and used as a blueprint and cloned for each goroutine.
-See Clone and Clear methods.
+Once configured, a godb.DB can be shared by multiple goroutines : the consumed
+time is updated atomically, and the prepared statements cache is synchronized.
+The transactions are started with BeginTx, which returns a *Tx used by a
+single goroutine, the shared DB is left untouched. The Tx gives the builders
+and the savepoints, but not the methods changing the configuration :
+
+	tx, err := db.BeginTx()
+	if err != nil {
+		...
+	}
+	defer tx.Rollback()
+	if err := tx.Insert(&book).Do(); err != nil {
+		...
+	}
+	err = tx.Commit()
 
-A typical use case is a web server. When the application starts a godb.DB is
-created, and cloned in each http handler with Clone, and ressources are to be
-freed calling Clear (use defer statement).
+RunInTransaction can also be called on a shared DB. But Begin, Commit and
+Rollback change the DB itself, a DB using them should not be shared. As before,
+a godb.DB can be cloned for each goroutine, see Clone and Clear methods.
 
 */
 package godb
@@ -0,0 +1,263 @@
+package godb
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// Column is a column used to build conditions without raw SQL strings.
+// Initialize it with the Col function.
+//
+// The column names are quoted by the adapter when the statement is built,
+// the conditions compose with And, Or and Not like the ones built with Q.
+//
+// Example :
+//
+// 	err := db.SelectFrom("users").
+// 		WhereQ(godb.And(
+// 			godb.Col("age").Gt(18),
+// 			godb.Col("country").In("FR", "BE"),
+// 			godb.Not(godb.Col("deleted_at").IsNull()),
+// 		)).
+// 		Do(&users)
+type Column struct {
+	name string
+}
+
+// Markers delimiting the expression parts resolved with the adapter.
+// They can't be found in valid SQL strings.
+const (
+	identifierMarker = "\x1f"
+	iLikeMarker      = "\x1e"
+)
+
+// Col returns a Column with the given name. The name could be qualified,
+// like "books.title", each part will be quoted.
+func Col(name string) Column {
+	return Column{name: name}
+}
+
+// identifier returns the marked column name, it will be quoted by the
+// adapter.
+func (c Column) identifier() string {
+	return identifierMarker + c.name + identifierMarker
+}
+
+// compare builds a condition comparing the column with the given value. The
+// value could be another Column.
+func (c Column) compare(operator string, value interface{}) *Condition {
+	if other, ok := value.(Column); ok {
+		return Q(c.identifier() + operator + other.identifier())
+	}
+	return Q(c.identifier()+operator+Placeholder, value)
+}
+
+// Eq builds a column = value condition.
+func (c Column) Eq(value interface{}) *Condition {
+	return c.compare(" = ", value)
+}
+
+// Ne builds a column <> value condition.
+func (c Column) Ne(value interface{}) *Condition {
+	return c.compare(" <> ", value)
+}
+
+// Gt builds a column > value condition.
+func (c Column) Gt(value interface{}) *Condition {
+	return c.compare(" > ", value)
+}
+
+// Ge builds a column >= value condition.
+func (c Column) Ge(value interface{}) *Condition {
+	return c.compare(" >= ", value)
+}
+
+// Lt builds a column < value condition.
+func (c Column) Lt(value interface{}) *Condition {
+	return c.compare(" < ", value)
+}
+
+// Le builds a column <= value condition.
+func (c Column) Le(value interface{}) *Condition {
+	return c.compare(" <= ", value)
+}
+
+// In builds a column IN (values) condition. The values could be given as
+// many arguments, or as a single slice.
+func (c Column) In(values ...interface{}) *Condition {
+	return Q(c.identifier()+" IN ("+Placeholder+")", inValues(values))
+}
+
+// NotIn builds a column NOT IN (values) condition. The values could be given
+// as many arguments, or as a single slice.
+func (c Column) NotIn(values ...interface{}) *Condition {
+	return Q(c.identifier()+" NOT IN ("+Placeholder+")", inValues(values))
+}
+
+// inValues returns the given values, or the only value if there is one.
+// Q will expand it if it's a slice.
+func inValues(values []interface{}) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return values
+}
+
+// Between builds a column BETWEEN low AND high condition.
+func (c Column) Between(low interface{}, high interface{}) *Condition {
+	return Q(c.identifier()+" BETWEEN "+Placeholder+" AND "+Placeholder, low, high)
+}
+
+// IsNull builds a column IS NULL condition.
+func (c Column) IsNull() *Condition {
+	return Q(c.identifier() + " IS NULL")
+}
+
+// IsNotNull builds a column IS NOT NULL condition.
+func (c Column) IsNotNull() *Condition {
+	return Q(c.identifier() + " IS NOT NULL")
+}
+
+// Like builds a column LIKE pattern condition.
+func (c Column) Like(pattern interface{}) *Condition {
+	return Q(c.identifier()+" LIKE "+Placeholder, pattern)
+}
+
+// ILike builds a case insensitive column LIKE pattern condition. It uses
+// ILIKE if the adapter supports it, otherwise it's emulated with
+// LOWER(column) LIKE LOWER(pattern).
+func (c Column) ILike(pattern interface{}) *Condition {
+	return Q(iLikeMarker+c.identifier()+iLikeMarker+Placeholder+iLikeMarker, pattern)
+}
+
+// Eq builds a condition from a map of columns names and values, combined
+// with AND. The predicate depends on the value : IS NULL for nil, IN for a
+// slice (except []byte), = otherwise. The columns are sorted by name, and an
+// empty map gives an always true condition.
+//
+// Example :
+//
+// 	q := godb.Eq(map[string]interface{}{"status": "open", "owner_id": []int{1, 2}})
+// 	// "owner_id" IN (?,?) AND "status" = ?
+func Eq(values map[string]interface{}) *Condition {
+	if len(values) == 0 {
+		return Q("1 = 1")
+	}
+
+	columns := slices.Sorted(maps.Keys(values))
+	conditions := make([]*Condition, 0, len(columns))
+	for _, column := range columns {
+		conditions = append(conditions, equalityCondition(column, values[column]))
+	}
+	return And(conditions...)
+}
+
+// equalityCondition builds the predicate of Eq for a single column.
+func equalityCondition(column string, value interface{}) *Condition {
+	if value == nil {
+		return Col(column).IsNull()
+	}
+
+	// Like Q, []byte is a single value
+	valueType := reflect.TypeOf(value)
+	if valueType.Kind() == reflect.Slice && valueType.Elem().Kind() != reflect.Uint8 {
+		return Col(column).In(value)
+	}
+
+	return Col(column).Eq(value)
+}
+
+// structCondition builds a condition with an equality predicate for each
+// non zero field of the given struct. It returns nil if all fields are zero.
+func structCondition(record interface{}) (*Condition, error) {
+	recordInfo, err := buildRecordDescription(record)
+	if err != nil {
+		return nil, err
+	}
+	if recordInfo.isSlice {
+		return nil, fmt.Errorf("a struct is needed to build conditions, got a slice")
+	}
+
+	columns, values := recordInfo.structMapping.GetNonZeroFieldsValues(record)
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	conditions := make([]*Condition, 0, len(columns))
+	for i, column := range columns {
+		conditions = append(conditions, Col(column).Eq(values[i]))
+	}
+	return And(conditions...), nil
+}
+
+// resolveExpressions replaces the expression markers in the given SQL,
+// quoting the identifiers with the adapter, and writing the ILIKE predicates
+// according to the adapter. The adapter could be nil, the identifiers are then
+// not quoted.
+func resolveExpressions(adapter adapters.Adapter, sql string) string {
+	if !strings.ContainsAny(sql, identifierMarker+iLikeMarker) {
+		return sql
+	}
+
+	sql = resolveILikes(adapter, sql)
+
+	var builder strings.Builder
+	builder.Grow(len(sql) + 16)
+	parts := strings.Split(sql, identifierMarker)
+	for i, part := range parts {
+		// The odd parts are the identifiers
+		if i%2 == 0 {
+			builder.WriteString(part)
+		} else {
+			builder.WriteString(quoteIdentifier(adapter, part))
+		}
+	}
+
+	return builder.String()
+}
+
+// resolveILikes replaces the ILIKE markers in the given SQL.
+func resolveILikes(adapter adapters.Adapter, sql string) string {
+	iLikeSupported := capabilitiesOf(adapter).ILike
+
+	parts := strings.Split(sql, iLikeMarker)
+	if len(parts)%3 != 1 {
+		// Not a valid marked SQL, leave it to the database
+		return sql
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(sql) + 16)
+	builder.WriteString(parts[0])
+	// Each ILIKE gives the column, the pattern, and the following SQL
+	for i := 1; i < len(parts); i += 3 {
+		column, pattern := parts[i], parts[i+1]
+		if iLikeSupported {
+			builder.WriteString(column + " ILIKE " + pattern)
+		} else {
+			builder.WriteString("LOWER(" + column + ") LIKE LOWER(" + pattern + ")")
+		}
+		builder.WriteString(parts[i+2])
+	}
+
+	return builder.String()
+}
+
+// quoteIdentifier quotes all parts of the given identifier with the adapter,
+// if any.
+func quoteIdentifier(adapter adapters.Adapter, identifier string) string {
+	if adapter == nil {
+		return identifier
+	}
+
+	parts := strings.Split(identifier, ".")
+	for i := range parts {
+		parts[i] = adapter.Quote(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,174 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestColumnConditions(t *testing.T) {
+	Convey("Given a select statement with a sqlite adapter", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		toSQL := func(condition *Condition) (string, []interface{}) {
+			sql, args, err := db.SelectFrom("dummies").Columns("id").WhereQ(condition).ToSQL()
+			So(err, ShouldBeNil)
+			return sql, args
+		}
+
+		Convey("Comparisons quote the column and use a placeholder", func() {
+			sql, args := toSQL(Col("age").Gt(18))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" > ?`)
+			So(args, ShouldResemble, []interface{}{18})
+
+			sql, _ = toSQL(Col("age").Eq(1))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" = ?`)
+			sql, _ = toSQL(Col("age").Ne(1))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" <> ?`)
+			sql, _ = toSQL(Col("age").Ge(1))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" >= ?`)
+			sql, _ = toSQL(Col("age").Lt(1))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" < ?`)
+			sql, _ = toSQL(Col("age").Le(1))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" <= ?`)
+		})
+
+		Convey("Qualified columns are quoted part by part", func() {
+			sql, _ := toSQL(Col("d.age").Eq(Col("r.age")))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "d"."age" = "r"."age"`)
+		})
+
+		Convey("In accepts many values or a slice", func() {
+			sql, args := toSQL(Col("id").In(1, 2, 3))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "id" IN (?,?,?)`)
+			So(args, ShouldResemble, []interface{}{1, 2, 3})
+
+			sql, args = toSQL(Col("id").NotIn([]int{1, 2}))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "id" NOT IN (?,?)`)
+			So(args, ShouldResemble, []interface{}{1, 2})
+		})
+
+		Convey("Between, IsNull, IsNotNull and Like build the predicates", func() {
+			sql, args := toSQL(Col("age").Between(18, 30))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" BETWEEN ? AND ?`)
+			So(args, ShouldResemble, []interface{}{18, 30})
+
+			sql, _ = toSQL(Col("name").IsNull())
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "name" IS NULL`)
+			sql, _ = toSQL(Col("name").IsNotNull())
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "name" IS NOT NULL`)
+			sql, _ = toSQL(Col("name").Like("foo%"))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "name" LIKE ?`)
+		})
+
+		Convey("ILike is emulated with LOWER", func() {
+			sql, args := toSQL(Col("name").ILike("foo%"))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE LOWER("name") LIKE LOWER(?)`)
+			So(args, ShouldResemble, []interface{}{"foo%"})
+		})
+
+		Convey("Conditions compose with And, Or and Not", func() {
+			sql, args := toSQL(And(
+				Col("age").Gt(18),
+				Or(Col("name").ILike("a%"), Col("name").ILike("b%")),
+				Not(Col("deleted_at").IsNull()),
+			))
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "age" > ? AND (LOWER("name") LIKE LOWER(?) OR LOWER("name") LIKE LOWER(?)) AND NOT ("deleted_at" IS NULL)`)
+			So(args, ShouldResemble, []interface{}{18, "a%", "b%"})
+		})
+
+		Convey("Conditions are used in joins", func() {
+			sql, _, err := db.SelectFrom("dummies d").
+				Columns("d.id").
+				InnerJoin("others", "o", Col("o.dummy_id").Eq(Col("d.id"))).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT d.id FROM dummies d INNER JOIN others AS o ON "o"."dummy_id" = "d"."id"`)
+		})
+
+		Convey("Errors are reported", func() {
+			_, _, err := db.SelectFrom("dummies").Columns("id").WhereQ(Col("id").In()).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given other adapters", t, func() {
+		Convey("Columns are quoted by the adapter", func() {
+			db := &DB{adapter: mysql.Adapter}
+			sql, _, err := db.DeleteFrom("dummies").WhereQ(Col("age").Lt(18)).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM dummies WHERE `age` < ?")
+		})
+
+		Convey("ILike uses ILIKE with PostgreSQL", func() {
+			db := &DB{adapter: postgresql.Adapter}
+			sql, _, err := db.UpdateTable("dummies").Set("a", 1).WhereQ(Col("name").ILike("foo%")).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `UPDATE dummies SET a=? WHERE "name" ILIKE ?`)
+		})
+	})
+}
+
+func TestEq(t *testing.T) {
+	Convey("Given a select statement with a sqlite adapter", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+
+		Convey("Eq chooses the predicate according to the values", func() {
+			sql, args, err := db.SelectFrom("dummies").Columns("id").WhereQ(Eq(map[string]interface{}{
+				"status":   "open",
+				"owner_id": []int{1, 2},
+				"closed":   nil,
+				"data":     []byte("foo"),
+			})).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "closed" IS NULL AND "data" = ? AND "owner_id" IN (?,?) AND "status" = ?`)
+			So(args, ShouldResemble, []interface{}{[]byte("foo"), 1, 2, "open"})
+		})
+
+		Convey("Eq with an empty map is always true", func() {
+			sql, _, err := db.SelectFrom("dummies").Columns("id").WhereQ(Eq(nil)).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE 1 = 1`)
+		})
+	})
+}
+
+func TestWhereStruct(t *testing.T) {
+	Convey("Given a select statement with a sqlite adapter", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+
+		Convey("WhereStruct adds the non zero fields", func() {
+			filter := Dummy{AText: "foo", AnInteger: 12}
+			sql, args, err := db.SelectFrom("dummies").Columns("id").WhereStruct(&filter).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM dummies WHERE "a_text" = ? AND "an_integer" = ?`)
+			So(args, ShouldResemble, []interface{}{"foo", 12})
+		})
+
+		Convey("WhereStruct adds nothing with a zero struct", func() {
+			sql, _, err := db.SelectFrom("dummies").Columns("id").WhereStruct(&Dummy{}).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM dummies`)
+		})
+
+		Convey("WhereStruct needs a struct pointer", func() {
+			_, _, err := db.SelectFrom("dummies").Columns("id").WhereStruct(Dummy{}).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("WhereStruct filters struct selects", func() {
+			dummies := make([]Dummy, 0)
+			err := db.Select(&dummies).WhereStruct(&Dummy{AnInteger: 12}).Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "Second")
+		})
+	})
+}
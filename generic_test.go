@@ -0,0 +1,53 @@
+package godb_test
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/generic"
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/puresqlite"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	"github.com/samonzeweb/godb/dbtests/common"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCommonTestsWithGenericAdapter(t *testing.T) {
+	databases := []struct {
+		name    string
+		dialect generic.Dialect
+		setup   func(*testing.T, adapters.Adapter) (*godb.DB, func())
+	}{
+		{"SQLite", sqlite.Dialect, fixturesSetupSQLiteWithAdapter},
+		{"SQLite (pure Go)", puresqlite.Dialect, fixturesSetupSQLiteWithAdapter},
+		{"PostgreSQL", postgresql.Dialect, fixturesSetupPostgreSQLWithAdapter},
+		{"MySQL", mysql.Dialect, fixturesSetupMySQLWithAdapter},
+		{"SQL Server", mssql.Dialect, fixturesSetupMSSQLWithAdapter},
+	}
+
+	for _, database := range databases {
+		database := database
+		t.Run(database.name, func(t *testing.T) {
+			Convey("A DB using the generic adapter with the dialect of "+database.name, t, func() {
+				db, teardown := database.setup(t, generic.New(database.dialect))
+				defer teardown()
+
+				Convey("The common statements tests must pass", func() {
+					common.StatementsTests(db, t)
+				})
+
+				Convey("The common structs tests must pass", func() {
+					common.StructsTests(db, t)
+				})
+
+				Convey("The common raw tests must pass", func() {
+					common.RawSQLTests(db, t)
+				})
+			})
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/samonzeweb/godb/adapters"
@@ -12,21 +13,32 @@ import (
 
 // DB stores a connection to the database, the current transaction, logger, ...
 // Everything starts with a DB.
-// DB is not thread safe (see Clone).
+// DB can be used by multiple goroutines once configured, as long as they
+// don't use Begin, Commit and Rollback on it (see BeginTx and Clone).
 type DB struct {
 	adapter      adapters.Adapter
 	sqlDB        *sql.DB
 	sqlTx        *sql.Tx
 	logger       Logger
-	consumedTime time.Duration
+	consumedTime atomic.Int64
 	// Called to format db table name if TableName() func is not defined for model struct
 	defaultTableNamer tablenamer.NamerFn
+	// Default schema and prefix of the tables used by struct statements
+	schema      string
+	tablePrefix string
 	// Prepared Statement cache for DB and Tx
 	stmtCacheDB *StmtCache
 	stmtCacheTx *StmtCache
 	// Optional error parsing by adapters (false by default = legacy mode)
 	// Will probably be the default behavior in new major release.
 	useErrorParser bool
+	// Count of retries of transactions run by RunInTransaction
+	maxTransactionRetries int
+	// Optional tenant, and tables having a tenant column (shared by clones)
+	tenant       *tenant
+	tenantTables *tenantTables
+	// Optional replicas running the read queries (shared by clones)
+	cluster *cluster
 }
 
 // Placeholder is the placeholder string, use it to build queries.
@@ -37,11 +49,20 @@ const Placeholder string = "?"
 var ErrOpLock = errors.New("optimistic locking failure")
 
 // Open creates a new DB struct and initialise a sql.DB connection.
+// If the adapter implements adapters.ServerDetector, the server is queried
+// and the adapter is replaced by the detected one.
 func Open(adapter adapters.Adapter, dataSourceName string) (*DB, error) {
 	dbInst, err := sql.Open(adapter.DriverName(), dataSourceName)
 	if err != nil {
 		return nil, err
 	}
+	if detector, ok := adapter.(adapters.ServerDetector); ok {
+		adapter, err = detector.DetectServer(dbInst)
+		if err != nil {
+			dbInst.Close()
+			return nil, err
+		}
+	}
 	return initialize(adapter, dbInst), nil
 }
 
@@ -60,6 +81,9 @@ func initialize(adapter adapters.Adapter, dbInst *sql.DB) *DB {
 		defaultTableNamer: tablenamer.Same(),
 		stmtCacheDB:       newStmtCache(),
 		stmtCacheTx:       newStmtCache(),
+		tenantTables:      newTenantTables(),
+
+		maxTransactionRetries: defaultMaxTransactionRetries,
 	}
 
 	// Prepared statements cache is disabled by default except for Tx
@@ -71,7 +95,8 @@ func initialize(adapter adapters.Adapter, dbInst *sql.DB) *DB {
 // Clone creates a copy of an existing DB, without the current transaction.
 // The clone has consumedTime set to zero, and new prepared statements caches with
 // the same characteristics.
-// Use it to create new DB object before starting a goroutine.
+// Use it to create new DB object before starting a goroutine using Begin,
+// otherwise the DB can be shared (see BeginTx).
 // Use Clear when a clone is not longer useful to free ressources.
 func (db *DB) Clone() *DB {
 	clone := &DB{
@@ -79,13 +104,19 @@ func (db *DB) Clone() *DB {
 		sqlDB:             db.sqlDB,
 		sqlTx:             nil,
 		logger:            db.logger,
-		consumedTime:      0,
 		defaultTableNamer: db.defaultTableNamer,
+		schema:            db.schema,
+		tablePrefix:       db.tablePrefix,
 		stmtCacheDB:       newStmtCache(),
 		stmtCacheTx:       newStmtCache(),
 		useErrorParser:    db.useErrorParser,
+		tenant:            db.tenant,
+		tenantTables:      db.tenantTables,
+		cluster:           db.cluster,
 	}
 
+	clone.maxTransactionRetries = db.maxTransactionRetries
+
 	clone.stmtCacheDB.SetSize(db.stmtCacheDB.GetSize())
 	if !db.stmtCacheDB.IsEnabled() {
 		clone.stmtCacheDB.Disable()
@@ -121,6 +152,12 @@ func (db *DB) Clear() error {
 func (db *DB) Close() error {
 	db.logPrintln("CLOSE DB")
 	db.Clear()
+	if db.cluster != nil {
+		if err := db.cluster.close(); err != nil {
+			db.sqlDB.Close()
+			return err
+		}
+	}
 	return db.sqlDB.Close()
 }
 
@@ -138,17 +175,17 @@ func (db *DB) CurrentDB() *sql.DB {
 // ConsumedTime returns the time consumed by SQL queries executions
 // The duration is reseted when the DB is cloned.
 func (db *DB) ConsumedTime() time.Duration {
-	return db.consumedTime
+	return time.Duration(db.consumedTime.Load())
 }
 
 // ResetConsumedTime resets the time consumed by SQL queries executions
 func (db *DB) ResetConsumedTime() {
-	db.consumedTime = 0
+	db.consumedTime.Store(0)
 }
 
 // addConsumedTime adds duration to the consumed time
 func (db *DB) addConsumedTime(duration time.Duration) {
-	db.consumedTime += duration
+	db.consumedTime.Add(int64(duration))
 }
 
 // timeElapsedSince returns the time elapsed (duration) since a given
@@ -159,11 +196,7 @@ func timeElapsedSince(startTime time.Time) time.Duration {
 
 // quote quotes all part of the given string using the current adapter.
 func (db *DB) quote(identifier string) string {
-	parts := strings.Split(identifier, ".")
-	for i := range parts {
-		parts[i] = db.adapter.Quote(parts[i])
-	}
-	return strings.Join(parts, ".")
+	return quoteIdentifier(db.adapter, identifier)
 }
 
 // quoteAll returns all strings given quoted by the adapter.
@@ -176,15 +209,24 @@ func (db *DB) quoteAll(identifiers []string) []string {
 	return quotedIdentifiers
 }
 
-// replacePlaceholders uses the adapter to change placeholders according to
-// the database used.
+// syntax returns the syntax of the literals and comments of the database.
+func (db *DB) syntax() adapters.Syntax {
+	return db.Capabilities().Syntax
+}
+
+// q builds a condition like Q, with the syntax of the database.
+func (db *DB) q(sql string, args ...interface{}) *Condition {
+	return buildCondition(db.syntax(), sql, false, args)
+}
+
+// replacePlaceholders changes placeholders according to the database used
+// (see adapters.Capabilities.Placeholders).
 func (db *DB) replacePlaceholders(sql string) string {
-	placeholderReplacer, ok := (db.adapter).(adapters.PlaceholdersReplacer)
-	if !ok {
-		return sql
+	if placeholderReplacer, ok := legacyAdapter(db.adapter).(adapters.PlaceholdersReplacer); ok {
+		return placeholderReplacer.ReplacePlaceholders(Placeholder, sql)
 	}
 
-	return placeholderReplacer.ReplacePlaceholders(Placeholder, sql)
+	return db.Capabilities().ReplacePlaceholders(Placeholder, sql)
 }
 
 // SetDefaultTableNamer sets table naming function
@@ -192,7 +234,42 @@ func (db *DB) SetDefaultTableNamer(tnamer tablenamer.NamerFn) {
 	db.defaultTableNamer = tnamer
 }
 
+// SetSchema sets the default schema of the tables used by the struct
+// statements (Select, Insert, Update and Delete). A struct implementing
+// SchemaNamer overrides it.
+func (db *DB) SetSchema(schema string) {
+	db.schema = schema
+}
+
+// SetTablePrefix sets the prefix added to the names of the tables used by the
+// struct statements (Select, Insert, Update and Delete).
+func (db *DB) SetTablePrefix(prefix string) {
+	db.tablePrefix = prefix
+}
+
+// tableName returns the table name of the given record, with the table prefix
+// and the schema if any. The name is not quoted.
+func (db *DB) tableName(recordInfo *recordDescription) string {
+	tableName := db.defaultTableNamer(recordInfo.getTableName())
+	if i := strings.LastIndex(tableName, "."); i >= 0 {
+		// The name already has a schema, only the table gets the prefix
+		return tableName[:i+1] + db.tablePrefix + tableName[i+1:]
+	}
+	tableName = db.tablePrefix + tableName
+
+	schema := db.schema
+	if recordSchema := recordInfo.getSchemaName(); recordSchema != "" {
+		schema = recordSchema
+	}
+	if schema == "" {
+		return tableName
+	}
+	return schema + "." + tableName
+}
+
 // UseErrorParser will allow adapters to parse errors and wrap ones returned by drivers
+// (see the dberror package). It's used for all executed statements, and for
+// the transaction commands. The parsed errors are wrapped into a QueryError.
 func (db *DB) UseErrorParser() {
 	db.useErrorParser = true
 }
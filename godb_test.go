@@ -1,6 +1,8 @@
 package godb
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/samonzeweb/godb/tablenamer"
@@ -30,6 +32,63 @@ func TestClone(t *testing.T) {
 	})
 }
 
+func TestConcurrentUse(t *testing.T) {
+	Convey("Given a DB shared by multiple goroutines", t, func() {
+		db := createFileConnection(t)
+		defer db.Close()
+		db.StmtCacheDB().Enable()
+
+		Convey("The statements and the transactions can run concurrently", func() {
+			const goroutines = 8
+			const iterations = 10
+			var wg sync.WaitGroup
+			errs := make(chan error, goroutines*iterations)
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < iterations; i++ {
+						dummy := Dummy{AText: fmt.Sprintf("%d-%d", g, i), AnotherText: "Bar", AnInteger: i}
+						if err := db.Insert(&dummy).Do(); err != nil {
+							errs <- err
+							continue
+						}
+
+						tx, err := db.BeginTx()
+						if err != nil {
+							errs <- err
+							continue
+						}
+						_, err = tx.UpdateTable("dummies").Set("an_integer", i+1).Where("id = ?", dummy.ID).Do()
+						if err == nil {
+							err = tx.Commit()
+						} else {
+							tx.Rollback()
+						}
+						if err != nil {
+							errs <- err
+						}
+
+						if _, err := db.SelectFrom("dummies").Where("an_integer > ?", 0).Count(); err != nil {
+							errs <- err
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				So(err, ShouldBeNil)
+			}
+			count, err := db.SelectFrom("dummies").Where("an_integer > ?", 0).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, goroutines*iterations)
+			So(db.ConsumedTime(), ShouldBeGreaterThan, 0)
+		})
+	})
+}
+
 func TestTableNamer(t *testing.T) {
 	db := createInMemoryConnection(t)
 	defer db.Close()
@@ -118,6 +177,66 @@ func TestTableNamer(t *testing.T) {
 	})
 }
 
+type schemaTypeToDescribe struct {
+	ID int `db:"id,key,auto"`
+}
+
+func (*schemaTypeToDescribe) TableName() string {
+	return "described"
+}
+
+func (*schemaTypeToDescribe) SchemaName() string {
+	return "other"
+}
+
+type qualifiedTypeToDescribe struct {
+	ID int `db:"id,key,auto"`
+}
+
+func (*qualifiedTypeToDescribe) TableName() string {
+	return "archive.described"
+}
+
+func TestSchemaAndTablePrefix(t *testing.T) {
+	Convey("Given an existing DB with a schema and a table prefix", t, func() {
+		db := createInMemoryConnection(t)
+		defer db.Close()
+		db.SetDefaultTableNamer(tablenamer.Snake())
+		db.SetSchema("billing")
+		db.SetTablePrefix("app_")
+
+		Convey("tableName adds the schema and the prefix to the table name", func() {
+			recordDesc, _ := buildRecordDescription(&typeToDescribe{})
+			So(db.tableName(recordDesc), ShouldEqual, "billing.app_type_to_describe")
+
+			recordDesc, _ = buildRecordDescription(&otherTypeToDescribe{})
+			So(db.tableName(recordDesc), ShouldEqual, "billing.app_others")
+		})
+
+		Convey("tableName uses the schema given by the struct", func() {
+			recordDesc, _ := buildRecordDescription(&schemaTypeToDescribe{})
+			So(db.tableName(recordDesc), ShouldEqual, "other.app_described")
+		})
+
+		Convey("tableName keeps the schema given by TableName", func() {
+			recordDesc, _ := buildRecordDescription(&qualifiedTypeToDescribe{})
+			So(db.tableName(recordDesc), ShouldEqual, "archive.app_described")
+		})
+
+		Convey("Clone copies the schema and the prefix", func() {
+			clone := db.Clone()
+			So(clone.schema, ShouldEqual, "billing")
+			So(clone.tablePrefix, ShouldEqual, "app_")
+		})
+
+		Convey("Struct statements use the qualified table name", func() {
+			sql, _, err := db.Select(&schemaTypeToDescribe{}).statementWithColumns().ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT "id" FROM "other"."app_described"`)
+		})
+	})
+}
+
 func TestQuote(t *testing.T) {
 	Convey("Given an existing DB", t, func() {
 		db := createInMemoryConnection(t)
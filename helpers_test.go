@@ -2,6 +2,7 @@ package godb
 
 import (
 	"database/sql"
+	"path/filepath"
 	"testing"
 
 	"github.com/samonzeweb/godb/adapters/sqlite"
@@ -19,6 +20,31 @@ func createInMemoryConnection(t *testing.T) *DB {
 	return db
 }
 
+// createFileConnection opens a SQLite database in a temporary file, all the
+// connections of the pool share it unlike in memory databases.
+func createFileConnection(t *testing.T) *DB {
+	dataSourceName := filepath.Join(t.TempDir(), "godb.db") + "?_busy_timeout=5000&_journal_mode=WAL"
+	db, err := Open(sqlite.Adapter, dataSourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createTable :=
+		`create table dummies (
+		id                  integer not null primary key autoincrement,
+		a_text              text not null,
+		another_text        text not null,
+		an_integer          integer not null,
+		a_nullable_string   text,
+		version             integer not null default(0));
+	`
+	if _, err := db.CurrentDB().Exec(createTable); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
 // Fixtures
 
 type Dummy struct {
@@ -1,6 +1,12 @@
 package godb
 
-import "github.com/samonzeweb/godb/adapters"
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
 
 // InsertStatement is an INSERT statement builder.
 // Initialize it with the InsertInto method.
@@ -11,23 +17,55 @@ import "github.com/samonzeweb/godb/adapters"
 // 		Columns("foo", "baz").
 // 		Values(2, "something").
 // 		Do()
+//
+// 	id, err = db.InsertInto("archived_books").
+// 		Columns("title", "author").
+// 		FromSelect(db.SelectFrom("books").Columns("title", "author").Where("published < ?", limit)).
+// 		Do()
 type InsertStatement struct {
-	db *DB
+	db    *DB
+	error error
 
+	verb             string
 	columns          []string
 	intoTable        string
 	values           [][]interface{}
+	fromSelect       *SelectStatement
+	upsert           *upsertPart
 	returningColumns []string
 	suffixes         []string
+	withoutTenant    bool
 }
 
 // InsertInto initializes a INSERT statement builder
 func (db *DB) InsertInto(tableName string) *InsertStatement {
-	ip := &InsertStatement{db: db}
+	ip := &InsertStatement{db: db, verb: "INSERT "}
 	ip.intoTable = tableName
 	return ip
 }
 
+// Clone returns a deep copy of the statement.
+func (is *InsertStatement) Clone() *InsertStatement {
+	clone := *is
+	clone.columns = slices.Clone(is.columns)
+	clone.values = make([][]interface{}, 0, len(is.values))
+	for _, values := range is.values {
+		clone.values = append(clone.values, slices.Clone(values))
+	}
+	if is.fromSelect != nil {
+		clone.fromSelect = is.fromSelect.Clone()
+	}
+	if is.upsert != nil {
+		clone.upsert = &upsertPart{
+			conflictColumns: slices.Clone(is.upsert.conflictColumns),
+			updateColumns:   slices.Clone(is.upsert.updateColumns),
+		}
+	}
+	clone.returningColumns = slices.Clone(is.returningColumns)
+	clone.suffixes = slices.Clone(is.suffixes)
+	return &clone
+}
+
 // Columns adds columns to insert.
 func (is *InsertStatement) Columns(columns ...string) *InsertStatement {
 	is.columns = append(is.columns, columns...)
@@ -40,6 +78,79 @@ func (is *InsertStatement) Values(values ...interface{}) *InsertStatement {
 	return is
 }
 
+// ValuesFromStruct adds the values of the non auto fields of the given struct
+// pointer. The columns are set from the struct mapping if none were given,
+// otherwise only the fields of the given columns are used, they have to be
+// non auto columns of the struct.
+func (is *InsertStatement) ValuesFromStruct(record interface{}) *InsertStatement {
+	recordInfo, err := buildRecordDescription(record)
+	if err != nil {
+		is.error = err
+		return is
+	}
+	if recordInfo.isSlice {
+		is.error = fmt.Errorf("ValuesFromStruct accepts only a single instance, got a slice")
+		return is
+	}
+
+	return is.valuesFromRecords(recordInfo)
+}
+
+// ValuesFromSlice adds the values of the non auto fields of each struct of
+// the given slice pointer, see ValuesFromStruct.
+func (is *InsertStatement) ValuesFromSlice(records interface{}) *InsertStatement {
+	recordInfo, err := buildRecordDescription(records)
+	if err != nil {
+		is.error = err
+		return is
+	}
+	if !recordInfo.isSlice {
+		is.error = fmt.Errorf("ValuesFromSlice accepts only a slice")
+		return is
+	}
+
+	return is.valuesFromRecords(recordInfo)
+}
+
+// valuesFromRecords adds the columns and values of the described records.
+func (is *InsertStatement) valuesFromRecords(recordInfo *recordDescription) *InsertStatement {
+	names := recordInfo.structMapping.GetNonAutoColumnsNames()
+	if len(is.columns) == 0 {
+		is.columns = is.db.quoteAll(names)
+	}
+
+	// Position of the field of each statement column
+	positions := make([]int, 0, len(is.columns))
+	for _, column := range is.columns {
+		position := slices.IndexFunc(names, func(name string) bool {
+			return column == name || column == is.db.quote(name)
+		})
+		if position == -1 {
+			is.error = fmt.Errorf("the column %s is not a non auto column of the struct", column)
+			return is
+		}
+		positions = append(positions, position)
+	}
+
+	for i := 0; i < recordInfo.len(); i++ {
+		values := recordInfo.structMapping.GetNonAutoFieldsValues(recordInfo.index(i))
+		rowValues := make([]interface{}, 0, len(positions))
+		for _, position := range positions {
+			rowValues = append(rowValues, values[position])
+		}
+		is.Values(rowValues...)
+	}
+	return is
+}
+
+// FromSelect inserts the rows returned by the given select statement, instead
+// of values, building an INSERT INTO ... SELECT statement. The select
+// columns have to match the insert ones.
+func (is *InsertStatement) FromSelect(selectStatement *SelectStatement) *InsertStatement {
+	is.fromSelect = selectStatement.Clone()
+	return is
+}
+
 // Returning adds a RETURNING or OUTPUT clause to the statement. Use it with
 // PostgreSQL and SQL Server.
 func (is *InsertStatement) Returning(columns ...string) *InsertStatement {
@@ -56,43 +167,95 @@ func (is *InsertStatement) Suffix(suffix string) *InsertStatement {
 // ToSQL returns a string with the SQL statement (containing placeholders),
 // the arguments slices, and an error.
 func (is *InsertStatement) ToSQL() (string, []interface{}, error) {
+	if is.error != nil {
+		return "", nil, is.error
+	}
+	if is.fromSelect != nil {
+		return is.insertSelectToSQL()
+	}
+
+	columns, values, err := is.tenantColumnsAndValues()
+	if err != nil {
+		return "", nil, err
+	}
+
 	// TODO : estimate the buffer size.
 	sqlBuffer := newSQLBuffer(is.db.adapter, 256, 16)
 
-	sqlBuffer.Write("INSERT ")
+	sqlBuffer.Write(is.verb)
 	sqlBuffer.writeInto(is.intoTable)
 	sqlBuffer.Write(" (")
-	sqlBuffer.writeColumns(is.columns)
+	sqlBuffer.writeColumns(columns)
 	sqlBuffer.Write(") ")
-	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningSQLServer)
+	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningSQLServer, false)
 	sqlBuffer.Write("VALUES ")
-	sqlBuffer.writeInsertValues(is.values, len(is.columns))
-	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningPostgreSQL)
+	sqlBuffer.writeInsertValues(values, len(columns))
+	sqlBuffer.writeUpsert(is.db.adapter, is.upsert)
+	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningPostgreSQL, false)
 	sqlBuffer.writeStringsWithSpaces(is.suffixes)
 
 	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
 }
 
+// insertSelectToSQL returns the INSERT INTO ... SELECT statement, its
+// arguments, and an error.
+func (is *InsertStatement) insertSelectToSQL() (string, []interface{}, error) {
+	if len(is.values) > 0 {
+		return "", nil, fmt.Errorf("you can't mix Values and FromSelect to build an insert query")
+	}
+	if is.upsert != nil {
+		return "", nil, fmt.Errorf("you can't mix an upsert and FromSelect to build an insert query")
+	}
+
+	columns, selectStatement, err := is.tenantColumnsAndSelect()
+	if err != nil {
+		return "", nil, err
+	}
+	selectSQL, selectArgs, err := selectStatement.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sqlBuffer := newSQLBuffer(is.db.adapter, len(selectSQL)+128, len(selectArgs))
+
+	sqlBuffer.Write(is.verb)
+	sqlBuffer.writeInto(is.intoTable)
+	sqlBuffer.Write(" (")
+	sqlBuffer.writeColumns(columns)
+	sqlBuffer.Write(") ")
+	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningSQLServer, false)
+	sqlBuffer.Write(selectSQL, selectArgs...)
+	sqlBuffer.writeReturningForPosition(is.returningColumns, adapters.ReturningPostgreSQL, false)
+	sqlBuffer.writeStringsWithSpaces(is.suffixes)
+
+	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
+}
+
+// queryInfo describes the statement for the errors.
+func (is *InsertStatement) queryInfo() queryInfo {
+	return queryInfo{op: strings.TrimSpace(is.verb), table: is.intoTable}
+}
+
 // Do executes the builded INSERT statement and returns the creadted 'id' if
-// the adapter does not implement InsertReturningSuffixer.
+// the driver supports LastInsertId (otherwise it's zero with adapters
+// supporting the RETURNING clause).
 func (is *InsertStatement) Do() (int64, error) {
 	query, args, err := is.ToSQL()
 	if err != nil {
 		return 0, err
 	}
 
-	result, err := is.db.do(query, args)
+	result, err := is.db.do(is.queryInfo(), query, args)
 	if err != nil {
 		return 0, err
 	}
 
 	// Return the created 'Id' (if available)
-	_, ok := is.db.adapter.(adapters.ReturningBuilder)
-	if ok {
-		// adapters with ReturningSuffixer does not use LastInsertId()
+	lastInsertID, err := result.LastInsertId()
+	if is.db.Capabilities().Returning != adapters.ReturningNone && err != nil {
+		// adapters supporting RETURNING may not support LastInsertId()
 		return 0, nil
 	}
-	lastInsertID, err := result.LastInsertId()
 	return lastInsertID, err
 }
 
@@ -121,5 +284,5 @@ func (is *InsertStatement) doWithReturning(recordDescription *recordDescription,
 		return 0, err
 	}
 
-	return is.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter)
+	return is.db.doSelectOrWithReturning(is.queryInfo(), query, args, recordDescription, pointersGetter)
 }
@@ -3,6 +3,7 @@ package godb
 import (
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/sqlite"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -142,3 +143,131 @@ func TestDoInsert(t *testing.T) {
 		})
 	})
 }
+
+func TestInsertClone(t *testing.T) {
+	Convey("Given an insert statement", t, func() {
+		db := &DB{}
+		q := db.InsertInto("dummies").
+			Columns("foo", "bar").
+			Values(1, 2)
+
+		Convey("Changes to the clone does not alter the original", func() {
+			clone := q.Clone().Values(3, 4)
+			sql, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "INSERT INTO dummies (foo, bar) VALUES (?, ?)")
+			So(len(args), ShouldEqual, 2)
+			_, cloneArgs, err := clone.ToSQL()
+			So(err, ShouldBeNil)
+			So(len(cloneArgs), ShouldEqual, 4)
+		})
+	})
+}
+
+func TestInsertFromSelect(t *testing.T) {
+	Convey("Given an insert statement with a select statement", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+		selectStatement := db.SelectFrom("dummies").
+			Columns("a_text", "an_integer").
+			Where("an_integer > ?", 10)
+		q := db.InsertInto("others").
+			Columns("a_text", "an_integer").
+			FromSelect(selectStatement)
+
+		Convey("ToSQL merges the statements and their arguments", func() {
+			sql, args, err := q.Suffix("ON CONFLICT DO NOTHING").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "INSERT INTO others (a_text, an_integer) SELECT a_text, an_integer FROM dummies WHERE an_integer > ? ON CONFLICT DO NOTHING")
+			So(args, ShouldResemble, []interface{}{10})
+		})
+
+		Convey("The select statement is copied", func() {
+			selectStatement.Where("a_text <> ?", "")
+			_, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(args, ShouldResemble, []interface{}{10})
+		})
+
+		Convey("Values can't be mixed with FromSelect", func() {
+			_, _, err := q.Values("foo", 1).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Select errors are reported", func() {
+			_, _, err := db.InsertInto("others").
+				Columns("a_text").
+				FromSelect(db.SelectFrom("dummies")).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Do copies the selected rows", func() {
+			_, err := db.InsertInto("dummies").
+				Columns("a_text", "another_text", "an_integer").
+				FromSelect(db.SelectFrom("dummies").
+					Columns("another_text", "a_text", "an_integer + 100").
+					Where("an_integer < ?", 13)).
+				Do()
+			So(err, ShouldBeNil)
+
+			count, err := db.SelectFrom("dummies").Where("an_integer > ?", 100).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+	})
+}
+
+func TestInsertValuesFromStruct(t *testing.T) {
+	Convey("Given an insert statement", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+
+		Convey("ValuesFromStruct uses the non auto columns of the struct", func() {
+			dummy := Dummy{AText: "foo", AnotherText: "bar", AnInteger: 12}
+			sql, args, err := db.InsertInto("dummies").ValuesFromStruct(&dummy).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `INSERT INTO dummies ("a_text", "another_text", "an_integer", "a_nullable_string", "version") VALUES (?, ?, ?, ?, ?)`)
+			So(args[:3], ShouldResemble, []interface{}{"foo", "bar", 12})
+		})
+
+		Convey("ValuesFromSlice adds a row for each struct", func() {
+			dummies := []Dummy{{AText: "foo"}, {AText: "bar"}}
+			sql, args, err := db.InsertInto("dummies").ValuesFromSlice(&dummies).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `INSERT INTO dummies ("a_text", "another_text", "an_integer", "a_nullable_string", "version") VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)`)
+			So(len(args), ShouldEqual, 10)
+			So(args[0], ShouldEqual, "foo")
+			So(args[5], ShouldEqual, "bar")
+		})
+
+		Convey("Structs and slices are checked", func() {
+			dummies := []Dummy{{AText: "foo"}}
+			_, _, err := db.InsertInto("dummies").ValuesFromStruct(&dummies).ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.InsertInto("dummies").ValuesFromSlice(&Dummy{}).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("ValuesFromStruct uses only the fields of the given columns", func() {
+			dummy := Dummy{AText: "foo", AnotherText: "bar", AnInteger: 12}
+			sql, args, err := db.InsertInto("dummies").
+				Columns("an_integer", `"a_text"`).
+				ValuesFromStruct(&dummy).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `INSERT INTO dummies (an_integer, "a_text") VALUES (?, ?)`)
+			So(args, ShouldResemble, []interface{}{12, "foo"})
+		})
+
+		Convey("The given columns have to be non auto columns of the struct", func() {
+			_, _, err := db.InsertInto("dummies").Columns("unknown").ValuesFromStruct(&Dummy{}).ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.InsertInto("dummies").Columns("id").ValuesFromStruct(&Dummy{}).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -15,6 +15,9 @@ type Iterator interface {
 
 // iteratorInternals is the Iterator implementation (hidden)
 type iteratorInternals struct {
+	db         *DB
+	info       queryInfo
+	query      string
 	rows       *sql.Rows
 	recordInfo *recordDescription
 	columns    []string
@@ -46,21 +49,21 @@ func (i *iteratorInternals) Scan(record interface{}) error {
 		return err
 	}
 
-	return i.rows.Scan(pointers...)
+	return i.db.queryError(i.info, i.query, i.rows.Scan(pointers...))
 }
 
 //Scanx scans record values to destination columns
 func (i *iteratorInternals) Scanx(dest ...interface{}) error {
-	return i.rows.Scan(dest...)
+	return i.db.queryError(i.info, i.query, i.rows.Scan(dest...))
 }
 
 // Close frees ressources created by the request execution.
 func (i *iteratorInternals) Close() error {
-	return i.rows.Close()
+	return i.db.queryError(i.info, i.query, i.rows.Close())
 }
 
 // Err returns the error that was encountered during iteration, or nil.
 // Always check Err after an iteration, like with the standard sql.Err method.
 func (i *iteratorInternals) Err() error {
-	return i.rows.Err()
+	return i.db.queryError(i.info, i.query, i.rows.Err())
 }
@@ -0,0 +1,340 @@
+package godb
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// KeysetQuery is a keyset (or cursor) paginated SELECT statement.
+// Initialize it with the SelectStatement.Paginate method.
+//
+// Example :
+//
+// 	books := make([]Book, 0)
+// 	page, err := db.SelectFrom("books").
+// 		Where("author = ?", author).
+// 		Paginate([]string{"published DESC", "id"}, cursor, 20).
+// 		Do(&books)
+type KeysetQuery struct {
+	selectStatement *SelectStatement
+	order           []keysetColumn
+	cursor          string
+	pageSize        int
+	error           error
+}
+
+// KeysetPage describes a page fetched with keyset pagination. The cursors
+// are opaque strings, give them back to Paginate to get the next or previous
+// page. A cursor is empty if there is no such page.
+type KeysetPage struct {
+	NextCursor     string
+	PreviousCursor string
+	HasNext        bool
+	HasPrevious    bool
+}
+
+// keysetColumn is a column used to order and seek the rows.
+type keysetColumn struct {
+	name string
+	desc bool
+}
+
+// keysetCursor is the content of an encoded cursor. The values are converted
+// to driver values (int64, float64, bool, []byte, string, time.Time or nil),
+// whatever the type of the keys fields.
+type keysetCursor struct {
+	Backward bool
+	Values   []interface{}
+}
+
+func init() {
+	// The only driver value type which is not registered by default
+	gob.Register(time.Time{})
+}
+
+// Paginate returns a keyset paginated query.
+//
+// The orderColumns are the columns names (with an optional ASC or DESC
+// direction) ordering the rows, they must identify a row in a unique way
+// (add the key as last column if needed) and must not be null.
+// The cursor is empty for the first page, otherwise it's a cursor returned
+// by a previous page.
+//
+// Instead of skipping rows with an OFFSET clause, the query seeks the rows
+// following (or preceding) the cursor with a predicate like (a, b) > (?, ?),
+// or with an equivalent chain of OR for mixed directions and databases not
+// supporting row values comparison. The statement must not have an ORDER BY
+// clause or an offset, the page is not fetched otherwise.
+func (ss *SelectStatement) Paginate(orderColumns []string, cursor string, pageSize int) *KeysetQuery {
+	kq := &KeysetQuery{
+		selectStatement: ss,
+		cursor:          cursor,
+		pageSize:        pageSize,
+	}
+	kq.order, kq.error = parseKeysetColumns(orderColumns)
+	if kq.error == nil && pageSize < 1 {
+		kq.error = fmt.Errorf("invalid page size : %d", pageSize)
+	}
+	return kq
+}
+
+// Do executes the paginated query. The record argument has to be a pointer
+// to a slice, which is reset before being filled with the page rows.
+// If no columns is defined for the select statement, all columns are added
+// from record parameter's struct.
+func (kq *KeysetQuery) Do(record interface{}) (*KeysetPage, error) {
+	if kq.error != nil {
+		return nil, kq.error
+	}
+	if kq.selectStatement.error != nil {
+		return nil, kq.selectStatement.error
+	}
+
+	recordInfo, err := buildRecordDescription(record)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := kq.selectStatement.Clone()
+	pointersGetter := statement.prepareColumns(recordInfo)
+	return statement.doKeysetPage(recordInfo, pointersGetter, kq.order, kq.cursor, kq.pageSize)
+}
+
+// Paginate executes the select statement with keyset pagination, see
+// SelectStatement.Paginate. The record given to Select has to be a slice,
+// it will contain the page rows.
+func (ss *StructSelect) Paginate(orderColumns []string, cursor string, pageSize int) (*KeysetPage, error) {
+	if ss.error != nil {
+		return nil, ss.error
+	}
+
+	order, err := parseKeysetColumns(orderColumns)
+	if err != nil {
+		return nil, err
+	}
+	if pageSize < 1 {
+		return nil, fmt.Errorf("invalid page size : %d", pageSize)
+	}
+
+	statement := ss.statementWithColumns()
+	f := func(record interface{}, columns []string) ([]interface{}, error) {
+		pointers := ss.recordDescription.structMapping.GetAllFieldsPointers(record)
+		return pointers, nil
+	}
+
+	return statement.doKeysetPage(ss.recordDescription, f, order, cursor, pageSize)
+}
+
+// doKeysetPage adds the seek predicate, ordering and limit to the statement,
+// executes it, and builds the cursors from the first and last rows.
+// It changes the statement, use it on a clone.
+func (ss *SelectStatement) doKeysetPage(recordInfo *recordDescription, pointersGetter pointersGetter, order []keysetColumn, cursor string, pageSize int) (*KeysetPage, error) {
+	if !recordInfo.isSlice {
+		return nil, fmt.Errorf("keyset pagination needs a slice")
+	}
+	// The rows have to be ordered by the keyset columns only, and the pages
+	// are found by the seek predicate
+	if len(ss.orderBy) > 0 || ss.offset != nil {
+		return nil, fmt.Errorf("keyset pagination can't be used with ORDER BY or OFFSET")
+	}
+
+	var current keysetCursor
+	if cursor != "" {
+		var err error
+		current, err = decodeKeysetCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if len(current.Values) != len(order) {
+			return nil, fmt.Errorf("the cursor does not match the order columns")
+		}
+		ss.WhereQ(ss.keysetCondition(order, current))
+	}
+
+	for _, column := range order {
+		// Backward pages are read in reverse order, then reversed
+		if column.desc != current.Backward {
+			ss.OrderBy(ss.db.quote(column.name) + " DESC")
+		} else {
+			ss.OrderBy(ss.db.quote(column.name))
+		}
+	}
+
+	// One more row to know if there is a following page
+	ss.Limit(pageSize + 1)
+	// Some DB require an offset if a limit is specified (MS SQL Server)
+	ss.Offset(0)
+
+	// Always grow the slice from an empty one
+	recordInfo.truncate(0)
+	if err := ss.do(recordInfo, pointersGetter); err != nil {
+		return nil, err
+	}
+
+	hasMore := recordInfo.len() > pageSize
+	recordInfo.truncate(pageSize)
+	if current.Backward {
+		recordInfo.reverse()
+	}
+
+	page := &KeysetPage{}
+	if current.Backward {
+		page.HasNext = true
+		page.HasPrevious = hasMore
+	} else {
+		page.HasNext = hasMore
+		page.HasPrevious = cursor != ""
+	}
+
+	length := recordInfo.len()
+	if length == 0 {
+		return page, nil
+	}
+
+	var err error
+	if page.HasNext {
+		page.NextCursor, err = buildKeysetCursor(recordInfo, length-1, order, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if page.HasPrevious {
+		page.PreviousCursor, err = buildKeysetCursor(recordInfo, 0, order, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// keysetCondition builds the predicate seeking the rows after (or before)
+// the cursor.
+func (ss *SelectStatement) keysetCondition(order []keysetColumn, cursor keysetCursor) *Condition {
+	// The operator for each column, according to its direction
+	operators := make([]string, len(order))
+	sameDirection := true
+	for i, column := range order {
+		if column.desc != cursor.Backward {
+			operators[i] = " < "
+		} else {
+			operators[i] = " > "
+		}
+		sameDirection = sameDirection && column.desc == order[0].desc
+	}
+
+	rowValueComparison := ss.db.Capabilities().RowValueComparison
+
+	quotedNames := make([]string, len(order))
+	for i, column := range order {
+		quotedNames[i] = ss.db.quote(column.name)
+	}
+
+	// (a, b) > (?, ?)
+	if len(order) == 1 || (sameDirection && rowValueComparison) {
+		sql := "(" + strings.Join(quotedNames, ", ") + ")" +
+			operators[0] +
+			string(buildGroupOfPlaceholders(len(order)).Bytes())
+		return &Condition{sql: sql, args: cursor.Values}
+	}
+
+	// (a > ?) OR (a = ? AND b > ?) OR ...
+	buffer := bytes.NewBuffer(make([]byte, 0, 32*len(order)*len(order)))
+	args := make([]interface{}, 0, len(order)*(len(order)+1)/2)
+	buffer.WriteString("(")
+	for i := range order {
+		if i > 0 {
+			buffer.WriteString(" OR ")
+		}
+		buffer.WriteString("(")
+		for j := 0; j < i; j++ {
+			buffer.WriteString(quotedNames[j] + " = " + Placeholder + " AND ")
+			args = append(args, cursor.Values[j])
+		}
+		buffer.WriteString(quotedNames[i] + operators[i] + Placeholder)
+		args = append(args, cursor.Values[i])
+		buffer.WriteString(")")
+	}
+	buffer.WriteString(")")
+
+	return &Condition{sql: buffer.String(), args: args}
+}
+
+// parseKeysetColumns parses columns like "foo", "foo ASC" or "foo DESC".
+func parseKeysetColumns(orderColumns []string) ([]keysetColumn, error) {
+	if len(orderColumns) == 0 {
+		return nil, fmt.Errorf("keyset pagination needs at least one order column")
+	}
+
+	order := make([]keysetColumn, 0, len(orderColumns))
+	for _, orderColumn := range orderColumns {
+		parts := strings.Fields(orderColumn)
+		column := keysetColumn{}
+		switch {
+		case len(parts) == 1:
+			column.name = parts[0]
+		case len(parts) == 2 && strings.EqualFold(parts[1], "ASC"):
+			column.name = parts[0]
+		case len(parts) == 2 && strings.EqualFold(parts[1], "DESC"):
+			column.name = parts[0]
+			column.desc = true
+		default:
+			return nil, fmt.Errorf("invalid order column : %s", orderColumn)
+		}
+		order = append(order, column)
+	}
+
+	return order, nil
+}
+
+// buildKeysetCursor builds an encoded cursor with the order columns values
+// of the record having the given index. The qualified columns names (ie
+// "b.id") are resolved without their qualifier.
+func buildKeysetCursor(recordInfo *recordDescription, index int, order []keysetColumn, backward bool) (string, error) {
+	names := make([]string, len(order))
+	for i, column := range order {
+		names[i] = column.name[strings.LastIndex(column.name, ".")+1:]
+	}
+
+	pointers, err := recordInfo.structMapping.GetPointersForColumns(recordInfo.index(index), names...)
+	if err != nil {
+		return "", err
+	}
+
+	cursor := keysetCursor{
+		Backward: backward,
+		Values:   make([]interface{}, 0, len(pointers)),
+	}
+	for i, pointer := range pointers {
+		value, err := driver.DefaultParameterConverter.ConvertValue(reflect.ValueOf(pointer).Elem().Interface())
+		if err != nil {
+			return "", fmt.Errorf("the value of the order column %s can't be used in a cursor : %v", order[i].name, err)
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(cursor); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// decodeKeysetCursor decodes a cursor built by buildKeysetCursor.
+func decodeKeysetCursor(encodedCursor string) (keysetCursor, error) {
+	cursor := keysetCursor{}
+	data, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return cursor, fmt.Errorf("invalid cursor : %v", err)
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cursor); err != nil {
+		return cursor, fmt.Errorf("invalid cursor : %v", err)
+	}
+	return cursor, nil
+}
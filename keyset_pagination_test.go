@@ -0,0 +1,195 @@
+package godb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKeysetCondition(t *testing.T) {
+	Convey("Given a select statement", t, func() {
+		cursor := keysetCursor{Values: []interface{}{12, 2}}
+		order := []keysetColumn{{name: "an_integer"}, {name: "id"}}
+
+		Convey("keysetCondition uses row values comparison by default", func() {
+			db := &DB{adapter: sqlite.Adapter}
+			condition := db.SelectFrom("dummies").keysetCondition(order, cursor)
+			So(condition.sql, ShouldEqual, `("an_integer", "id") > (?, ?)`)
+			So(condition.args, ShouldResemble, []interface{}{12, 2})
+		})
+
+		Convey("keysetCondition inverts the comparison for backward cursors", func() {
+			db := &DB{adapter: sqlite.Adapter}
+			backward := keysetCursor{Backward: true, Values: cursor.Values}
+			condition := db.SelectFrom("dummies").keysetCondition(order, backward)
+			So(condition.sql, ShouldEqual, `("an_integer", "id") < (?, ?)`)
+		})
+
+		Convey("keysetCondition uses OR chains for mixed directions", func() {
+			db := &DB{adapter: sqlite.Adapter}
+			mixed := []keysetColumn{{name: "an_integer", desc: true}, {name: "id"}}
+			condition := db.SelectFrom("dummies").keysetCondition(mixed, cursor)
+			So(condition.sql, ShouldEqual, `(("an_integer" < ?) OR ("an_integer" = ? AND "id" > ?))`)
+			So(condition.args, ShouldResemble, []interface{}{12, 12, 2})
+		})
+
+		Convey("keysetCondition uses OR chains if the adapter does not support row values", func() {
+			db := &DB{adapter: mssql.Adapter}
+			condition := db.SelectFrom("dummies").keysetCondition(order, cursor)
+			So(condition.sql, ShouldEqual, `(([an_integer] > ?) OR ([an_integer] = ? AND [id] > ?))`)
+		})
+	})
+}
+
+func TestParseKeysetColumns(t *testing.T) {
+	Convey("parseKeysetColumns accepts optional directions", t, func() {
+		order, err := parseKeysetColumns([]string{"foo", "bar asc", "baz DESC"})
+		So(err, ShouldBeNil)
+		So(order, ShouldResemble, []keysetColumn{{name: "foo"}, {name: "bar"}, {name: "baz", desc: true}})
+	})
+
+	Convey("parseKeysetColumns rejects invalid columns", t, func() {
+		_, err := parseKeysetColumns([]string{"foo sideways"})
+		So(err, ShouldNotBeNil)
+		_, err = parseKeysetColumns(nil)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestPaginate(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Paginate walks forward and backward through the pages", func() {
+			order := []string{"an_integer DESC", "id"}
+
+			firstPage := make([]Dummy, 0)
+			page, err := db.SelectFrom("dummies").Paginate(order, "", 2).Do(&firstPage)
+			So(err, ShouldBeNil)
+			So(len(firstPage), ShouldEqual, 2)
+			So(firstPage[0].AnInteger, ShouldEqual, 13)
+			So(firstPage[1].AnInteger, ShouldEqual, 12)
+			So(page.HasNext, ShouldBeTrue)
+			So(page.HasPrevious, ShouldBeFalse)
+			So(page.PreviousCursor, ShouldBeEmpty)
+
+			secondPage := make([]Dummy, 0)
+			page, err = db.SelectFrom("dummies").Paginate(order, page.NextCursor, 2).Do(&secondPage)
+			So(err, ShouldBeNil)
+			So(len(secondPage), ShouldEqual, 1)
+			So(secondPage[0].AnInteger, ShouldEqual, 11)
+			So(page.HasNext, ShouldBeFalse)
+			So(page.NextCursor, ShouldBeEmpty)
+			So(page.HasPrevious, ShouldBeTrue)
+
+			previousPage := make([]Dummy, 0)
+			page, err = db.SelectFrom("dummies").Paginate(order, page.PreviousCursor, 2).Do(&previousPage)
+			So(err, ShouldBeNil)
+			So(len(previousPage), ShouldEqual, 2)
+			So(previousPage[0].AnInteger, ShouldEqual, 13)
+			So(previousPage[1].AnInteger, ShouldEqual, 12)
+			So(page.HasNext, ShouldBeTrue)
+			So(page.HasPrevious, ShouldBeFalse)
+		})
+
+		Convey("Paginate works with struct selects", func() {
+			dummies := make([]Dummy, 0)
+			page, err := db.Select(&dummies).Paginate([]string{"id"}, "", 2)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+			So(page.HasNext, ShouldBeTrue)
+
+			page, err = db.Select(&dummies).Paginate([]string{"id"}, page.NextCursor, 2)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "Third")
+			So(page.HasNext, ShouldBeFalse)
+		})
+
+		Convey("Paginate accepts qualified order columns", func() {
+			dummies := make([]Dummy, 0)
+			page, err := db.SelectFrom("dummies").Paginate([]string{"dummies.id"}, "", 2).Do(&dummies)
+			So(err, ShouldBeNil)
+			So(page.HasNext, ShouldBeTrue)
+
+			page, err = db.SelectFrom("dummies").Paginate([]string{"dummies.id"}, page.NextCursor, 2).Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AText, ShouldEqual, "Third")
+		})
+
+		Convey("Paginate returns an error with an invalid cursor", func() {
+			dummies := make([]Dummy, 0)
+			_, err := db.SelectFrom("dummies").Paginate([]string{"id"}, "not a cursor", 2).Do(&dummies)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Paginate returns an error with an ORDER BY clause", func() {
+			dummies := make([]Dummy, 0)
+			_, err := db.SelectFrom("dummies").OrderBy("a_text").Paginate([]string{"id"}, "", 2).Do(&dummies)
+			So(err, ShouldNotBeNil)
+			_, err = db.Select(&dummies).OrderBy("a_text").Paginate([]string{"id"}, "", 2)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Paginate returns an error with an offset", func() {
+			dummies := make([]Dummy, 0)
+			_, err := db.SelectFrom("dummies").Offset(1).Paginate([]string{"id"}, "", 2).Do(&dummies)
+			So(err, ShouldNotBeNil)
+			_, err = db.Select(&dummies).Offset(1).Paginate([]string{"id"}, "", 2)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Paginate needs a slice", func() {
+			dummy := Dummy{}
+			_, err := db.SelectFrom("dummies").Paginate([]string{"id"}, "", 2).Do(&dummy)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+type keysetID int64
+
+type keysetCode [2]byte
+
+func (c keysetCode) Value() (driver.Value, error) {
+	return string(c[:]), nil
+}
+
+type KeysetKeys struct {
+	ID       keysetID      `db:"id"`
+	Nullable sql.NullInt64 `db:"nullable"`
+	Code     keysetCode    `db:"code"`
+}
+
+func TestKeysetCursor(t *testing.T) {
+	Convey("Given a record with keys of various types", t, func() {
+		records := []KeysetKeys{{ID: 3, Nullable: sql.NullInt64{Int64: 7, Valid: true}, Code: keysetCode{'a', 'b'}}}
+		recordInfo, err := buildRecordDescription(&records)
+		So(err, ShouldBeNil)
+		order := []keysetColumn{{name: "k.id"}, {name: "nullable"}, {name: "code"}}
+
+		Convey("The cursor contains the driver values of the keys", func() {
+			encoded, err := buildKeysetCursor(recordInfo, 0, order, true)
+			So(err, ShouldBeNil)
+			cursor, err := decodeKeysetCursor(encoded)
+			So(err, ShouldBeNil)
+			So(cursor.Backward, ShouldBeTrue)
+			So(cursor.Values, ShouldResemble, []interface{}{int64(3), int64(7), "ab"})
+		})
+
+		Convey("A null key is kept in the cursor", func() {
+			records[0].Nullable = sql.NullInt64{}
+			encoded, err := buildKeysetCursor(recordInfo, 0, order, false)
+			So(err, ShouldBeNil)
+			cursor, err := decodeKeysetCursor(encoded)
+			So(err, ShouldBeNil)
+			So(cursor.Values, ShouldResemble, []interface{}{int64(3), nil, "ab"})
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package godb
+
+import (
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// ForUpdate adds a FOR UPDATE clause, locking the selected rows until the end
+// of the current transaction. It's not supported by all databases (see
+// adapters.Capabilities).
+//
+// Example :
+//
+// 	err := db.SelectFrom("jobs").
+// 		Where("state = ?", "pending").
+// 		OrderBy("id").
+// 		Limit(10).
+// 		ForUpdate().
+// 		SkipLocked().
+// 		Do(&jobs)
+func (ss *SelectStatement) ForUpdate() *SelectStatement {
+	if ss.db.Capabilities().Locking == adapters.LockingNone {
+		ss.error = &UnsupportedError{Feature: "FOR UPDATE"}
+		return ss
+	}
+	if ss.lock == "" {
+		ss.lock = "FOR UPDATE"
+	}
+	return ss
+}
+
+// SkipLocked adds SKIP LOCKED to the FOR UPDATE clause, the rows locked by
+// other transactions are ignored. It implies ForUpdate.
+func (ss *SelectStatement) SkipLocked() *SelectStatement {
+	if ss.db.Capabilities().Locking != adapters.LockingSkipLocked {
+		ss.error = &UnsupportedError{Feature: "SKIP LOCKED"}
+		return ss
+	}
+	ss.lock = "FOR UPDATE SKIP LOCKED"
+	return ss
+}
+
+// ForUpdate adds a FOR UPDATE clause (see SelectStatement.ForUpdate).
+func (ss *StructSelect) ForUpdate() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.ForUpdate()
+	return ss
+}
+
+// SkipLocked adds a FOR UPDATE SKIP LOCKED clause (see
+// SelectStatement.SkipLocked).
+func (ss *StructSelect) SkipLocked() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.SkipLocked()
+	return ss
+}
+
+// writeLock writes the locking clause if any.
+func (b *sqlBuffer) writeLock(lock string) *sqlBuffer {
+	if b.Err() != nil || lock == "" {
+		return b
+	}
+
+	b.Write(" ").
+		Write(lock)
+	return b
+}
@@ -0,0 +1,60 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestForUpdate(t *testing.T) {
+	Convey("Given a select statement with a postgresql adapter", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+
+		Convey("FOR UPDATE SKIP LOCKED is after the limit", func() {
+			sql, _, err := db.SelectFrom("jobs").
+				Columns("id").
+				Where("state = ?", "pending").
+				Limit(10).
+				SkipLocked().
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM jobs WHERE state = ? LIMIT ? FOR UPDATE SKIP LOCKED`)
+		})
+
+		Convey("Count ignores the locking clause", func() {
+			sql, _, err := db.SelectFrom("jobs").ForUpdate().countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT COUNT(*) FROM jobs`)
+		})
+	})
+
+	Convey("Given a select statement with mysql adapters", t, func() {
+		Convey("SKIP LOCKED needs a detected MySQL 8 server", func() {
+			db := &DB{adapter: mysql.Adapter}
+			sql, _, err := db.SelectFrom("jobs").Columns("id").ForUpdate().ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM jobs FOR UPDATE`)
+			_, _, err = db.SelectFrom("jobs").Columns("id").SkipLocked().ToSQL()
+			So(err, ShouldNotBeNil)
+
+			adapter, err := mysql.NewServer("8.0.32")
+			So(err, ShouldBeNil)
+			db = &DB{adapter: adapter}
+			sql, _, err = db.SelectFrom("jobs").Columns("id").ForUpdate().SkipLocked().ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM jobs FOR UPDATE SKIP LOCKED`)
+		})
+	})
+
+	Convey("Given a select statement with a sqlite adapter", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+
+		Convey("FOR UPDATE is not supported", func() {
+			_, _, err := db.SelectFrom("jobs").Columns("id").ForUpdate().ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
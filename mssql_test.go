@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/adapters/mssql"
 	"github.com/samonzeweb/godb/dbtests/common"
 
@@ -14,11 +15,15 @@ import (
 )
 
 func fixturesSetupMSSQL(t *testing.T) (*godb.DB, func()) {
+	return fixturesSetupMSSQLWithAdapter(t, mssql.Adapter)
+}
+
+func fixturesSetupMSSQLWithAdapter(t *testing.T, adapter adapters.Adapter) (*godb.DB, func()) {
 	if os.Getenv("GODB_MSSQL") == "" {
 		t.Skip("Don't run SQL Server test, GODB_MSSQL not set")
 	}
 
-	db, err := godb.Open(mssql.Adapter, os.Getenv("GODB_MSSQL"))
+	db, err := godb.Open(adapter, os.Getenv("GODB_MSSQL"))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,105 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// multiTableStyle returns how the UPDATE and DELETE statements use other
+// tables with the adapter.
+func (db *DB) multiTableStyle() adapters.MultiTableStyle {
+	return db.Capabilities().MultiTable
+}
+
+// checkUpdateDeleteLimit returns an error if the ORDER BY or LIMIT clauses
+// of an UPDATE or DELETE statement can't be used with the adapter.
+func (db *DB) checkUpdateDeleteLimit(orderBy []string, limit *int, multiTable bool) error {
+	if len(orderBy) == 0 && limit == nil {
+		return nil
+	}
+
+	if !db.Capabilities().UpdateDeleteLimit {
+		return &UnsupportedError{Feature: "ORDER BY and LIMIT in UPDATE and DELETE statements"}
+	}
+	if multiTable && db.multiTableStyle() == adapters.MultiTableMySQL {
+		return fmt.Errorf("ORDER BY and LIMIT can't be used with other tables in UPDATE and DELETE statements")
+	}
+	return nil
+}
+
+// otherTables returns the other tables of an UPDATE or DELETE statement, the
+// joined ones with their alias.
+func otherTables(fromTables []string, joins []*joinPart) []string {
+	tables := make([]string, 0, len(fromTables)+len(joins))
+	tables = append(tables, fromTables...)
+	for _, join := range joins {
+		if join.as != "" {
+			tables = append(tables, join.tableName+" AS "+join.as)
+		} else {
+			tables = append(tables, join.tableName)
+		}
+	}
+	return tables
+}
+
+// multiTableWhere returns the conditions of the joins followed by the where
+// conditions, all surrounded with parentheses.
+func multiTableWhere(joins []*joinPart, where []*Condition) []*Condition {
+	conditions := make([]*Condition, 0, len(joins)+1)
+	for _, join := range joins {
+		if join.on != nil {
+			conditions = append(conditions, parenthesize(join.on))
+		}
+	}
+	if len(where) > 0 {
+		conditions = append(conditions, parenthesize(And(where...)))
+	}
+	return conditions
+}
+
+// subqueryTarget returns the target table of an UPDATE or DELETE statement
+// using a correlated subquery, with its alias following AS : SQLite rejects
+// an alias without AS in these statements.
+func subqueryTarget(table string) string {
+	name, alias, ok := splitTableAlias(table)
+	if !ok || alias == "" {
+		return table
+	}
+	return name + " AS " + alias
+}
+
+// existsCondition returns a correlated EXISTS condition on the other tables,
+// with the conditions of the joins and the where conditions. It's used when
+// the database can't use other tables in UPDATE and DELETE statements.
+func existsCondition(fromTables []string, joins []*joinPart, where []*Condition) *Condition {
+	sql := "EXISTS (SELECT 1 FROM " + strings.Join(otherTables(fromTables, joins), ", ")
+	conditions := multiTableWhere(joins, where)
+	if len(conditions) == 0 {
+		return &Condition{sql: sql + ")"}
+	}
+
+	condition := And(conditions...)
+	if condition.err != nil {
+		return condition
+	}
+	return &Condition{
+		sql:  sql + " WHERE " + condition.sql + ")",
+		args: condition.args,
+	}
+}
+
+// writeMultiTableJoins writes the other tables of an UPDATE or DELETE
+// statement as joins, the ones without conditions as CROSS JOIN.
+func (b *sqlBuffer) writeMultiTableJoins(fromTables []string, joins []*joinPart) *sqlBuffer {
+	if b.Err() != nil {
+		return b
+	}
+
+	for _, table := range fromTables {
+		b.Write(" CROSS JOIN ").
+			Write(table)
+	}
+	return b.writeJoins(joins)
+}
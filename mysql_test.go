@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/adapters/mysql"
 	"github.com/samonzeweb/godb/dbtests/common"
 
@@ -12,11 +13,15 @@ import (
 )
 
 func fixturesSetupMySQL(t *testing.T) (*godb.DB, func()) {
+	return fixturesSetupMySQLWithAdapter(t, mysql.Adapter)
+}
+
+func fixturesSetupMySQLWithAdapter(t *testing.T, adapter adapters.Adapter) (*godb.DB, func()) {
 	if os.Getenv("GODB_MYSQL") == "" {
 		t.Skip("Don't run MySQL test, GODB_MYSQL not set")
 	}
 
-	db, err := godb.Open(mysql.Adapter, os.Getenv("GODB_MYSQL"))
+	db, err := godb.Open(adapter, os.Getenv("GODB_MYSQL"))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,145 @@
+package godb
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// QNamed builds a condition like Q, but with named parameters like :name or
+// @name. The params are either a map[string]interface{}, or a pointer to a
+// struct whose fields are found by their column names.
+//
+// Example :
+//
+// 	q := godb.QNamed("author = :author AND id IN (:ids)", map[string]interface{}{
+// 		"author": "Tolkien",
+// 		"ids":    []int{1, 2, 3},
+// 	})
+func QNamed(sql string, params interface{}) *Condition {
+	c := qNamed(defaultSyntax, sql, params)
+	c.rebuild = func(syntax adapters.Syntax) *Condition {
+		return qNamed(syntax, sql, params)
+	}
+	return c
+}
+
+// qNamed builds the condition of QNamed, using the given syntax.
+func qNamed(syntax adapters.Syntax, sql string, params interface{}) *Condition {
+	positionalSQL, args, err := bindNamedParameters(syntax, sql, params)
+	if err != nil {
+		return &Condition{err: err}
+	}
+	return buildCondition(syntax, positionalSQL, false, args)
+}
+
+// RawSQLNamed creates a RawSQL like RawSQL, but with named parameters like
+// :name or @name, see QNamed. Unlike RawSQL, the slices arguments are
+// expanded like with Q.
+func (db *DB) RawSQLNamed(sql string, params interface{}) *RawSQL {
+	raw := &RawSQL{db: db}
+	condition := qNamed(db.syntax(), sql, params)
+	if condition.err != nil {
+		raw.error = condition.err
+		return raw
+	}
+
+	raw.sql = condition.sql
+	raw.arguments = condition.args
+	return raw
+}
+
+// bindNamedParameters replaces the named parameters of the sql string with
+// placeholders, and returns their values in the same order.
+// Named parameters in literals and comments are ignored, as well as
+// PostgreSQL casts (::) and SQL Server variables (@@).
+func bindNamedParameters(syntax adapters.Syntax, sql string, params interface{}) (string, []interface{}, error) {
+	values, err := namedValues(params)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, len(sql)))
+	var args []interface{}
+	previousEnd := 0
+	for i := 0; i < len(sql); {
+		if next := syntax.SkipNonCode(sql, i); next != i {
+			i = next
+			continue
+		}
+
+		nameLength := namedParameterLength(sql, i)
+		if nameLength == 0 {
+			i++
+			continue
+		}
+
+		name := sql[i+1 : i+1+nameLength]
+		value, ok := values[name]
+		if !ok {
+			return "", nil, fmt.Errorf("missing value for the named parameter %s", name)
+		}
+		buffer.WriteString(sql[previousEnd:i])
+		buffer.WriteString(Placeholder)
+		args = append(args, value)
+		i += 1 + nameLength
+		previousEnd = i
+	}
+	buffer.WriteString(sql[previousEnd:])
+
+	return buffer.String(), args, nil
+}
+
+// namedParameterLength returns the length of the name of the named parameter
+// starting at the given position, or zero if there is none.
+func namedParameterLength(sql string, position int) int {
+	prefix := sql[position]
+	if prefix != ':' && prefix != '@' {
+		return 0
+	}
+	// Like ::int, @@ROWCOUNT, or a:b
+	if position > 0 && (sql[position-1] == prefix || isNameByte(sql[position-1])) {
+		return 0
+	}
+
+	length := 0
+	for i := position + 1; i < len(sql) && isNameByte(sql[i]); i++ {
+		// The name can't start with a digit
+		if length == 0 && sql[i] >= '0' && sql[i] <= '9' {
+			return 0
+		}
+		length++
+	}
+	return length
+}
+
+// isNameByte returns true if the byte could be a part of a parameter name.
+func isNameByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// namedValues returns the values of named parameters, by name. The params
+// are a map, or a pointer to a struct.
+func namedValues(params interface{}) (map[string]interface{}, error) {
+	if values, ok := params.(map[string]interface{}); ok {
+		return values, nil
+	}
+
+	recordInfo, err := buildRecordDescription(params)
+	if err != nil {
+		return nil, fmt.Errorf("named parameters need a map or a struct pointer : %v", err)
+	}
+	if recordInfo.isSlice {
+		return nil, fmt.Errorf("named parameters need a map or a struct pointer, got a slice")
+	}
+
+	columns := recordInfo.structMapping.GetAllColumnsNames()
+	pointers := recordInfo.structMapping.GetAllFieldsPointers(params)
+	values := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		values[column] = reflect.ValueOf(pointers[i]).Elem().Interface()
+	}
+	return values, nil
+}
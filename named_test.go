@@ -0,0 +1,78 @@
+package godb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQNamed(t *testing.T) {
+	Convey("QNamed binds named parameters from a map", t, func() {
+		q := QNamed("author = :author AND id IN (:ids) AND title <> @author", map[string]interface{}{
+			"author": "Tolkien",
+			"ids":    []int{1, 2},
+		})
+		So(q.err, ShouldBeNil)
+		So(q.sql, ShouldEqual, "author = ? AND id IN (?,?) AND title <> ?")
+		So(q.args, ShouldResemble, []interface{}{"Tolkien", 1, 2, "Tolkien"})
+	})
+
+	Convey("QNamed binds named parameters from a struct", t, func() {
+		dummy := Dummy{AText: "foo", AnInteger: 12}
+		q := QNamed("a_text = :a_text AND an_integer > :an_integer", &dummy)
+		So(q.err, ShouldBeNil)
+		So(q.sql, ShouldEqual, "a_text = ? AND an_integer > ?")
+		So(q.args, ShouldResemble, []interface{}{"foo", 12})
+	})
+
+	Convey("QNamed ignores literals, comments, casts and variables", t, func() {
+		q := QNamed("a = ':a' AND b::int = :b AND c = @@ROWCOUNT /* :c */", map[string]interface{}{"b": 1})
+		So(q.err, ShouldBeNil)
+		So(q.sql, ShouldEqual, "a = ':a' AND b::int = ? AND c = @@ROWCOUNT /* :c */")
+		So(q.args, ShouldResemble, []interface{}{1})
+	})
+
+	Convey("QNamed returns an error", t, func() {
+		Convey("if a parameter is missing", func() {
+			q := QNamed("a = :a", map[string]interface{}{"b": 1})
+			So(q.err, ShouldNotBeNil)
+		})
+
+		Convey("if the parameters are not a map or a struct pointer", func() {
+			q := QNamed("a = :a", 123)
+			So(q.err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestQIgnoresLiterals(t *testing.T) {
+	Convey("Q ignores the placeholders in literals and the escaped ones", t, func() {
+		q := Q("a = '?' AND data ?? 'key' AND b = ?", 1)
+		So(q.err, ShouldBeNil)
+		So(q.sql, ShouldEqual, "a = '?' AND data ?? 'key' AND b = ?")
+		So(q.args, ShouldResemble, []interface{}{1})
+	})
+}
+
+func TestRawSQLNamed(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("RawSQLNamed executes the query with named parameters", func() {
+			dummies := make([]Dummy, 0)
+			err := db.RawSQLNamed(
+				"select * from dummies where an_integer in (:values) and a_text <> '?'",
+				map[string]interface{}{"values": []int{11, 12}},
+			).Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+
+		Convey("RawSQLNamed returns the binding errors", func() {
+			dummies := make([]Dummy, 0)
+			err := db.RawSQLNamed("select * from dummies where an_integer = :value", map[string]interface{}{}).Do(&dummies)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,197 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+
+)
+
+// NullsOrder specifies the position of NULL values in an ORDER BY clause.
+type NullsOrder int
+
+const (
+	// NullsFirst puts NULL values before the others.
+	NullsFirst NullsOrder = iota + 1
+	// NullsLast puts NULL values after the others.
+	NullsLast
+)
+
+// OrderByAsc adds an ascending ORDER BY expression for the given column. The
+// column name is checked and quoted, it's safe to use a (whitelisted) name
+// coming from a query string.
+// The position of NULL values could be given, it's emulated with a CASE
+// expression if the database does not support NULLS FIRST / NULLS LAST.
+//
+// Example :
+//
+// 	err := db.SelectFrom("books").
+// 		OrderByDesc("published", godb.NullsLast).
+// 		OrderByAsc("title").
+// 		Do(&books)
+func (ss *SelectStatement) OrderByAsc(column string, nulls ...NullsOrder) *SelectStatement {
+	return ss.orderByColumn(column, "ASC", nulls)
+}
+
+// OrderByDesc adds a descending ORDER BY expression for the given column,
+// see OrderByAsc.
+func (ss *SelectStatement) OrderByDesc(column string, nulls ...NullsOrder) *SelectStatement {
+	return ss.orderByColumn(column, "DESC", nulls)
+}
+
+// OrderByQ adds an ORDER BY expression with arguments, like
+// Q("CASE WHEN status = ? THEN 0 ELSE 1 END", "urgent").
+func (ss *SelectStatement) OrderByQ(expression *Condition) *SelectStatement {
+	expression = expression.withSyntax(ss.db.syntax())
+	if expression.err != nil {
+		ss.error = expression.err
+		return ss
+	}
+	if err := ss.db.checkFeatures(expression.features); err != nil {
+		ss.error = err
+		return ss
+	}
+
+	ss.orderBy = append(ss.orderBy, resolveExpressions(ss.db.adapter, expression.sql))
+	ss.orderByArgs = append(ss.orderByArgs, expression.args...)
+	return ss
+}
+
+// orderByColumn adds the ORDER BY expressions for the given column, direction
+// and position of NULL values.
+func (ss *SelectStatement) orderByColumn(column string, direction string, nulls []NullsOrder) *SelectStatement {
+	if err := checkIdentifier(column); err != nil {
+		ss.error = err
+		return ss
+	}
+	if len(nulls) > 1 {
+		ss.error = fmt.Errorf("only one position of NULL values is allowed")
+		return ss
+	}
+
+	quotedColumn := ss.db.quote(column)
+	if len(nulls) == 0 {
+		ss.orderBy = append(ss.orderBy, quotedColumn+" "+direction)
+		return ss
+	}
+
+	nullsSupported := ss.db.Capabilities().NullsOrder
+
+	switch {
+	case nulls[0] != NullsFirst && nulls[0] != NullsLast:
+		ss.error = fmt.Errorf("invalid position of NULL values : %d", nulls[0])
+	case nullsSupported && nulls[0] == NullsFirst:
+		ss.orderBy = append(ss.orderBy, quotedColumn+" "+direction+" NULLS FIRST")
+	case nullsSupported:
+		ss.orderBy = append(ss.orderBy, quotedColumn+" "+direction+" NULLS LAST")
+	case nulls[0] == NullsFirst:
+		ss.orderBy = append(ss.orderBy,
+			"CASE WHEN "+quotedColumn+" IS NULL THEN 0 ELSE 1 END",
+			quotedColumn+" "+direction)
+	default:
+		ss.orderBy = append(ss.orderBy,
+			"CASE WHEN "+quotedColumn+" IS NULL THEN 1 ELSE 0 END",
+			quotedColumn+" "+direction)
+	}
+	return ss
+}
+
+// GroupByRollup adds a ROLLUP(columns) element to the GROUP BY clause. The
+// column names are checked and quoted.
+// It returns an error if the database does not support grouping sets.
+func (ss *SelectStatement) GroupByRollup(columns ...string) *SelectStatement {
+	return ss.groupByGroupingSets("ROLLUP", [][]string{columns})
+}
+
+// GroupByCube adds a CUBE(columns) element to the GROUP BY clause, see
+// GroupByRollup.
+func (ss *SelectStatement) GroupByCube(columns ...string) *SelectStatement {
+	return ss.groupByGroupingSets("CUBE", [][]string{columns})
+}
+
+// GroupingSets adds a GROUPING SETS element to the GROUP BY clause, with a
+// set for each given columns list. An empty list is the grand total set.
+//
+// Example :
+//
+// 	ss.GroupingSets([]string{"author", "year"}, []string{"author"}, nil)
+// 	// GROUP BY GROUPING SETS (("author", "year"), ("author"), ())
+func (ss *SelectStatement) GroupingSets(sets ...[]string) *SelectStatement {
+	return ss.groupByGroupingSets("GROUPING SETS", sets)
+}
+
+// groupByGroupingSets adds a GROUP BY element with the given keyword and
+// columns sets.
+func (ss *SelectStatement) groupByGroupingSets(keyword string, sets [][]string) *SelectStatement {
+	if !ss.db.Capabilities().GroupingSets {
+		ss.error = &UnsupportedError{Feature: keyword}
+		return ss
+	}
+
+	groupingSets := len(sets) != 1 || keyword == "GROUPING SETS"
+	if len(sets) == 0 || (!groupingSets && len(sets[0]) == 0) {
+		ss.error = fmt.Errorf("%s needs columns", keyword)
+		return ss
+	}
+
+	parts := make([]string, 0, len(sets))
+	for _, set := range sets {
+		for _, column := range set {
+			if err := checkIdentifier(column); err != nil {
+				ss.error = err
+				return ss
+			}
+		}
+		parts = append(parts, strings.Join(ss.db.quoteAll(set), ", "))
+	}
+
+	if !groupingSets {
+		return ss.GroupBy(keyword + "(" + parts[0] + ")")
+	}
+	return ss.GroupBy(keyword + " ((" + strings.Join(parts, "), (") + "))")
+}
+
+// checkIdentifier returns an error if the given identifier is not a simple
+// or qualified name, like "title" or "books.title".
+func checkIdentifier(identifier string) error {
+	for _, part := range strings.Split(identifier, ".") {
+		if part == "" {
+			return fmt.Errorf("invalid identifier %q", identifier)
+		}
+		for i := 0; i < len(part); i++ {
+			if !isNameByte(part[i]) {
+				return fmt.Errorf("invalid identifier %q", identifier)
+			}
+		}
+	}
+	return nil
+}
+
+// OrderByAsc adds an ascending ORDER BY expression for the given column (see
+// SelectStatement.OrderByAsc).
+func (ss *StructSelect) OrderByAsc(column string, nulls ...NullsOrder) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.OrderByAsc(column, nulls...)
+	return ss
+}
+
+// OrderByDesc adds a descending ORDER BY expression for the given column (see
+// SelectStatement.OrderByDesc).
+func (ss *StructSelect) OrderByDesc(column string, nulls ...NullsOrder) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.OrderByDesc(column, nulls...)
+	return ss
+}
+
+// OrderByQ adds an ORDER BY expression with arguments (see
+// SelectStatement.OrderByQ).
+func (ss *StructSelect) OrderByQ(expression *Condition) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.OrderByQ(expression)
+	return ss
+}
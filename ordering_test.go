@@ -0,0 +1,149 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderByColumns(t *testing.T) {
+	Convey("Given a select statement with a sqlite adapter", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+
+		Convey("OrderByAsc and OrderByDesc quote the columns", func() {
+			sql, _, err := db.SelectFrom("books").
+				Columns("id").
+				OrderByDesc("books.published").
+				OrderByAsc("title").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM books ORDER BY "books"."published" DESC, "title" ASC`)
+		})
+
+		Convey("NULLS FIRST and NULLS LAST are used when supported", func() {
+			sql, _, err := db.SelectFrom("books").
+				Columns("id").
+				OrderByDesc("published", NullsLast).
+				OrderByAsc("title", NullsFirst).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM books ORDER BY "published" DESC NULLS LAST, "title" ASC NULLS FIRST`)
+		})
+
+		Convey("Invalid columns are rejected", func() {
+			_, _, err := db.SelectFrom("books").Columns("id").OrderByAsc("title; DROP TABLE books").ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.SelectFrom("books").Columns("id").OrderByAsc("books.").ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.SelectFrom("books").Columns("id").OrderByAsc(`ti"tle`).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Invalid NULL positions are rejected", func() {
+			_, _, err := db.SelectFrom("books").Columns("id").OrderByAsc("title", NullsFirst, NullsLast).ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.SelectFrom("books").Columns("id").OrderByAsc("title", NullsOrder(0)).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("OrderByQ arguments are after the other arguments, before the limit", func() {
+			sql, args, err := db.SelectFrom("books").
+				Columns("id").
+				Where("author = ?", "Tolkien").
+				OrderByQ(Q("CASE WHEN title = ? THEN 0 ELSE 1 END", "The Hobbit")).
+				OrderByAsc("id").
+				Limit(10).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM books WHERE author = ? ORDER BY CASE WHEN title = ? THEN 0 ELSE 1 END, "id" ASC LIMIT ?`)
+			So(args, ShouldResemble, []interface{}{"Tolkien", "The Hobbit", 10})
+		})
+
+		Convey("Count ignores the OrderByQ arguments", func() {
+			sql, args, err := db.SelectFrom("books").
+				Where("author = ?", "Tolkien").
+				OrderByQ(Q("CASE WHEN title = ? THEN 0 ELSE 1 END", "The Hobbit")).
+				countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT COUNT(*) FROM books WHERE author = ?`)
+			So(args, ShouldResemble, []interface{}{"Tolkien"})
+		})
+
+		Convey("Grouping sets are not supported", func() {
+			_, _, err := db.SelectFrom("books").Columns("author").GroupByRollup("author").ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a select statement with a mysql adapter", t, func() {
+		db := &DB{adapter: mysql.Adapter}
+
+		Convey("NULLS FIRST and NULLS LAST are emulated", func() {
+			sql, _, err := db.SelectFrom("books").
+				Columns("id").
+				OrderByDesc("published", NullsLast).
+				OrderByAsc("title", NullsFirst).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM books ORDER BY CASE WHEN `published` IS NULL THEN 1 ELSE 0 END, `published` DESC, CASE WHEN `title` IS NULL THEN 0 ELSE 1 END, `title` ASC")
+		})
+	})
+
+	Convey("Given a select statement with a postgresql adapter", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+
+		Convey("Rollup, cube and grouping sets quote the columns", func() {
+			sql, _, err := db.SelectFrom("books").
+				Columns("author", "year", "COUNT(*)").
+				GroupBy("country").
+				GroupByRollup("author", "year").
+				GroupByCube("format").
+				GroupingSets([]string{"author", "year"}, []string{"author"}, nil).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT author, year, COUNT(*) FROM books GROUP BY country, ROLLUP("author", "year"), CUBE("format"), GROUPING SETS (("author", "year"), ("author"), ())`)
+		})
+
+		Convey("Rollup needs valid columns", func() {
+			_, _, err := db.SelectFrom("books").Columns("author").GroupByRollup().ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.SelectFrom("books").Columns("author").GroupingSets().ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = db.SelectFrom("books").Columns("author").GroupByCube("a b").ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a select statement with a mssql adapter", t, func() {
+		db := &DB{adapter: mssql.Adapter}
+
+		Convey("Rollup is supported and NULLS ordering emulated", func() {
+			sql, _, err := db.SelectFrom("books").
+				Columns("author", "COUNT(*)").
+				GroupByRollup("author").
+				OrderByAsc("author", NullsLast).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT author, COUNT(*) FROM books GROUP BY ROLLUP([author]) ORDER BY CASE WHEN [author] IS NULL THEN 1 ELSE 0 END, [author] ASC")
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Struct selects are ordered with NULL values last", func() {
+			dummies := make([]Dummy, 0)
+			err := db.Select(&dummies).OrderByAsc("a_nullable_string", NullsLast).OrderByDesc("id").Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[0].AText, ShouldEqual, "Second")
+			So(dummies[1].AText, ShouldEqual, "First")
+			So(dummies[2].AText, ShouldEqual, "Third")
+		})
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/adapters/postgresql"
 	"github.com/samonzeweb/godb/dbtests/common"
 
@@ -13,11 +14,15 @@ import (
 )
 
 func fixturesSetupPostgreSQL(t *testing.T) (*godb.DB, func()) {
+	return fixturesSetupPostgreSQLWithAdapter(t, postgresql.Adapter)
+}
+
+func fixturesSetupPostgreSQLWithAdapter(t *testing.T, adapter adapters.Adapter) (*godb.DB, func()) {
 	if os.Getenv("GODB_POSTGRESQL") == "" {
 		t.Skip("Don't run PostgreSQL test, GODB_POSTGRESQL not set")
 	}
 
-	db, err := godb.Open(postgresql.Adapter, os.Getenv("GODB_POSTGRESQL"))
+	db, err := godb.Open(adapter, os.Getenv("GODB_POSTGRESQL"))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -63,20 +63,16 @@ func (db *DB) getQueryableWithOptions(query string, noTx, noStmtCache bool) (que
 		return &wrapper, nil
 	}
 
-	// Already prepared ?
-	stmt := cache.get(query)
-	if stmt != nil {
-		db.logPrintln("Use cached prepared statement")
-		return stmt, nil
-	}
-
-	// New prepared statement
-	db.logPrintln("Prepare statement and cache it")
-	stmt, err := dbOrTx.Prepare(query)
+	// Already prepared, or new prepared statement
+	stmt, cached, err := cache.getOrPrepare(query, dbOrTx.Prepare)
 	if err != nil {
 		return nil, err
 	}
-	cache.add(query, stmt)
+	if cached {
+		db.logPrintln("Use cached prepared statement")
+	} else {
+		db.logPrintln("Prepare statement and cache it")
+	}
 	return stmt, nil
 }
 
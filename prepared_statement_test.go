@@ -1,7 +1,6 @@
 package godb
 
 import (
-	"database/sql"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -26,19 +25,20 @@ func TestGetQueryable(t *testing.T) {
 				cache.Enable()
 				q, err := db.getQueryable(sqlQuery)
 				So(err, ShouldBeNil)
-				So(q, ShouldHaveSameTypeAs, &sql.Stmt{})
+				So(q, ShouldHaveSameTypeAs, &cachedStmt{})
+				stmt := q.(*cachedStmt).item.stmt
 
 				Convey("getQueryable returns cached prepared statements", func() {
 					q2, err := db.getQueryable(sqlQuery)
 					So(err, ShouldBeNil)
-					So(q2, ShouldEqual, q)
+					So(q2.(*cachedStmt).item.stmt, ShouldEqual, stmt)
 				})
 
 				Convey("getQueryable returns a new prepared statements after a clear cache", func() {
 					cache.Clear()
 					q2, err := db.getQueryable(sqlQuery)
 					So(err, ShouldBeNil)
-					So(q2, ShouldNotEqual, q)
+					So(q2.(*cachedStmt).item.stmt, ShouldNotEqual, stmt)
 				})
 			})
 		}
@@ -0,0 +1,43 @@
+package godb_test
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/puresqlite"
+	"github.com/samonzeweb/godb/dbtests/common"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStatementsPureSQLite(t *testing.T) {
+	Convey("A DB for a SQLite database with the pure Go driver", t, func() {
+		db, teardown := fixturesSetupSQLiteWithAdapter(t, puresqlite.Adapter)
+		defer teardown()
+
+		Convey("The common statements tests must pass", func() {
+			common.StatementsTests(db, t)
+		})
+	})
+}
+
+func TestStructsPureSQLite(t *testing.T) {
+	Convey("A DB for a SQLite database with the pure Go driver", t, func() {
+		db, teardown := fixturesSetupSQLiteWithAdapter(t, puresqlite.Adapter)
+		defer teardown()
+
+		Convey("The common structs tests must pass", func() {
+			common.StructsTests(db, t)
+		})
+	})
+}
+
+func TestRawPureSQLite(t *testing.T) {
+	Convey("A DB for a SQLite database with the pure Go driver", t, func() {
+		db, teardown := fixturesSetupSQLiteWithAdapter(t, puresqlite.Adapter)
+		defer teardown()
+
+		Convey("The common raw tests must pass", func() {
+			common.RawSQLTests(db, t)
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// QueryError is returned when the execution of a statement fails, if
+// UseErrorParser was called. It gives the kind of statement (SELECT, INSERT,
+// UPDATE, DELETE, RAW for the raw queries, or the transaction commands), its
+// main table if any, and the SQL query.
+//
+// The wrapped error is the one returned by the adapter. Use errors.As to get
+// it :
+//
+// 	var uniqueConstraint dberror.UniqueConstraint
+// 	if errors.As(err, &uniqueConstraint) {
+// 		...
+// 	}
+type QueryError struct {
+	Op    string
+	Table string
+	SQL   string
+	Err   error
+}
+
+func (e *QueryError) Error() string {
+	if e.Table == "" {
+		return fmt.Sprintf("%s : %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("%s %s : %v", e.Op, e.Table, e.Err)
+}
+
+// Unwrap returns the error parsed by the adapter.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// queryInfo describes an executed statement, for the errors. The read
+// statements could run on a replica (see OpenCluster).
+type queryInfo struct {
+	op    string
+	table string
+	read  bool
+}
+
+// queryError returns the error of a failed execution, parsed by the adapter
+// and wrapped into a QueryError if UseErrorParser was called. Otherwise the
+// error of the driver is returned unchanged (legacy mode).
+// sql.ErrNoRows is returned as is, it's not really a failure.
+func (db *DB) queryError(info queryInfo, query string, err error) error {
+	if err == nil || err == sql.ErrNoRows || !db.useErrorParser {
+		return err
+	}
+	return &QueryError{Op: info.op, Table: info.table, SQL: query, Err: db.adapter.ParseError(err)}
+}
@@ -0,0 +1,108 @@
+package godb
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/samonzeweb/godb/dberror"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryError(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		insertWithoutText := db.InsertInto("dummies").
+			Columns("another_text", "an_integer").
+			Values("Hello", 42)
+
+		Convey("Without the error parser a failed statement returns the driver error", func() {
+			_, err := insertWithoutText.Do()
+			var queryError *QueryError
+			So(errors.As(err, &queryError), ShouldBeFalse)
+			_, ok := err.(sqlite3.Error)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("With the error parser the QueryError wraps the parsed error", func() {
+			db.UseErrorParser()
+
+			Convey("giving the statement", func() {
+				_, err := insertWithoutText.Do()
+				var queryError *QueryError
+				So(errors.As(err, &queryError), ShouldBeTrue)
+				So(queryError.Op, ShouldEqual, "INSERT")
+				So(queryError.Table, ShouldEqual, "dummies")
+				So(queryError.SQL, ShouldStartWith, "INSERT INTO dummies")
+				So(err.Error(), ShouldStartWith, "INSERT dummies : ")
+			})
+
+			Convey("for statements executed with Exec", func() {
+				_, err := insertWithoutText.Do()
+				var notNull dberror.NotNullConstraint
+				So(errors.As(err, &notNull), ShouldBeTrue)
+				So(notNull.Column, ShouldEqual, "a_text")
+			})
+
+			Convey("for statements executed with Query", func() {
+				dummies := make([]Dummy, 0)
+				err := db.SelectFrom("unknown").Do(&dummies)
+				var queryError *QueryError
+				So(errors.As(err, &queryError), ShouldBeTrue)
+				So(queryError.Op, ShouldEqual, "SELECT")
+				So(queryError.Table, ShouldEqual, "unknown")
+			})
+
+			Convey("for counts", func() {
+				_, err := db.SelectFrom("unknown").Count()
+				var queryError *QueryError
+				So(errors.As(err, &queryError), ShouldBeTrue)
+				So(queryError.Op, ShouldEqual, "SELECT")
+			})
+
+			Convey("for iterators", func() {
+				_, err := db.RawSQL("SELECT * FROM unknown").DoWithIterator()
+				var queryError *QueryError
+				So(errors.As(err, &queryError), ShouldBeTrue)
+				So(queryError.Op, ShouldEqual, "RAW")
+				So(queryError.Table, ShouldEqual, "")
+			})
+
+			Convey("for the closing of iterators", func() {
+				iter, err := db.RawSQL("SELECT * FROM dummies").DoWithIterator()
+				So(err, ShouldBeNil)
+				So(iter.Close(), ShouldBeNil)
+			})
+
+			Convey("for commits", func() {
+				_, err := db.CurrentDB().Exec(`
+					PRAGMA foreign_keys = ON;
+					CREATE TABLE parents (id INTEGER PRIMARY KEY);
+					CREATE TABLE children (parent_id INTEGER REFERENCES parents(id) DEFERRABLE INITIALLY DEFERRED);`)
+				So(err, ShouldBeNil)
+				So(db.Begin(), ShouldBeNil)
+				_, err = db.InsertInto("children").Columns("parent_id").Values(1).Do()
+				So(err, ShouldBeNil)
+
+				err = db.Commit()
+				var queryError *QueryError
+				So(errors.As(err, &queryError), ShouldBeTrue)
+				So(queryError.Op, ShouldEqual, "COMMIT")
+				var foreignKey dberror.ForeignKeyConstraint
+				So(errors.As(err, &foreignKey), ShouldBeTrue)
+			})
+		})
+
+		Convey("sql.ErrNoRows is not wrapped", func() {
+			dummy := Dummy{}
+			err := db.SelectFrom("dummies").Where("id = ?", -1).Do(&dummy)
+			So(err, ShouldEqual, sql.ErrNoRows)
+			var count int
+			err = db.SelectFrom("dummies").Columns("id").Where("id = ?", -1).Scanx(&count)
+			So(err, ShouldEqual, sql.ErrNoRows)
+		})
+	})
+}
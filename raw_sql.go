@@ -1,6 +1,9 @@
 package godb
 
-import "database/sql"
+import (
+	"database/sql"
+	"slices"
+)
 
 // RawSQL allows the execution of a custom SQL query.
 // Initialize it with the RawSQL method.
@@ -13,8 +16,10 @@ import "database/sql"
 // later evolutions without breaking the it.
 type RawSQL struct {
 	db        *DB
+	error     error
 	sql       string
 	arguments []interface{}
+	onReplica bool
 }
 
 // RawSQL create a RawSQL structure, allowing the executing of a custom sql
@@ -27,11 +32,22 @@ func (db *DB) RawSQL(sql string, args ...interface{}) *RawSQL {
 	}
 }
 
+// Clone returns a copy of the raw query, with its own arguments.
+func (raw *RawSQL) Clone() *RawSQL {
+	clone := *raw
+	clone.arguments = slices.Clone(raw.arguments)
+	return &clone
+}
+
 // Do executes the raw query.
 // The record argument has to be a pointer to a struct or a slice.
 // If the argument is not a slice, a row is expected, and Do returns
 // sql.ErrNoRows is none where found.
 func (raw *RawSQL) Do(record interface{}) error {
+	if raw.error != nil {
+		return raw.error
+	}
+
 	recordInfo, err := buildRecordDescription(record)
 	if err != nil {
 		return err
@@ -44,7 +60,7 @@ func (raw *RawSQL) Do(record interface{}) error {
 		return pointers, err
 	}
 
-	rowsCount, err := raw.db.doSelectOrWithReturning(raw.sql, raw.arguments, recordInfo, pointersGetter)
+	rowsCount, err := raw.db.doSelectOrWithReturning(raw.queryInfo(), raw.sql, raw.arguments, recordInfo, pointersGetter)
 	if err != nil {
 		return err
 	}
@@ -63,5 +79,15 @@ func (raw *RawSQL) Do(record interface{}) error {
 // Warning : it does not use an existing transation to avoid some pitfalls with
 // drivers, nor the prepared statement.
 func (raw *RawSQL) DoWithIterator() (Iterator, error) {
-	return raw.db.doWithIterator(raw.sql, raw.arguments)
+	if raw.error != nil {
+		return nil, raw.error
+	}
+
+	return raw.db.doWithIterator(raw.queryInfo(), raw.sql, raw.arguments)
+}
+
+// queryInfo describes the query for the errors, and for the choice of the
+// database in a cluster.
+func (raw *RawSQL) queryInfo() queryInfo {
+	return queryInfo{op: "RAW", read: raw.onReplica}
 }
@@ -73,3 +73,26 @@ func TestRawSQLDoWithIterator(t *testing.T) {
 		})
 	})
 }
+
+func TestRawSQLClone(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Changes to the clone does not alter the original", func() {
+			q := db.RawSQL("select * from dummies where an_integer = ?", 11)
+			clone := q.Clone()
+			clone.arguments[0] = 12
+			So(q.arguments, ShouldResemble, []interface{}{11})
+
+			dummy := Dummy{}
+			err := q.Do(&dummy)
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "First")
+
+			err = clone.Do(&dummy)
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Second")
+		})
+	})
+}
@@ -26,6 +26,13 @@ type tableNamer interface {
 	TableName() string
 }
 
+// SchemaNamer wraps the SchemaName method, allowing a struct to specify the
+// schema of its table, instead of the default schema of the DB (see
+// SetSchema).
+type SchemaNamer interface {
+	SchemaName() string
+}
+
 // buildRecordDescription builds a recordDescription for the given object.
 // Always use a pointer as argument.
 func buildRecordDescription(record interface{}) (*recordDescription, error) {
@@ -138,6 +145,53 @@ func (r *recordDescription) index(i int) interface{} {
 	return v.Addr().Interface()
 }
 
+// truncate shortens the slice of the record to the given length. It does
+// nothing for a single instance or a shorter slice.
+func (r *recordDescription) truncate(length int) {
+	if !r.isSlice || r.len() <= length {
+		return
+	}
+
+	slice := reflect.ValueOf(r.record).Elem()
+	slice.Set(slice.Slice(0, length))
+}
+
+// reverse reverses the order of the slice elements. It does nothing for a
+// single instance.
+func (r *recordDescription) reverse() {
+	if !r.isSlice {
+		return
+	}
+
+	slice := reflect.ValueOf(r.record).Elem()
+	swap := reflect.Swapper(slice.Interface())
+	for i, j := 0, slice.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+// getDefaultScopes returns the default scopes of the record struct, if it
+// implements the defaultScoper interface.
+func (r *recordDescription) getDefaultScopes() []Scope {
+	p := r.getOneInstancePointer()
+	if scoper, ok := p.(defaultScoper); ok {
+		return scoper.DefaultScopes()
+	}
+
+	return nil
+}
+
+// getSchemaName returns the schema name of the record struct if it implements
+// the SchemaNamer interface, or a blank string.
+func (r *recordDescription) getSchemaName() string {
+	p := r.getOneInstancePointer()
+	if namer, ok := p.(SchemaNamer); ok {
+		return namer.SchemaName()
+	}
+
+	return ""
+}
+
 // getTableName returns the table name to use for the current record and
 // if model's TableName() is used to get name it returns true, else false
 func (r *recordDescription) getTableName() (string, bool) {
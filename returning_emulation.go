@@ -0,0 +1,117 @@
+package godb
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The RETURNING clause is emulated for the adapters not supporting it (see
+// adapters.Capabilities) : the auto keys are set with LastInsertId, and the
+// other auto columns are selected again by key, in the same transaction.
+
+// withTransaction calls f with a DB using the current transaction, or a new
+// one committed if f succeeds.
+func (db *DB) withTransaction(f func(db *DB) error) error {
+	if db.CurrentTx() != nil {
+		return f(db)
+	}
+
+	txDB := db.Clone()
+	defer func() {
+		db.addConsumedTime(txDB.ConsumedTime())
+	}()
+	if err := txDB.Begin(); err != nil {
+		return err
+	}
+	if err := f(txDB); err != nil {
+		txDB.Rollback()
+		return err
+	}
+	return txDB.Commit()
+}
+
+// autoColumnsToRefresh returns the auto columns which are not keys, their
+// values have to be selected again after an insert or an update.
+func autoColumnsToRefresh(recordInfo *recordDescription) []string {
+	keyColumns := recordInfo.structMapping.GetKeyColumnsNames()
+	var columns []string
+	for _, column := range recordInfo.structMapping.GetAutoColumnsNames() {
+		if !slices.Contains(keyColumns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// refreshAutoColumns selects the given auto columns of all records of the
+// described slice or struct, using their keys.
+func (db *DB) refreshAutoColumns(quotedTableName string, recordInfo *recordDescription, autoColumns []string) error {
+	keyColumns := recordInfo.structMapping.GetKeyColumnsNames()
+	if len(keyColumns) == 0 {
+		return fmt.Errorf("the object of type %T has no key : ", recordInfo.record)
+	}
+
+	for i := 0; i < recordInfo.len(); i++ {
+		record := recordInfo.index(i)
+		selectStatement := db.SelectFrom(quotedTableName).
+			Columns(db.quoteAll(autoColumns)...).
+			WithoutTenant()
+		keyValues := recordInfo.structMapping.GetKeyFieldsValues(record)
+		for j, column := range keyColumns {
+			selectStatement.Where(db.quote(column)+" = ?", keyValues[j])
+		}
+
+		pointers, err := recordInfo.structMapping.GetPointersForColumns(record, autoColumns...)
+		if err != nil {
+			return err
+		}
+		if err := selectStatement.Scanx(pointers...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasAutoKey returns true if the described records have an auto key.
+func hasAutoKey(recordInfo *recordDescription) bool {
+	autoColumns := recordInfo.structMapping.GetAutoColumnsNames()
+	for _, column := range recordInfo.structMapping.GetKeyColumnsNames() {
+		if slices.Contains(autoColumns, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// insertAndSetAutoKeys runs the insert statement of the described records,
+// and sets their auto key with the id given by LastInsertId.
+// The ids of the rows of a bulk insert can't be deduced from LastInsertId,
+// they're not always consecutive (concurrent inserts, increment other than
+// one, ...). With an auto key, the rows of a slice are inserted one by one,
+// use it inside a transaction.
+func (db *DB) insertAndSetAutoKeys(insertStatement *InsertStatement, recordInfo *recordDescription) error {
+	if recordInfo.isSlice && !hasAutoKey(recordInfo) {
+		_, err := insertStatement.Do()
+		return err
+	}
+
+	allValues := insertStatement.values
+	defer func() {
+		insertStatement.values = allValues
+	}()
+	for i, values := range allValues {
+		insertStatement.values = [][]interface{}{values}
+		insertedID, err := insertStatement.Do()
+		if err != nil {
+			return err
+		}
+		pointerToID, err := recordInfo.structMapping.GetAutoKeyPointer(recordInfo.index(i))
+		if err != nil {
+			return err
+		}
+		if err := setAutoKey(pointerToID, insertedID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
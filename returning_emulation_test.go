@@ -0,0 +1,129 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type ComputedDummy struct {
+	ID       int    `db:"id,key,auto"`
+	AText    string `db:"a_text"`
+	Computed string `db:"computed,auto"`
+}
+
+func (*ComputedDummy) TableName() string {
+	return "computeddummies"
+}
+
+func computedFixturesSetup(t *testing.T) *DB {
+	db := createInMemoryConnection(t)
+
+	createTable :=
+		`create table computeddummies (
+		id                  integer not null primary key autoincrement,
+		a_text              text not null,
+		computed            text not null default 'none');
+
+		create trigger computeinsert
+		after insert
+		on computeddummies
+		begin
+			update computeddummies set computed = 'I_' || NEW.a_text where id = NEW.id;
+		end;
+
+		create trigger computeupdate
+		after update of a_text
+		on computeddummies
+		begin
+			update computeddummies set computed = 'U_' || NEW.a_text where id = NEW.id;
+		end;
+	`
+	if _, err := db.sqlDB.Exec(createTable); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestReturningEmulation(t *testing.T) {
+	Convey("Given a test database without RETURNING clause", t, func() {
+		db := computedFixturesSetup(t)
+		defer db.Close()
+
+		Convey("Insert sets the key and selects the auto columns", func() {
+			dummy := ComputedDummy{AText: "foo"}
+			err := db.Insert(&dummy).Do()
+			So(err, ShouldBeNil)
+			So(dummy.ID, ShouldBeGreaterThan, 0)
+			So(dummy.Computed, ShouldEqual, "I_foo")
+			So(db.CurrentTx(), ShouldBeNil)
+		})
+
+		Convey("BulkInsert sets the keys and selects the auto columns", func() {
+			err := db.Insert(&ComputedDummy{AText: "first"}).Do()
+			So(err, ShouldBeNil)
+			// The ids of the rows of a bulk insert aren't always consecutive
+			_, err = db.sqlDB.Exec(`create trigger insertgap after insert on computeddummies
+				when NEW.a_text <> 'gap'
+				begin
+					insert into computeddummies (a_text) values ('gap');
+				end;`)
+			So(err, ShouldBeNil)
+
+			dummies := []ComputedDummy{{AText: "foo"}, {AText: "bar"}}
+			err = db.BulkInsert(&dummies).Do()
+			So(err, ShouldBeNil)
+			So(dummies[0].ID, ShouldEqual, 2)
+			So(dummies[1].ID, ShouldEqual, 4)
+			So(dummies[0].Computed, ShouldEqual, "I_foo")
+			So(dummies[1].Computed, ShouldEqual, "I_bar")
+		})
+
+		Convey("Update selects the auto columns", func() {
+			dummy := ComputedDummy{AText: "foo"}
+			So(db.Insert(&dummy).Do(), ShouldBeNil)
+
+			dummy.AText = "bar"
+			err := db.Update(&dummy).Do()
+			So(err, ShouldBeNil)
+			So(dummy.Computed, ShouldEqual, "U_bar")
+		})
+
+		Convey("The current transaction is used", func() {
+			So(db.Begin(), ShouldBeNil)
+			dummy := ComputedDummy{AText: "foo"}
+			So(db.Insert(&dummy).Do(), ShouldBeNil)
+			So(dummy.Computed, ShouldEqual, "I_foo")
+			So(db.CurrentTx(), ShouldNotBeNil)
+			So(db.Rollback(), ShouldBeNil)
+
+			count, err := db.SelectFrom("computeddummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given the SQLite adapter using RETURNING", t, func() {
+		db := &DB{adapter: sqlite.ReturningAdapter}
+
+		Convey("Statements have a RETURNING clause", func() {
+			sql, _, err := db.InsertInto("dummies").
+				Columns("a_text").
+				Values("foo").
+				Returning(db.ReturningBuilder(false).FormatForNewValues([]string{"id", "computed"})...).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `INSERT INTO dummies (a_text) VALUES (?) RETURNING "id", "computed" `)
+
+			sql, _, err = db.UpdateTable("dummies").
+				Set("a_text", "foo").
+				Where("id = ?", 1).
+				Returning(`"computed"`).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `UPDATE dummies SET a_text=? WHERE id = ? RETURNING "computed" `)
+		})
+	})
+}
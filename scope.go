@@ -0,0 +1,105 @@
+package godb
+
+import "fmt"
+
+// Scope is a reusable query modifier, like a tenant filter or a
+// published-only view. Apply it with the Scopes method of statements.
+//
+// Example :
+//
+// 	func Published(ss *godb.SelectStatement) *godb.SelectStatement {
+// 		return ss.Where("published_at IS NOT NULL")
+// 	}
+// 	…
+//
+// 	err := db.Select(&books).Scopes(Published).Do()
+//
+// With UPDATE and DELETE statements, scopes can only add conditions.
+type Scope func(*SelectStatement) *SelectStatement
+
+// defaultScoper wraps the DefaultScopes method, allowing a struct to specify
+// scopes always applied by Select, unless Unscoped is called.
+type defaultScoper interface {
+	DefaultScopes() []Scope
+}
+
+// Scopes applies the given scopes to the statement.
+func (ss *SelectStatement) Scopes(scopes ...Scope) *SelectStatement {
+	for _, scope := range scopes {
+		ss = scope(ss)
+	}
+	return ss
+}
+
+// Scopes applies the given scopes to the statement.
+func (ss *StructSelect) Scopes(scopes ...Scope) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.Scopes(scopes...)
+	return ss
+}
+
+// Unscoped disables the default scopes of the struct, if any. The tenant
+// filtering is kept, see WithoutTenant.
+func (ss *StructSelect) Unscoped() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.unscoped = true
+	return ss
+}
+
+// Scopes applies the conditions of the given scopes to the statement.
+func (us *UpdateStatement) Scopes(scopes ...Scope) *UpdateStatement {
+	conditions, err := scopesConditions(us.db, us.updateTable, scopes)
+	if err != nil {
+		us.error = err
+		return us
+	}
+	us.where = append(us.where, conditions...)
+	return us
+}
+
+// Scopes applies the conditions of the given scopes to the statement.
+func (ds *DeleteStatement) Scopes(scopes ...Scope) *DeleteStatement {
+	conditions, err := scopesConditions(ds.db, ds.fromTable, scopes)
+	if err != nil {
+		ds.error = err
+		return ds
+	}
+	ds.where = append(ds.where, conditions...)
+	return ds
+}
+
+// scopesConditions applies the given scopes to an empty select statement, and
+// returns the resulting conditions. It returns an error if a scope changes
+// something else than the WHERE clause.
+func scopesConditions(db *DB, tableName string, scopes []Scope) ([]*Condition, error) {
+	ss := db.SelectFrom(tableName).Scopes(scopes...)
+	if ss.error != nil {
+		return nil, ss.error
+	}
+
+	if ss.distinct ||
+		len(ss.columns) > 0 ||
+		len(ss.columnsArgs) > 0 ||
+		len(ss.columnAliases) > 0 ||
+		len(ss.fromTables) != 1 ||
+		len(ss.joins) > 0 ||
+		len(ss.groupBy) > 0 ||
+		len(ss.having) > 0 ||
+		len(ss.windows) > 0 ||
+		len(ss.orderBy) > 0 ||
+		len(ss.orderByArgs) > 0 ||
+		ss.limit != nil ||
+		ss.offset != nil ||
+		ss.lock != "" ||
+		len(ss.suffixes) > 0 ||
+		ss.withoutTenant ||
+		ss.onPrimary {
+		return nil, fmt.Errorf("scopes can only add conditions to UPDATE and DELETE statements")
+	}
+
+	return ss.where, nil
+}
@@ -0,0 +1,119 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type ScopedDummy struct {
+	Dummy `db:""`
+}
+
+func (*ScopedDummy) TableName() string {
+	return "dummies"
+}
+
+func (*ScopedDummy) DefaultScopes() []Scope {
+	return []Scope{aboveEleven}
+}
+
+func aboveEleven(ss *SelectStatement) *SelectStatement {
+	return ss.Where("an_integer > ?", 11)
+}
+
+func belowThirteen(ss *SelectStatement) *SelectStatement {
+	return ss.Where("an_integer < ?", 13)
+}
+
+func TestScopes(t *testing.T) {
+	Convey("Given a select statement", t, func() {
+		db := &DB{}
+		q := db.SelectFrom("dummies").Columns("id")
+
+		Convey("Scopes applies all scopes", func() {
+			sql, args, err := q.Scopes(aboveEleven, belowThirteen).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM dummies WHERE an_integer > ? AND an_integer < ?")
+			So(args, ShouldResemble, []interface{}{11, 13})
+		})
+	})
+
+	Convey("Given an update statement", t, func() {
+		db := &DB{}
+		q := db.UpdateTable("dummies").Set("a_text", "foo")
+
+		Convey("Scopes adds the scopes conditions", func() {
+			sql, _, err := q.Scopes(aboveEleven).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET a_text=? WHERE an_integer > ?")
+		})
+
+		Convey("Scopes returns an error if a scope does more than adding conditions", func() {
+			ordered := func(ss *SelectStatement) *SelectStatement {
+				return ss.OrderBy("id")
+			}
+			_, _, err := q.Scopes(ordered).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Scopes returns an error if a scope changes the locking or the database", func() {
+			db := &DB{adapter: postgresql.Adapter}
+			scopes := []Scope{
+				func(ss *SelectStatement) *SelectStatement { return ss.ForUpdate() },
+				func(ss *SelectStatement) *SelectStatement { return ss.OnPrimary() },
+				func(ss *SelectStatement) *SelectStatement { return ss.ColumnsQ(Q("? AS foo", 1)) },
+			}
+			for _, scope := range scopes {
+				_, _, err := db.UpdateTable("dummies").Set("a_text", "foo").Scopes(scope).ToSQL()
+				So(err, ShouldNotBeNil)
+				_, _, err = db.DeleteFrom("dummies").Scopes(scope).ToSQL()
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given a delete statement", t, func() {
+		db := &DB{}
+		q := db.DeleteFrom("dummies")
+
+		Convey("Scopes adds the scopes conditions", func() {
+			sql, _, err := q.Scopes(aboveEleven, belowThirteen).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM dummies WHERE an_integer > ? AND an_integer < ?")
+		})
+	})
+}
+
+func TestStructSelectScopes(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Scopes are applied to struct selects", func() {
+			dummies := make([]Dummy, 0)
+			err := db.Select(&dummies).Scopes(belowThirteen).Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+
+		Convey("Default scopes are applied", func() {
+			dummies := make([]ScopedDummy, 0)
+			err := db.Select(&dummies).Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+
+			count, err := db.Select(&dummies).Scopes(belowThirteen).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Unscoped disables the default scopes", func() {
+			dummies := make([]ScopedDummy, 0)
+			err := db.Select(&dummies).Unscoped().Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+	})
+}
@@ -3,6 +3,8 @@ package godb
 import (
 	"database/sql"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/samonzeweb/godb/adapters"
@@ -22,6 +24,7 @@ type SelectStatement struct {
 
 	distinct             bool
 	columns              []string
+	columnsArgs          []interface{}
 	areColumnsFromStruct bool
 	columnAliases        map[string]string
 	fromTables           []string
@@ -29,10 +32,15 @@ type SelectStatement struct {
 	where                []*Condition
 	groupBy              []string
 	having               []*Condition
+	windows              []string
 	orderBy              []string
+	orderByArgs          []interface{}
 	limit                *int
 	offset               *int
+	lock                 string
 	suffixes             []string
+	withoutTenant        bool
+	onPrimary            bool
 }
 
 // joinPart describes a sql JOIN clause.
@@ -49,6 +57,52 @@ func (db *DB) SelectFrom(tableNames ...string) *SelectStatement {
 	return ss.From(tableNames...)
 }
 
+// Clone returns a deep copy of the statement. Use it to build a base
+// statement and extend it without altering the original one.
+//
+// Example :
+// 	activeUsers := db.SelectFrom("users").Where("active = ?", true)
+// 	err := activeUsers.Clone().Where("country = ?", country).Do(&users)
+func (ss *SelectStatement) Clone() *SelectStatement {
+	clone := *ss
+	clone.columns = slices.Clone(ss.columns)
+	clone.columnsArgs = slices.Clone(ss.columnsArgs)
+	clone.columnAliases = maps.Clone(ss.columnAliases)
+	clone.fromTables = slices.Clone(ss.fromTables)
+	clone.joins = cloneJoins(ss.joins)
+	clone.where = cloneConditions(ss.where)
+	clone.groupBy = slices.Clone(ss.groupBy)
+	clone.having = cloneConditions(ss.having)
+	clone.windows = slices.Clone(ss.windows)
+	clone.orderBy = slices.Clone(ss.orderBy)
+	clone.orderByArgs = slices.Clone(ss.orderByArgs)
+	if ss.limit != nil {
+		clone.Limit(*ss.limit)
+	}
+	if ss.offset != nil {
+		clone.Offset(*ss.offset)
+	}
+	clone.suffixes = slices.Clone(ss.suffixes)
+	return &clone
+}
+
+// cloneJoins returns a copy of the given joins list.
+func cloneJoins(joins []*joinPart) []*joinPart {
+	if joins == nil {
+		return nil
+	}
+
+	clones := make([]*joinPart, 0, len(joins))
+	for _, join := range joins {
+		clone := *join
+		if join.on != nil {
+			clone.on = cloneConditions([]*Condition{join.on})[0]
+		}
+		clones = append(clones, &clone)
+	}
+	return clones
+}
+
 // From adds table to the select statement. It can be called multiple times.
 func (ss *SelectStatement) From(tableNames ...string) *SelectStatement {
 	ss.fromTables = append(ss.fromTables, tableNames...)
@@ -66,6 +120,31 @@ func (ss *SelectStatement) Columns(columns ...string) *SelectStatement {
 	return ss
 }
 
+// ColumnsQ adds columns built with arguments, like the aggregate and window
+// functions (see Function), or any expression built with Q. Like Columns, it
+// can't be mixed with ColumnsFromStruct.
+func (ss *SelectStatement) ColumnsQ(columns ...*Condition) *SelectStatement {
+	if ss.areColumnsFromStruct {
+		ss.error = fmt.Errorf("you can't mix Columns and ColumnsFromStruct to build a select query")
+		return ss
+	}
+
+	for _, column := range columns {
+		column = column.withSyntax(ss.db.syntax())
+		if column.err != nil {
+			ss.error = column.err
+			return ss
+		}
+		if err := ss.db.checkFeatures(column.features); err != nil {
+			ss.error = err
+			return ss
+		}
+		ss.columns = append(ss.columns, resolveExpressions(ss.db.adapter, column.sql))
+		ss.columnsArgs = append(ss.columnsArgs, column.args...)
+	}
+	return ss
+}
+
 // ColumnsFromStruct adds columns to select, extrating them from the
 // given struct (or slice of struct). Always use a pointer as argument.
 // You can't mix the use of ColumnsFromStruct and Columns methods.
@@ -118,7 +197,7 @@ func (ss *SelectStatement) addJoin(joinType string, tableName string, as string,
 		joinType:  joinType,
 		tableName: tableName,
 		as:        as,
-		on:        on,
+		on:        on.withSyntax(ss.db.syntax()),
 	}
 	ss.joins = append(ss.joins, join)
 	return ss
@@ -126,16 +205,31 @@ func (ss *SelectStatement) addJoin(joinType string, tableName string, as string,
 
 // Where adds a condition using string and arguments.
 func (ss *SelectStatement) Where(sql string, args ...interface{}) *SelectStatement {
-	return ss.WhereQ(Q(sql, args...))
+	return ss.WhereQ(ss.db.q(sql, args...))
 }
 
 // WhereQ adds a simple or complex predicate generated with Q and
 // conjunctions.
 func (ss *SelectStatement) WhereQ(condition *Condition) *SelectStatement {
-	ss.where = append(ss.where, condition)
+	ss.where = append(ss.where, condition.withSyntax(ss.db.syntax()))
 	return ss
 }
 
+// WhereStruct adds an equality condition for each non zero field of the given
+// struct pointer, using its mapping. Use it to filter with a partially
+// filled struct.
+func (ss *SelectStatement) WhereStruct(record interface{}) *SelectStatement {
+	condition, err := structCondition(record)
+	if err != nil {
+		ss.error = err
+		return ss
+	}
+	if condition == nil {
+		return ss
+	}
+	return ss.WhereQ(condition)
+}
+
 // GroupBy adds a GROUP BY clause. You can call GroupBy multiple times.
 func (ss *SelectStatement) GroupBy(groupBy string) *SelectStatement {
 	ss.groupBy = append(ss.groupBy, groupBy)
@@ -145,16 +239,32 @@ func (ss *SelectStatement) GroupBy(groupBy string) *SelectStatement {
 // Having adds a HAVING clause with a condition build with a sql string and
 // its arguments (like Where).
 func (ss *SelectStatement) Having(sql string, args ...interface{}) *SelectStatement {
-	return ss.HavingQ(Q(sql, args...))
+	return ss.HavingQ(ss.db.q(sql, args...))
 }
 
 // HavingQ adds a simple or complex predicate generated with Q and
 // conjunctions (like WhereQ).
 func (ss *SelectStatement) HavingQ(condition *Condition) *SelectStatement {
+	condition = condition.withSyntax(ss.db.syntax())
+	if err := ss.db.checkFeatures(condition.features); err != nil {
+		ss.error = err
+		return ss
+	}
 	ss.having = append(ss.having, condition)
 	return ss
 }
 
+// Window adds a named window to the WINDOW clause, the window functions
+// reference it with Function.OverWindow.
+func (ss *SelectStatement) Window(name string, spec *WindowSpec) *SelectStatement {
+	if err := ss.db.checkFeatures(windowFunctionsFeature); err != nil {
+		ss.error = err
+		return ss
+	}
+	ss.windows = append(ss.windows, name+" AS ("+spec.sql()+")")
+	return ss
+}
+
 // OrderBy adds an expression for the ORDER BY clause.
 // You can call GroupBy multiple times.
 func (ss *SelectStatement) OrderBy(orderBy string) *SelectStatement {
@@ -189,37 +299,111 @@ func (ss *SelectStatement) ToSQL() (string, []interface{}, error) {
 		return "", nil, ss.error
 	}
 
-	sqlWhereLength, argsWhereLength, err := sumOfConditionsLengths(ss.where)
+	statement := ss.withTenant()
+	sqlBuffer, err := statement.newSQLBuffer()
 	if err != nil {
 		return "", nil, err
 	}
 
-	sqlHavingLength, argsHavingLength, err := sumOfConditionsLengths(ss.having)
+	statement.writeSelect(sqlBuffer, ss.distinct, ss.columns, ss.columnsArgs, true)
+	sqlBuffer.writeStringsWithSpaces(ss.suffixes)
+
+	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
+}
+
+// countToSQL returns a string with the SQL request counting the rows of the
+// statement, the arguments slices, and an error.
+// The ORDER BY, LIMIT and OFFSET clauses are ignored. If the statement
+// has DISTINCT or GROUP BY clauses, it is used as a subquery.
+func (ss *SelectStatement) countToSQL() (string, []interface{}, error) {
+	if ss.error != nil {
+		return "", nil, ss.error
+	}
+
+	statement := ss.withTenant()
+	sqlBuffer, err := statement.newSQLBuffer()
 	if err != nil {
 		return "", nil, err
 	}
 
-	sqlBuffer := newSQLBuffer(
+	if ss.distinct || len(ss.groupBy) > 0 {
+		columns, columnsArgs := ss.columns, ss.columnsArgs
+		if len(columns) == 0 {
+			switch {
+			case ss.distinct && len(ss.groupBy) > 0:
+				return "", nil, fmt.Errorf("the columns are needed to count with DISTINCT and GROUP BY")
+			case ss.distinct:
+				columns = []string{"*"}
+			default:
+				columns = []string{"1"}
+			}
+		}
+		sqlBuffer.Write("SELECT COUNT(*) FROM (")
+		statement.writeSelect(sqlBuffer, ss.distinct, columns, columnsArgs, false)
+		sqlBuffer.writeStringsWithSpaces(ss.suffixes)
+		sqlBuffer.Write(") godb_count")
+	} else {
+		statement.writeSelect(sqlBuffer, false, []string{"COUNT(*)"}, nil, false)
+		sqlBuffer.writeStringsWithSpaces(ss.suffixes)
+	}
+
+	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
+}
+
+// newSQLBuffer creates a buffer sized for the statement.
+func (ss *SelectStatement) newSQLBuffer() (*sqlBuffer, error) {
+	sqlWhereLength, argsWhereLength, err := sumOfConditionsLengths(ss.where)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlHavingLength, argsHavingLength, err := sumOfConditionsLengths(ss.having)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSQLBuffer(
 		ss.db.adapter,
 		sqlWhereLength+sqlHavingLength+64,
 		argsWhereLength+argsHavingLength+4,
-	)
+	), nil
+}
 
+// writeSelect writes the SELECT statement with the given columns and their
+// arguments into the buffer, without the suffixes. The ORDER BY, LIMIT and OFFSET clauses are
+// written only if withOrderAndLimit is true, like the locking clause.
+func (ss *SelectStatement) writeSelect(sqlBuffer *sqlBuffer, distinct bool, columns []string, columnsArgs []interface{}, withOrderAndLimit bool) {
 	sqlBuffer.Write("SELECT ")
 
-	if ss.distinct {
+	if distinct {
 		sqlBuffer.Write("DISTINCT ")
 	}
 
-	sqlBuffer.writeColumns(ss.columns).
-		writeFrom(ss.fromTables...).
+	top := withOrderAndLimit && sqlBuffer.writeTop(ss.limit, ss.offset)
+
+	sqlBuffer.writeColumns(columns).
+		Write("", columnsArgs...)
+	sqlBuffer.writeFrom(ss.fromTables...).
 		writeJoins(ss.joins).
 		writeWhere(ss.where).
 		writeGroupByAndHaving(ss.groupBy, ss.having).
-		writeOrderBy(ss.orderBy)
+		writeWindows(ss.windows)
 
-	offsetFirst := false
-	if limitOffsetOrderer, ok := ss.db.adapter.(adapters.LimitOffsetOrderer); ok {
+	if !withOrderAndLimit {
+		return
+	}
+
+	sqlBuffer.writeOrderBy(ss.orderBy).
+		Write("", ss.orderByArgs...)
+
+	if top {
+		// The limit is already written with TOP
+		sqlBuffer.writeLock(ss.lock)
+		return
+	}
+
+	offsetFirst := ss.db.Capabilities().Pagination == adapters.PaginationOffsetFetch
+	if limitOffsetOrderer, ok := legacyAdapter(ss.db.adapter).(adapters.LimitOffsetOrderer); ok {
 		offsetFirst = limitOffsetOrderer.IsOffsetFirst()
 	}
 	if offsetFirst {
@@ -231,10 +415,17 @@ func (ss *SelectStatement) ToSQL() (string, []interface{}, error) {
 		sqlBuffer.writeLimit(ss.limit).
 			writeOffset(ss.offset)
 	}
+	sqlBuffer.writeLock(ss.lock)
+}
 
-	sqlBuffer.writeStringsWithSpaces(ss.suffixes)
-
-	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
+// queryInfo describes the statement for the errors, and for the choice of
+// the database in a cluster (the locking reads run on the primary).
+func (ss *SelectStatement) queryInfo() queryInfo {
+	info := queryInfo{op: "SELECT", read: !ss.onPrimary && ss.lock == ""}
+	if len(ss.fromTables) > 0 {
+		info.table = ss.fromTables[0]
+	}
+	return info
 }
 
 // Do executes the select statement.
@@ -243,6 +434,7 @@ func (ss *SelectStatement) ToSQL() (string, []interface{}, error) {
 // added from record parameter's struct.
 // If the argument is not a slice, a row is expected, and Do returns
 // sql.ErrNoRows is none where found.
+// The statement itself is not modified, it can be executed again.
 func (ss *SelectStatement) Do(record interface{}) error {
 	if ss.error != nil {
 		return ss.error
@@ -252,6 +444,15 @@ func (ss *SelectStatement) Do(record interface{}) error {
 	if err != nil {
 		return err
 	}
+
+	statement := ss.Clone()
+	return statement.do(recordInfo, statement.prepareColumns(recordInfo))
+}
+
+// prepareColumns adds the columns from the record struct if none were given,
+// applies the aliases, and returns the pointersGetter matching the columns.
+// It changes the statement, use it on a clone.
+func (ss *SelectStatement) prepareColumns(recordInfo *recordDescription) pointersGetter {
 	// If no columns defined for selection, get all columns (SELECT * FROM)
 	if len(ss.columns) == 0 {
 		ss.areColumnsFromStruct = true
@@ -278,11 +479,12 @@ func (ss *SelectStatement) Do(record interface{}) error {
 		return pointers, err
 	}
 
-	return ss.do(recordInfo, f)
+	return f
 }
 
 // do executes the statement and fill the struct or slice given through the
 // recordDescription.
+// It changes the statement, use it on a clone.
 func (ss *SelectStatement) do(recordInfo *recordDescription, pointersGetter pointersGetter) error {
 	if !recordInfo.isSlice {
 		// Only one row is requested
@@ -306,7 +508,7 @@ func (ss *SelectStatement) do(recordInfo *recordDescription, pointersGetter poin
 		return err
 	}
 
-	rowsCount, err := ss.db.doSelectOrWithReturning(sqlQuery, args, recordInfo, pointersGetter)
+	rowsCount, err := ss.db.doSelectOrWithReturning(ss.queryInfo(), sqlQuery, args, recordInfo, pointersGetter)
 	if err != nil {
 		return err
 	}
@@ -326,37 +528,132 @@ func (ss *SelectStatement) Scanx(dest ...interface{}) error {
 	if err != nil {
 		return err
 	}
+
+	return ss.scanx(stmt, args, dest...)
+}
+
+// scanx runs the given query and scans the first row to dest params.
+// Like the other read queries, it runs on a replica if possible.
+func (ss *SelectStatement) scanx(stmt string, args []interface{}, dest ...interface{}) error {
+	if err := ss.db.checkArguments(args); err != nil {
+		return err
+	}
 	stmt = ss.db.replacePlaceholders(stmt)
 
+	if replica := ss.db.replicaFor(ss.queryInfo()); replica != nil {
+		replicaQueryable := &queryWrapper{db: replica.sqlDB, sqlQuery: stmt}
+		err := ss.scanRow(replicaQueryable, replica, time.Now(), stmt, args, dest)
+		if !ss.db.replicaFailed(replica, err) {
+			return ss.db.queryError(ss.queryInfo(), stmt, err)
+		}
+	}
+
 	startTime := time.Now()
 	queryable, err := ss.db.getQueryable(stmt)
 	if err != nil {
 		ss.db.logExecutionErr(err, stmt, args)
-		return err
+		return ss.db.queryError(ss.queryInfo(), stmt, err)
+	}
+	err = ss.scanRow(queryable, nil, startTime, stmt, args, dest)
+	if err != nil {
+		return ss.db.queryError(ss.queryInfo(), stmt, err)
 	}
-	err = queryable.QueryRow(args...).Scan(dest...)
+
+	return nil
+}
+
+// scanRow runs the query and scans the first row to dest. The latency of the
+// replica is updated if it's given.
+func (ss *SelectStatement) scanRow(queryable queryable, replica *replica, startTime time.Time, stmt string, args []interface{}, dest []interface{}) error {
+	err := queryable.QueryRow(args...).Scan(dest...)
 	consumedTime := timeElapsedSince(startTime)
 	ss.db.addConsumedTime(consumedTime)
 	ss.db.logExecution(consumedTime, stmt, args)
 	if err != nil {
 		ss.db.logExecutionErr(err, stmt, args)
-		return err
 	}
-
-	return nil
+	if replica != nil && (err == nil || err == sql.ErrNoRows) {
+		replica.observe(consumedTime)
+	}
+	return err
 }
 
-// Count runs the request with COUNT(*) (remove others columns)
-// and returns the count.
+// Count runs the request with COUNT(*) and returns the count.
+// The statement is left untouched, it can still be executed with Do.
+// If the statement has DISTINCT or GROUP BY clauses, the rows are counted
+// using it as a subquery.
 func (ss *SelectStatement) Count() (int64, error) {
-	ss.columns = ss.columns[:0]
-	ss.Columns("COUNT(*)")
+	stmt, args, err := ss.countToSQL()
+	if err != nil {
+		return 0, err
+	}
 
 	var count int64
-	err := ss.Scanx(&count)
+	err = ss.scanx(stmt, args, &count)
 	return count, err
 }
 
+// Page describes a page fetched with DoPage.
+type Page struct {
+	// Total is the count of rows for all pages
+	Total int64
+	// Page is the current page number, starting at 1
+	Page int
+	// PageSize is the maximum count of rows in a page
+	PageSize int
+	// Pages is the count of pages
+	Pages int
+}
+
+// DoPage counts all rows matching the statement, then executes it to fetch
+// only the rows of the given page (starting at 1) into the record, which has
+// to be a pointer to a slice.
+// If no ORDER BY clause is defined, the rows are ordered by the keys of the
+// record struct.
+func (ss *SelectStatement) DoPage(record interface{}, page int, pageSize int) (*Page, error) {
+	if ss.error != nil {
+		return nil, ss.error
+	}
+	if page < 1 || pageSize < 1 {
+		return nil, fmt.Errorf("invalid page %d or page size %d", page, pageSize)
+	}
+
+	recordInfo, err := buildRecordDescription(record)
+	if err != nil {
+		return nil, err
+	}
+	if !recordInfo.isSlice {
+		return nil, fmt.Errorf("DoPage needs a slice")
+	}
+
+	total, err := ss.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	statement := ss.Clone()
+	statement.Offset((page - 1) * pageSize).
+		Limit(pageSize)
+	// Some DB require an order by if offset and limit are used
+	// (MS SQL Server)
+	if len(statement.orderBy) == 0 {
+		for _, keyColumn := range recordInfo.structMapping.GetKeyColumnsNames() {
+			statement.OrderBy(statement.db.quote(keyColumn))
+		}
+	}
+
+	if err := statement.do(recordInfo, statement.prepareColumns(recordInfo)); err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Pages:    int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
 // DoWithIterator executes the select query and returns an Iterator allowing
 // the caller to fetch rows one at a time.
 // Warning : it does not use an existing transation to avoid some pitfalls with
@@ -367,5 +664,5 @@ func (ss *SelectStatement) DoWithIterator() (Iterator, error) {
 		return nil, err
 	}
 
-	return ss.db.doWithIterator(sqlQuery, args)
+	return ss.db.doWithIterator(ss.queryInfo(), sqlQuery, args)
 }
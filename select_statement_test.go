@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/adapters/generic"
 	"github.com/samonzeweb/godb/adapters/sqlite"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -136,7 +138,8 @@ func TestSelectWhereQ(t *testing.T) {
 			qc := Q("id = ?", 123)
 			q.WhereQ(qc)
 			So(len(q.where), ShouldEqual, 1)
-			So(q.where[0], ShouldEqual, qc)
+			So(q.where[0].sql, ShouldEqual, qc.sql)
+			So(q.where[0].args, ShouldResemble, qc.args)
 		})
 	})
 }
@@ -181,7 +184,8 @@ func TestSelectHavingQ(t *testing.T) {
 			qc := Q("count(*) > 1")
 			q.HavingQ(qc)
 			So(len(q.having), ShouldEqual, 1)
-			So(q.having[0], ShouldEqual, qc)
+			So(q.having[0].sql, ShouldEqual, qc.sql)
+			So(q.having[0].args, ShouldResemble, qc.args)
 		})
 	})
 }
@@ -334,6 +338,131 @@ func TestSelectToSQL(t *testing.T) {
 	})
 }
 
+func TestSelectTop(t *testing.T) {
+	Convey("Given a select query with an adapter using TOP", t, func() {
+		db := &DB{adapter: generic.New(generic.Dialect{
+			Capabilities: adapters.Capabilities{Pagination: adapters.PaginationTop},
+		})}
+		q := db.SelectFrom("dummies").
+			Distinct().
+			Columns("foo", "bar").
+			Where("foo > ?", 1).
+			OrderBy("foo").
+			Limit(10)
+
+		Convey("ToSQL writes the limit after SELECT", func() {
+			sql, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT DISTINCT TOP (?) foo, bar FROM dummies WHERE foo > ? ORDER BY foo")
+			So(args, ShouldResemble, []interface{}{10, 1})
+		})
+
+		Convey("countToSQL ignores the limit", func() {
+			sql, _, err := q.countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT COUNT(*) FROM (SELECT DISTINCT foo, bar FROM dummies WHERE foo > ?) godb_count")
+		})
+
+		Convey("ToSQL returns an error with an offset", func() {
+			q.Offset(20)
+			_, _, err := q.ToSQL()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+		})
+	})
+}
+
+func TestSelectOffsetFetch(t *testing.T) {
+	Convey("Given a select query with an adapter using OFFSET and FETCH", t, func() {
+		db := &DB{adapter: generic.New(generic.Dialect{
+			Capabilities: adapters.Capabilities{
+				Placeholders: adapters.PlaceholderAtP,
+				Pagination:   adapters.PaginationOffsetFetch,
+			},
+		})}
+		q := db.SelectFrom("dummies").
+			Columns("foo").
+			Where("foo > ?", 1).
+			OrderBy("foo").
+			Limit(10).
+			Offset(20)
+
+		Convey("ToSQL writes the offset before the limit", func() {
+			sql, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(db.replacePlaceholders(sql), ShouldEqual, "SELECT foo FROM dummies WHERE foo > @p1 ORDER BY foo OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY")
+			So(args, ShouldResemble, []interface{}{1, 20, 10})
+		})
+	})
+
+	Convey("Given an adapter without capabilities building the limit", t, func() {
+		db := &DB{adapter: legacyLimitAdapter{}}
+
+		Convey("ToSQL uses the adapter to write the limit", func() {
+			sql, _, err := db.SelectFrom("dummies").Columns("foo").Limit(10).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo FROM dummies FETCH FIRST ? ROWS ONLY")
+		})
+	})
+}
+
+// legacyLimitAdapter is an adapter without capabilities, implementing the
+// deprecated adapters.LimitBuilder.
+type legacyLimitAdapter struct{}
+
+func (legacyLimitAdapter) DriverName() string {
+	return "legacy"
+}
+
+func (legacyLimitAdapter) Quote(identifier string) string {
+	return identifier
+}
+
+func (legacyLimitAdapter) ParseError(err error) error {
+	return err
+}
+
+func (legacyLimitAdapter) BuildLimit(limit int) *adapters.SQLPart {
+	return &adapters.SQLPart{Sql: "FETCH FIRST ? ROWS ONLY", Arguments: []interface{}{limit}}
+}
+
+func TestSelectClone(t *testing.T) {
+	Convey("Given a select query", t, func() {
+		db := &DB{}
+		q := db.SelectFrom("dummies").
+			Columns("foo", "bar").
+			LeftJoin("others", "o", Q("o.id = dummies.other_id")).
+			Where("foo = ?", 1).
+			OrderBy("foo").
+			Limit(10).
+			Suffix("FOR UPDATE")
+
+		Convey("Clone produces the same SQL and arguments", func() {
+			sql, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			cloneSQL, cloneArgs, err := q.Clone().ToSQL()
+			So(err, ShouldBeNil)
+			So(cloneSQL, ShouldEqual, sql)
+			So(cloneArgs, ShouldResemble, args)
+		})
+
+		Convey("Changes to the clone does not alter the original", func() {
+			sql, _, _ := q.ToSQL()
+			q.Clone().
+				Columns("baz").
+				InnerJoin("again", "a", Q("a.id = dummies.again_id")).
+				Where("bar = ?", 2).
+				GroupBy("foo").
+				OrderBy("bar").
+				Limit(20).
+				Offset(5).
+				Suffix("NOWAIT")
+			originalSQL, args, _ := q.ToSQL()
+			So(originalSQL, ShouldEqual, sql)
+			So(args, ShouldResemble, []interface{}{1, 10})
+		})
+	})
+}
+
 func TestSelectToSQLErrors(t *testing.T) {
 	Convey("Columns are mandatory", t, func() {
 		db := &DB{}
@@ -441,7 +570,7 @@ func TestSelectDo(t *testing.T) {
 			So(singleDummy.ID, ShouldBeGreaterThan, 0)
 
 			So(err, ShouldBeNil)
-			So(len(selectStmt.columns), ShouldEqual, 6)
+			So(len(selectStmt.columns), ShouldEqual, 0)
 			So(singleDummy.AnInteger, ShouldEqual, 13)
 		})
 
@@ -464,7 +593,7 @@ func TestSelectDo(t *testing.T) {
 			So(singleDummy.ID, ShouldBeGreaterThan, 0)
 
 			So(err, ShouldBeNil)
-			So(len(selectStmt.columns), ShouldEqual, 6)
+			So(len(selectStmt.columns), ShouldEqual, 0)
 			So(singleDummy.AnInteger, ShouldEqual, 13)
 		})
 
@@ -475,7 +604,7 @@ func TestSelectDo(t *testing.T) {
 
 			err := selectStmt.Do(&dummiesSlice)
 			So(err, ShouldBeNil)
-			So(len(selectStmt.columns), ShouldEqual, 6)
+			So(len(selectStmt.columns), ShouldEqual, 0)
 			So(len(dummiesSlice), ShouldEqual, 3)
 			So(dummiesSlice[0].AnInteger, ShouldEqual, 11)
 			So(dummiesSlice[1].AnInteger, ShouldEqual, 12)
@@ -484,6 +613,44 @@ func TestSelectDo(t *testing.T) {
 	})
 }
 
+func TestSelectDoIsReusable(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("A base statement can be executed and extended multiple times", func() {
+			base := db.SelectFrom("dummies").Where("an_integer > ?", 11)
+
+			dummy := Dummy{}
+			err := base.Do(&dummy)
+			So(err, ShouldBeNil)
+
+			dummies := make([]Dummy, 0)
+			err = base.Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+
+			dummies = make([]Dummy, 0)
+			err = base.Clone().Where("an_integer < ?", 13).Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+
+			sql, _, err := base.Columns("id").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM dummies WHERE an_integer > ?")
+		})
+
+		Convey("A struct select can be executed multiple times", func() {
+			dummies := make([]Dummy, 0)
+			selectStmt := db.Select(&dummies).Where("an_integer > ?", 11)
+			So(selectStmt.Do(), ShouldBeNil)
+			dummies = dummies[:0]
+			So(selectStmt.Do(), ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+	})
+}
+
 func TestCount(t *testing.T) {
 	Convey("Given a test database", t, func() {
 		db := fixturesSetup(t)
@@ -507,6 +674,121 @@ func TestCount(t *testing.T) {
 	})
 }
 
+func TestCountToSQL(t *testing.T) {
+	Convey("Given a select query", t, func() {
+		db := &DB{}
+		q := db.SelectFrom("dummies").
+			Columns("foo", "bar").
+			Where("foo > ?", 1).
+			OrderBy("foo").
+			Limit(10)
+
+		Convey("countToSQL replaces columns and ignores order and limit", func() {
+			sql, args, err := q.countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT COUNT(*) FROM dummies WHERE foo > ?")
+			So(len(args), ShouldEqual, 1)
+		})
+
+		Convey("countToSQL uses a subquery with GROUP BY", func() {
+			q.GroupBy("foo")
+			sql, _, err := q.countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT COUNT(*) FROM (SELECT foo, bar FROM dummies WHERE foo > ? GROUP BY foo) godb_count")
+		})
+
+		Convey("countToSQL uses a subquery with DISTINCT", func() {
+			q.Distinct()
+			sql, _, err := q.countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT COUNT(*) FROM (SELECT DISTINCT foo, bar FROM dummies WHERE foo > ?) godb_count")
+		})
+
+		Convey("countToSQL counts the distinct rows without columns", func() {
+			sql, _, err := db.SelectFrom("dummies").Distinct().countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT COUNT(*) FROM (SELECT DISTINCT * FROM dummies) godb_count")
+
+			_, _, err = db.SelectFrom("dummies").Distinct().GroupBy("foo").countToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("countToSQL writes the suffixes in the subquery", func() {
+			sql, _, err := q.Distinct().Suffix("WITH (NOLOCK)").countToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT COUNT(*) FROM (SELECT DISTINCT foo, bar FROM dummies WHERE foo > ? WITH (NOLOCK)) godb_count")
+		})
+
+		Convey("countToSQL does not change the statement", func() {
+			q.countToSQL()
+			sql, _, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT foo, bar FROM dummies WHERE foo > ? ORDER BY foo LIMIT ?")
+		})
+	})
+}
+
+func TestDoPage(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Count leaves the statement reusable", func() {
+			selectStmt := db.SelectFrom("dummies").Where("an_integer > ?", 11)
+			count, err := selectStmt.Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+
+			dummies := make([]Dummy, 0)
+			err = selectStmt.Do(&dummies)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+		})
+
+		Convey("Count counts the distinct rows without columns", func() {
+			count, err := db.SelectFrom("dummies").Distinct().Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Count counts groups", func() {
+			count, err := db.SelectFrom("dummies").
+				Columns("an_integer > 11").
+				GroupBy("an_integer > 11").
+				Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("DoPage fetches a page and the total", func() {
+			dummies := make([]Dummy, 0)
+			page, err := db.SelectFrom("dummies").
+				OrderBy("an_integer").
+				DoPage(&dummies, 2, 2)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+			So(dummies[0].AnInteger, ShouldEqual, 13)
+			So(*page, ShouldResemble, Page{Total: 3, Page: 2, PageSize: 2, Pages: 2})
+		})
+
+		Convey("DoPage works with struct selects", func() {
+			dummies := make([]Dummy, 0)
+			page, err := db.Select(&dummies).DoPage(1, 2)
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+			So(dummies[0].AnInteger, ShouldEqual, 11)
+			So(page.Total, ShouldEqual, 3)
+			So(page.Pages, ShouldEqual, 2)
+		})
+
+		Convey("DoPage rejects invalid pages", func() {
+			dummies := make([]Dummy, 0)
+			_, err := db.SelectFrom("dummies").DoPage(&dummies, 0, 2)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
 func TestScanx(t *testing.T) {
 	Convey("Given a test database", t, func() {
 		db := fixturesSetup(t)
@@ -134,6 +134,8 @@ func (b *SQLBuffer) Append(other *SQLBuffer) *SQLBuffer {
 }
 
 // WriteCondition writes single conditional expressions.
+// SQLBuffer has no adapter, the identifiers of expressions (see Col) are not
+// quoted, and ILIKE is emulated.
 func (b *SQLBuffer) WriteCondition(condition *Condition) *SQLBuffer {
 	if b.err != nil {
 		return b
@@ -144,7 +146,7 @@ func (b *SQLBuffer) WriteCondition(condition *Condition) *SQLBuffer {
 		return b
 	}
 
-	b.Write(condition.sql, condition.args...)
+	b.Write(resolveExpressions(nil, condition.sql), condition.args...)
 	return b
 }
 
@@ -166,6 +168,22 @@ func newSQLBuffer(adapter adapters.Adapter, sqlLength int, argsLength int) *sqlB
 	}
 }
 
+// WriteCondition writes single conditional expressions, quoting the
+// identifiers of expressions with the adapter.
+func (b *sqlBuffer) WriteCondition(condition *Condition) *sqlBuffer {
+	if b.Err() != nil {
+		return b
+	}
+
+	if condition.Err() != nil {
+		b.err = condition.Err()
+		return b
+	}
+
+	b.Write(resolveExpressions(b.adapter, condition.sql), condition.args...)
+	return b
+}
+
 // writeStringsWithSpaces writes strings separated by spaces, and with a
 // leading space.
 func (b *sqlBuffer) writeStringsWithSpaces(customs []string) *sqlBuffer {
@@ -227,8 +245,8 @@ func (b *sqlBuffer) writeJoins(joins []*joinPart) *sqlBuffer {
 				Write(join.as)
 		}
 		if join.on != nil {
-			b.Write(" ON ").
-				WriteCondition(join.on)
+			b.Write(" ON ")
+			b.WriteCondition(join.on)
 		}
 	}
 
@@ -273,6 +291,20 @@ func (b *sqlBuffer) writeGroupByAndHaving(columns []string, conditions []*Condit
 	return b
 }
 
+// writeWindows writes WINDOW clause into the buffer.
+func (b *sqlBuffer) writeWindows(windows []string) *sqlBuffer {
+	if b.Err() != nil {
+		return b
+	}
+
+	if len(windows) != 0 {
+		b.Write(" WINDOW ")
+		b.writeNameList(windows)
+	}
+
+	return b
+}
+
 // writeOrderBy writes ORDER BY clause into the buffer.
 func (b *sqlBuffer) writeOrderBy(columns []string) *sqlBuffer {
 	if b.Err() != nil {
@@ -294,11 +326,15 @@ func (b *sqlBuffer) writeOffset(offset *int) *sqlBuffer {
 	}
 
 	if offset != nil {
-		offsetBuilder, ok := b.adapter.(adapters.OffsetBuilder)
+		offsetBuilder, ok := legacyAdapter(b.adapter).(adapters.OffsetBuilder)
 		if ok {
 			sqlPart := offsetBuilder.BuildOffset(*offset)
 			b.Write(" ").
 				Write(sqlPart.Sql, sqlPart.Arguments...)
+		} else if capabilitiesOf(b.adapter).Pagination == adapters.PaginationOffsetFetch {
+			b.Write(" OFFSET ").
+				Write(Placeholder, *offset).
+				Write(" ROWS")
 		} else {
 			b.Write(" OFFSET ").
 				Write(Placeholder, *offset)
@@ -315,11 +351,15 @@ func (b *sqlBuffer) writeLimit(limit *int) *sqlBuffer {
 	}
 
 	if limit != nil {
-		limitBuilder, ok := b.adapter.(adapters.LimitBuilder)
+		limitBuilder, ok := legacyAdapter(b.adapter).(adapters.LimitBuilder)
 		if ok {
 			sqlPart := limitBuilder.BuildLimit(*limit)
 			b.Write(" ").
 				Write(sqlPart.Sql, sqlPart.Arguments...)
+		} else if capabilitiesOf(b.adapter).Pagination == adapters.PaginationOffsetFetch {
+			b.Write(" FETCH NEXT ").
+				Write(Placeholder, *limit).
+				Write(" ROWS ONLY")
 		} else {
 			b.Write(" LIMIT ").
 				Write(Placeholder, *limit)
@@ -329,6 +369,28 @@ func (b *sqlBuffer) writeLimit(limit *int) *sqlBuffer {
 	return b
 }
 
+// writeTop writes the TOP clause following SELECT if the database uses it
+// (see adapters.PaginationTop), and returns true if it was written. The
+// OFFSET clause can't be used with it.
+func (b *sqlBuffer) writeTop(limit *int, offset *int) bool {
+	if b.Err() != nil || limit == nil {
+		return false
+	}
+
+	if capabilitiesOf(b.adapter).Pagination != adapters.PaginationTop {
+		return false
+	}
+	if offset != nil {
+		b.err = &UnsupportedError{Feature: "OFFSET"}
+		return false
+	}
+
+	b.Write("TOP (").
+		Write(Placeholder, *limit).
+		Write(") ")
+	return true
+}
+
 // writeInto writes INTO clause into the buffer.
 func (b *sqlBuffer) writeInto(intoTable string) *sqlBuffer {
 	if b.Err() != nil {
@@ -349,9 +411,9 @@ func (b *sqlBuffer) writeInto(intoTable string) *sqlBuffer {
 // is the one used by the adapter.
 //
 // If the columns list is empty, it always returns without error.
-// If the columns list isn't empty, the adapter have to implements the
-// ReturningBuilder interface.
-func (b *sqlBuffer) writeReturningForPosition(columns []string, position adapters.ReturningPosition) *sqlBuffer {
+// If the columns list isn't empty, the adapter have to support the RETURNING
+// clause in the statement, an UPDATE one if update is true.
+func (b *sqlBuffer) writeReturningForPosition(columns []string, position adapters.ReturningPosition, update bool) *sqlBuffer {
 	if b.Err() != nil {
 		return b
 	}
@@ -360,9 +422,9 @@ func (b *sqlBuffer) writeReturningForPosition(columns []string, position adapter
 		return b
 	}
 
-	returningBuilder, ok := b.adapter.(adapters.ReturningBuilder)
-	if !ok {
-		b.err = fmt.Errorf("the adapter does not manage RETUNING-like clause")
+	returningBuilder := returningBuilderOf(b.adapter, update)
+	if returningBuilder == nil {
+		b.err = &UnsupportedError{Feature: "RETURNING"}
 		return b
 	}
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/samonzeweb/godb"
+	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/adapters/sqlite"
 	"github.com/samonzeweb/godb/dbtests/common"
 
@@ -14,8 +15,12 @@ import (
 const sqlite3testdb = "sqlite3-test.db"
 
 func fixturesSetupSQLite(t *testing.T) (*godb.DB, func()) {
+	return fixturesSetupSQLiteWithAdapter(t, sqlite.Adapter)
+}
+
+func fixturesSetupSQLiteWithAdapter(t *testing.T, adapter adapters.Adapter) (*godb.DB, func()) {
 	removeDBIfExists(t)
-	db, err := godb.Open(sqlite.Adapter, sqlite3testdb)
+	db, err := godb.Open(adapter, sqlite3testdb)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -105,3 +110,23 @@ func TestRawSQLite(t *testing.T) {
 		})
 	})
 }
+
+func TestReturningSQLite(t *testing.T) {
+	Convey("Open checks the SQLite version of the adapter using RETURNING", t, func() {
+		removeDBIfExists(t)
+		defer removeDBIfExists(t)
+		db, err := godb.Open(sqlite.ReturningAdapter, sqlite3testdb)
+		if err != nil {
+			So(err.Error(), ShouldContainSubstring, "SQLite 3.35")
+			return
+		}
+		So(db.Close(), ShouldBeNil)
+
+		db, teardown := fixturesSetupSQLiteWithAdapter(t, sqlite.ReturningAdapter)
+		defer teardown()
+
+		Convey("The common structs tests must pass", func() {
+			common.StructsTests(db, t)
+		})
+	})
+}
@@ -12,7 +12,11 @@ type pointersGetter func(record interface{}, columns []string) ([]interface{}, e
 
 // do executes the given query (with its arguments) after replacing the
 // placeholders if neeeded, and returns sql.Result.
-func (db *DB) do(query string, arguments []interface{}) (sql.Result, error) {
+// The errors are described by info (see queryError).
+func (db *DB) do(info queryInfo, query string, arguments []interface{}) (sql.Result, error) {
+	if err := db.checkArguments(arguments); err != nil {
+		return nil, err
+	}
 	query = db.replacePlaceholders(query)
 
 	// Execute the statement
@@ -20,7 +24,7 @@ func (db *DB) do(query string, arguments []interface{}) (sql.Result, error) {
 	queryable, err := db.getQueryable(query)
 	if err != nil {
 		db.logExecutionErr(err, query, arguments)
-		return nil, err
+		return nil, db.queryError(info, query, err)
 	}
 	result, err := queryable.Exec(arguments...)
 	consumedTime := timeElapsedSince(startTime)
@@ -28,10 +32,7 @@ func (db *DB) do(query string, arguments []interface{}) (sql.Result, error) {
 	db.logExecution(consumedTime, query, arguments)
 	if err != nil {
 		db.logExecutionErr(err, query, arguments)
-		if db.useErrorParser {
-			return nil, db.adapter.ParseError(err)
-		}
-		return nil, err
+		return nil, db.queryError(info, query, err)
 	}
 
 	return result, err
@@ -40,8 +41,8 @@ func (db *DB) do(query string, arguments []interface{}) (sql.Result, error) {
 // doSelectOrWithReturning executes the statement and fills the auto fields.
 // It returns the count of rows returned.
 // It is called when the adapter implements ReturningSuffixer.
-func (db *DB) doSelectOrWithReturning(query string, arguments []interface{}, recordDescription *recordDescription, pointersGetter pointersGetter) (int64, error) {
-	rows, columns, err := db.executeQuery(query, arguments, false, false)
+func (db *DB) doSelectOrWithReturning(info queryInfo, query string, arguments []interface{}, recordDescription *recordDescription, pointersGetter pointersGetter) (int64, error) {
+	rows, columns, err := db.executeQuery(info, query, arguments, false, false)
 	if err != nil {
 		return 0, err
 	}
@@ -60,27 +61,48 @@ func (db *DB) doSelectOrWithReturning(query string, arguments []interface{}, rec
 	}
 	if err != nil {
 		db.logExecutionErr(err, query, arguments)
-		return 0, err
+		return 0, db.queryError(info, query, err)
 	}
 
 	err = rows.Err()
 	if err != nil {
 		db.logExecutionErr(err, query, arguments)
 	}
-	return int64(rowsCount), err
+	return int64(rowsCount), db.queryError(info, query, err)
 }
 
 // executeQuery executes the given query with its arguments and returns the
 // resulting *sql.Rows, the list of columns names, and an error.
-func (db *DB) executeQuery(query string, arguments []interface{}, noTx, noStmtCache bool) (*sql.Rows, []string, error) {
+// The read queries run on a replica if there are some, outside transactions.
+// The primary is used if the replica failed.
+// The errors are described by info (see queryError).
+func (db *DB) executeQuery(info queryInfo, query string, arguments []interface{}, noTx, noStmtCache bool) (*sql.Rows, []string, error) {
+	if err := db.checkArguments(arguments); err != nil {
+		return nil, nil, err
+	}
 	query = db.replacePlaceholders(query)
 
+	if replica := db.replicaFor(info); replica != nil {
+		replicaQueryable := &queryWrapper{db: replica.sqlDB, sqlQuery: query}
+		rows, columns, err := db.queryWithColumns(replicaQueryable, replica, time.Now(), query, arguments)
+		if !db.replicaFailed(replica, err) {
+			return rows, columns, db.queryError(info, query, err)
+		}
+	}
+
 	startTime := time.Now()
 	queryable, err := db.getQueryableWithOptions(query, noTx, noStmtCache)
 	if err != nil {
 		db.logExecutionErr(err, query, arguments)
-		return nil, nil, err
+		return nil, nil, db.queryError(info, query, err)
 	}
+	rows, columns, err := db.queryWithColumns(queryable, nil, startTime, query, arguments)
+	return rows, columns, db.queryError(info, query, err)
+}
+
+// queryWithColumns runs the query and returns the resulting *sql.Rows and the
+// list of columns names. The latency of the replica is updated if it's given.
+func (db *DB) queryWithColumns(queryable queryable, replica *replica, startTime time.Time, query string, arguments []interface{}) (*sql.Rows, []string, error) {
 	rows, err := queryable.Query(arguments...)
 	consumedTime := timeElapsedSince(startTime)
 	db.addConsumedTime(consumedTime)
@@ -89,6 +111,9 @@ func (db *DB) executeQuery(query string, arguments []interface{}, noTx, noStmtCa
 		db.logExecutionErr(err, query, arguments)
 		return nil, nil, err
 	}
+	if replica != nil {
+		replica.observe(consumedTime)
+	}
 
 	columns, err := rows.Columns()
 	if err != nil {
@@ -159,8 +184,8 @@ func (db *DB) growAndFillWithValues(recordDescription *recordDescription, pointe
 
 // doWithIterator executes the given query (with its arguments) and returns
 // an Iterator.
-func (db *DB) doWithIterator(query string, arguments []interface{}) (Iterator, error) {
-	rows, columns, err := db.executeQuery(query, arguments, true, true)
+func (db *DB) doWithIterator(info queryInfo, query string, arguments []interface{}) (Iterator, error) {
+	rows, columns, err := db.executeQuery(info, query, arguments, true, true)
 	if err != nil {
 		if rows != nil {
 			rows.Close()
@@ -169,6 +194,9 @@ func (db *DB) doWithIterator(query string, arguments []interface{}) (Iterator, e
 	}
 
 	iterator := iteratorInternals{
+		db:      db,
+		info:    info,
+		query:   query,
 		rows:    rows,
 		columns: columns,
 	}
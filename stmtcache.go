@@ -3,10 +3,13 @@ package godb
 import (
 	"database/sql"
 	"fmt"
+	"sync"
 )
 
 // StmtCache is a LRU cache for prepared statements.
+// It can be used by multiple goroutines.
 type StmtCache struct {
+	mutex sync.Mutex
 	// isEnabled isn't useful for the cache itself, but it allow a complete
 	// control of the cache with a StmtCache instance for godb users.
 	isEnabled bool
@@ -18,6 +21,37 @@ type StmtCache struct {
 type stmtCacheItem struct {
 	stmt    *sql.Stmt
 	lastUse uint64
+	// Count of queries running with the stmt, and true if the item was
+	// removed from the cache, the stmt being closed after the last query.
+	users   int
+	removed bool
+}
+
+// cachedStmt is a prepared statement of the cache, used to run a single
+// query. It's released once the query is started, then the statement can be
+// closed if it was removed from the cache meanwhile.
+type cachedStmt struct {
+	cache *StmtCache
+	item  *stmtCacheItem
+}
+
+// Exec wraps the Exec method of the prepared statement.
+func (s *cachedStmt) Exec(args ...interface{}) (sql.Result, error) {
+	defer s.cache.release(s.item)
+	return s.item.stmt.Exec(args...)
+}
+
+// Query wraps the Query method of the prepared statement. The rows keep the
+// statement usable until they are closed, even if it's closed before.
+func (s *cachedStmt) Query(args ...interface{}) (*sql.Rows, error) {
+	defer s.cache.release(s.item)
+	return s.item.stmt.Query(args...)
+}
+
+// QueryRow wraps the QueryRow method of the prepared statement.
+func (s *cachedStmt) QueryRow(args ...interface{}) *sql.Row {
+	defer s.cache.release(s.item)
+	return s.item.stmt.QueryRow(args...)
 }
 
 // DefaultStmtCacheSize is the default size of prepared statements LRU cache.
@@ -39,17 +73,23 @@ func newStmtCache() *StmtCache {
 
 // Enable enables the cache.
 func (cache *StmtCache) Enable() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	cache.isEnabled = true
 }
 
 // Disable disables the cache.
 // Disabling the cache does not clear it.
 func (cache *StmtCache) Disable() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	cache.isEnabled = false
 }
 
 // IsEnabled returns true if the cache is enabled, false otherwise.
 func (cache *StmtCache) IsEnabled() bool {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	return cache.isEnabled
 }
 
@@ -59,6 +99,8 @@ func (cache *StmtCache) SetSize(size int) error {
 		return fmt.Errorf("given cache size is out if allowed boundarie")
 	}
 
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	cache.maxSize = size
 
 	for len(cache.content) > cache.maxSize {
@@ -73,11 +115,77 @@ func (cache *StmtCache) SetSize(size int) error {
 
 // GetSize returns the maximum cache size
 func (cache *StmtCache) GetSize() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	return cache.maxSize
 }
 
 // sdd adds a prepared statement into the cache.
 func (cache *StmtCache) add(query string, stmt *sql.Stmt) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.store(query, &stmtCacheItem{stmt: stmt})
+}
+
+// getOrPrepare returns the prepared statement of the given query, prepared
+// with the prepare func and added into the cache if it wasn't found. The
+// returned bool is true if the statement was found.
+// The mutex isn't held while the query is prepared, if it's prepared by
+// concurrent calls only the first statement is kept, the others are closed.
+func (cache *StmtCache) getOrPrepare(query string, prepare func(query string) (*sql.Stmt, error)) (*cachedStmt, bool, error) {
+	if stmt := cache.acquire(query); stmt != nil {
+		return stmt, true, nil
+	}
+
+	preparedStmt, err := prepare(query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if item := cache.lookup(query); item != nil {
+		item.users++
+		preparedStmt.Close()
+		return &cachedStmt{cache: cache, item: item}, true, nil
+	}
+
+	item := &stmtCacheItem{stmt: preparedStmt, users: 1}
+	if err := cache.store(query, item); err != nil {
+		// Not cached, closed once used
+		item.removed = true
+	}
+	return &cachedStmt{cache: cache, item: item}, false, nil
+}
+
+// acquire returns the cached prepared statement of the given query, or nil.
+func (cache *StmtCache) acquire(query string) *cachedStmt {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	item := cache.lookup(query)
+	if item == nil {
+		return nil
+	}
+	item.users++
+	return &cachedStmt{cache: cache, item: item}
+}
+
+// release ends the use of a cached prepared statement, and closes it if it
+// was removed from the cache.
+func (cache *StmtCache) release(item *stmtCacheItem) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	item.users--
+	if item.users == 0 && item.removed {
+		item.stmt.Close()
+	}
+}
+
+// store adds a prepared statement into the cache, the caller must hold the
+// mutex.
+func (cache *StmtCache) store(query string, item *stmtCacheItem) error {
 	// Cache full ?
 	if len(cache.content) >= cache.maxSize {
 		err := cache.removeLeastRecentlyUsed()
@@ -88,14 +196,24 @@ func (cache *StmtCache) add(query string, stmt *sql.Stmt) error {
 
 	// Add new entry
 	cache.lastUse++
-	cache.content[query] = &stmtCacheItem{
-		stmt:    stmt,
-		lastUse: cache.lastUse,
-	}
+	item.lastUse = cache.lastUse
+	cache.content[query] = item
 
 	return nil
 }
 
+// remove removes an item from the cache, and closes its prepared statement
+// unless it's in use, the caller must hold the mutex.
+func (cache *StmtCache) remove(query string, cacheItem *stmtCacheItem) error {
+	delete(cache.content, query)
+	cacheItem.removed = true
+	if cacheItem.users > 0 {
+		// Closed by release
+		return nil
+	}
+	return cacheItem.stmt.Close()
+}
+
 // removeLeastRecentlyUsed removes the least recently used entry from the cache.
 func (cache *StmtCache) removeLeastRecentlyUsed() error {
 	// Search the LRU item
@@ -115,8 +233,7 @@ func (cache *StmtCache) removeLeastRecentlyUsed() error {
 		if !ok {
 			return fmt.Errorf("removeLeastRecentlyUsed : query not found in cache")
 		}
-		delete(cache.content, minQuery)
-		if err := cacheItem.stmt.Close(); err != nil {
+		if err := cache.remove(minQuery, cacheItem); err != nil {
 			return err
 		}
 	}
@@ -126,6 +243,17 @@ func (cache *StmtCache) removeLeastRecentlyUsed() error {
 
 // get returns an existing prepared statement or nil.
 func (cache *StmtCache) get(query string) *sql.Stmt {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cacheItem := cache.lookup(query)
+	if cacheItem == nil {
+		return nil
+	}
+	return cacheItem.stmt
+}
+
+// lookup returns an existing item or nil, the caller must hold the mutex.
+func (cache *StmtCache) lookup(query string) *stmtCacheItem {
 	cacheItem, ok := cache.content[query]
 	if !ok {
 		return nil
@@ -133,17 +261,20 @@ func (cache *StmtCache) get(query string) *sql.Stmt {
 
 	cache.lastUse++
 	cacheItem.lastUse = cache.lastUse
-	return cacheItem.stmt
+	return cacheItem
 }
 
 // Clear closes properly the the cached stmt, and clear the cache.
+// The stmt in use are closed once their queries are started.
 func (cache *StmtCache) Clear() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	defer func() {
-		cache.clearWithoutClosingStmt()
+		cache.content = make(map[string]*stmtCacheItem)
 	}()
 
-	for _, cacheItem := range cache.content {
-		if err := cacheItem.stmt.Close(); err != nil {
+	for query, cacheItem := range cache.content {
+		if err := cache.remove(query, cacheItem); err != nil {
 			return err
 		}
 	}
@@ -157,5 +288,7 @@ func (cache *StmtCache) Clear() error {
 // Use case if for Tx Commit or Rollback, when prepared statements could no
 // longer be used, do not use it otherwise.
 func (cache *StmtCache) clearWithoutClosingStmt() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	cache.content = make(map[string]*stmtCacheItem)
 }
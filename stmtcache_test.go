@@ -1,7 +1,9 @@
 package godb
 
 import (
+	"fmt"
 	"strconv"
+	"sync"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -188,3 +190,67 @@ func TestClearWithoutClosingStmt(t *testing.T) {
 		})
 	})
 }
+
+func TestGetOrPrepare(t *testing.T) {
+	Convey("Given a cache", t, func() {
+		c := newStmtCache()
+		db := fixturesSetup(t)
+		defer db.Close()
+		query := "select * from dummies"
+
+		Convey("getOrPrepare prepares the stmt once", func() {
+			stmt, cached, err := c.getOrPrepare(query, db.CurrentDB().Prepare)
+			So(err, ShouldBeNil)
+			So(cached, ShouldBeFalse)
+			stmt2, cached, err := c.getOrPrepare(query, db.CurrentDB().Prepare)
+			So(err, ShouldBeNil)
+			So(cached, ShouldBeTrue)
+			So(stmt2.item, ShouldEqual, stmt.item)
+		})
+
+		Convey("A stmt removed from the cache is closed once used", func() {
+			stmt, _, err := c.getOrPrepare(query, db.CurrentDB().Prepare)
+			So(err, ShouldBeNil)
+			So(c.Clear(), ShouldBeNil)
+
+			rows, err := stmt.Query()
+			So(err, ShouldBeNil)
+			So(rows.Close(), ShouldBeNil)
+			_, err = stmt.item.stmt.Query()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a DB with a cache of a single stmt", t, func() {
+		db := createFileConnection(t)
+		defer db.Close()
+		db.StmtCacheDB().Enable()
+		So(db.StmtCacheDB().SetSize(1), ShouldBeNil)
+
+		Convey("The stmt in use are not closed by the concurrent queries", func() {
+			const goroutines = 8
+			const iterations = 20
+			var wg sync.WaitGroup
+			errs := make(chan error, goroutines*iterations)
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < iterations; i++ {
+						where := fmt.Sprintf("an_integer > %d", (g+i)%3)
+						_, err := db.SelectFrom("dummies").Where(where).Count()
+						if err != nil {
+							errs <- err
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				So(err, ShouldBeNil)
+			}
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package godb
+
+import (
+	"database/sql"
+	"iter"
+)
+
+// Iterable is implemented by queries able to return an Iterator, like
+// SelectStatement, StructSelect and RawSQL.
+type Iterable interface {
+	DoWithIterator() (Iterator, error)
+}
+
+// Stream executes the given query and returns a Go iterator over its rows,
+// each one mapped to a new T (a struct type).
+// The rows are closed when the loop ends, including when it is stopped early
+// with a break.
+// If an error occurs it is yielded with a zero T, and the iteration stops.
+//
+// Example :
+//
+// 	for book, err := range godb.Stream[Book](db.SelectFrom("books").Columns("id", "title")) {
+// 		if err != nil {
+// 			return err
+// 		}
+// 		…
+// 	}
+//
+// Warning : like DoWithIterator it does not use an existing transaction, nor
+// the prepared statement cache.
+func Stream[T any](query Iterable) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		iterator, err := query.DoWithIterator()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer iterator.Close()
+
+		for iterator.Next() {
+			var record T
+			if err := iterator.Scan(&record); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+
+		if err := iterator.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// ForEach executes the given query and calls f for each row mapped to a T.
+// The iteration stops at the first error, either returned by f or by the
+// query execution.
+func ForEach[T any](query Iterable, f func(T) error) error {
+	for record, err := range Stream[T](query) {
+		if err != nil {
+			return err
+		}
+		if err = f(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All executes the given query and returns all rows mapped to a slice of T.
+func All[T any](query Iterable) ([]T, error) {
+	records := make([]T, 0)
+	for record, err := range Stream[T](query) {
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// One executes the given query and returns the first row mapped to a T.
+// It returns sql.ErrNoRows if there is none, like QueryRow in database/sql
+// package.
+// One does not add a LIMIT clause, the remaining rows are just discarded.
+func One[T any](query Iterable) (T, error) {
+	for record, err := range Stream[T](query) {
+		return record, err
+	}
+	var zero T
+	return zero, sql.ErrNoRows
+}
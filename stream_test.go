@@ -0,0 +1,121 @@
+package godb
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStream(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Stream iterates over rows of a SelectStatement", func() {
+			query := db.SelectFrom("dummies").
+				Columns("id", "a_text", "an_integer").
+				OrderBy("an_integer")
+
+			integers := make([]int, 0)
+			for dummy, err := range Stream[Dummy](query) {
+				So(err, ShouldBeNil)
+				integers = append(integers, dummy.AnInteger)
+			}
+			So(integers, ShouldResemble, []int{11, 12, 13})
+		})
+
+		Convey("Stream iterates over rows of a StructSelect", func() {
+			dummies := make([]Dummy, 0)
+			query := db.Select(&dummies).OrderBy("an_integer")
+
+			count := 0
+			for dummy, err := range Stream[Dummy](query) {
+				So(err, ShouldBeNil)
+				So(dummy.ID, ShouldBeGreaterThan, 0)
+				count++
+			}
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Stream iterates over rows of a RawSQL", func() {
+			query := db.RawSQL("select * from dummies order by an_integer")
+
+			texts := make([]string, 0)
+			for dummy, err := range Stream[Dummy](query) {
+				So(err, ShouldBeNil)
+				texts = append(texts, dummy.AText)
+			}
+			So(texts, ShouldResemble, []string{"First", "Second", "Third"})
+		})
+
+		Convey("Stream can be stopped with a break", func() {
+			query := db.RawSQL("select * from dummies order by an_integer")
+
+			count := 0
+			for _, err := range Stream[Dummy](query) {
+				So(err, ShouldBeNil)
+				So(db.CurrentDB().Stats().InUse, ShouldEqual, 1)
+				count++
+				break
+			}
+			So(count, ShouldEqual, 1)
+			// The rows were closed, the connection is back in the pool
+			So(db.CurrentDB().Stats().InUse, ShouldEqual, 0)
+		})
+
+		Convey("Stream yields the query error", func() {
+			query := db.RawSQL("select * from nowhere")
+
+			count := 0
+			for _, err := range Stream[Dummy](query) {
+				So(err, ShouldNotBeNil)
+				count++
+			}
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("ForEach calls the function for each row", func() {
+			query := db.RawSQL("select * from dummies")
+
+			sum := 0
+			err := ForEach(query, func(dummy Dummy) error {
+				sum += dummy.AnInteger
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(sum, ShouldEqual, 36)
+		})
+
+		Convey("ForEach stops at the first error returned by the function", func() {
+			query := db.RawSQL("select * from dummies")
+
+			count := 0
+			err := ForEach(query, func(dummy Dummy) error {
+				count++
+				return fmt.Errorf("stop")
+			})
+			So(err, ShouldNotBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("All returns all rows", func() {
+			dummies, err := All[Dummy](db.RawSQL("select * from dummies order by an_integer"))
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+			So(dummies[2].AText, ShouldEqual, "Third")
+		})
+
+		Convey("One returns the first row", func() {
+			dummy, err := One[Dummy](db.RawSQL("select * from dummies where an_integer = ?", 12))
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Second")
+		})
+
+		Convey("One returns sql.ErrNoRows if there is no row", func() {
+			_, err := One[Dummy](db.RawSQL("select * from dummies where an_integer = ?", 123))
+			So(err, ShouldEqual, sql.ErrNoRows)
+		})
+	})
+}
@@ -30,11 +30,25 @@ func (db *DB) Delete(record interface{}) *StructDelete {
 		return sd
 	}
 
-	quotedTableName := db.quote(db.defaultTableNamer(sd.recordDescription.getTableName()))
+	if err := db.registerTenantRecord(sd.recordDescription); err != nil {
+		sd.error = err
+		return sd
+	}
+	quotedTableName := db.quote(db.tableName(sd.recordDescription))
 	sd.deleteStatement = db.DeleteFrom(quotedTableName)
 	return sd
 }
 
+// Clone returns a deep copy of the statement, see SelectStatement.Clone.
+// The clone targets the same record.
+func (sd *StructDelete) Clone() *StructDelete {
+	clone := *sd
+	if sd.deleteStatement != nil {
+		clone.deleteStatement = sd.deleteStatement.Clone()
+	}
+	return &clone
+}
+
 // Do executes the DELETE statement for the struct given to the Delete method,
 // and returns the count of deleted rows and an error.
 func (sd *StructDelete) Do() (int64, error) {
@@ -42,6 +56,9 @@ func (sd *StructDelete) Do() (int64, error) {
 		return 0, sd.error
 	}
 
+	// Work on a copy, the statement stays reusable
+	deleteStatement := sd.deleteStatement.Clone()
+
 	// Keys
 	keyColumns := sd.recordDescription.structMapping.GetKeyColumnsNames()
 	keyValues := sd.recordDescription.structMapping.GetKeyFieldsValues(sd.recordDescription.record)
@@ -49,8 +66,8 @@ func (sd *StructDelete) Do() (int64, error) {
 		return 0, fmt.Errorf("the object of type %T has no key : ", sd.recordDescription.record)
 	}
 	for i, column := range keyColumns {
-		quotedColumn := sd.deleteStatement.db.quote(column)
-		sd.deleteStatement = sd.deleteStatement.Where(quotedColumn+" = ?", keyValues[i])
+		quotedColumn := deleteStatement.db.quote(column)
+		deleteStatement = deleteStatement.Where(quotedColumn+" = ?", keyValues[i])
 	}
 
 	// Optimistic Locking
@@ -60,11 +77,11 @@ func (sd *StructDelete) Do() (int64, error) {
 		if err != nil {
 			return 0, err
 		}
-		sd.deleteStatement = sd.deleteStatement.Where(opLockColumn+" = ?", opLockValue)
+		deleteStatement = deleteStatement.Where(opLockColumn+" = ?", opLockValue)
 	}
 
 	// Executes the query
-	rowsAffected, err := sd.deleteStatement.Do()
+	rowsAffected, err := deleteStatement.Do()
 
 	if opLockColumn != "" && rowsAffected == 0 {
 		err = ErrOpLock
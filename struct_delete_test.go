@@ -44,3 +44,26 @@ func TestDeleteDo(t *testing.T) {
 		})
 	})
 }
+
+func TestStructDeleteClone(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Changes to the clone does not alter the original", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("an_integer = ?", 11).Do()
+			So(err, ShouldBeNil)
+
+			q := db.Delete(&dummy)
+			clone := q.Clone().WithoutTenant()
+			So(clone.deleteStatement, ShouldNotPointTo, q.deleteStatement)
+			So(clone.deleteStatement.withoutTenant, ShouldBeTrue)
+			So(q.deleteStatement.withoutTenant, ShouldBeFalse)
+
+			count, err := q.Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+}
@@ -3,8 +3,8 @@ package godb
 import (
 	"database/sql"
 	"fmt"
+	"slices"
 
-	"github.com/samonzeweb/godb/adapters"
 	"github.com/samonzeweb/godb/types"
 )
 
@@ -36,9 +36,9 @@ func (db *DB) Insert(record interface{}) *StructInsert {
 
 // BulkInsert initializes an INSERT sql statement for a slice.
 //
-// Warning : not all databases are able to update the auto columns in the
-// case of insert with multiple rows. Only adapters implementing the
-// InsertReturningSuffix interface will have auto columns updated.
+// The auto columns are updated for all rows. Without RETURNING clause (see
+// adapters.Capabilities), the rows of a slice having an auto key are
+// inserted one by one in a transaction, to get their ids.
 func (db *DB) BulkInsert(record interface{}) *StructInsert {
 	si := db.buildInsert(record)
 
@@ -62,11 +62,27 @@ func (db *DB) buildInsert(record interface{}) *StructInsert {
 		return si
 	}
 
-	quotedTableName := db.quote(db.defaultTableNamer(si.recordDescription.getTableName()))
+	if err := db.registerTenantRecord(si.recordDescription); err != nil {
+		si.error = err
+		return si
+	}
+	quotedTableName := db.quote(db.tableName(si.recordDescription))
 	si.insertStatement = db.InsertInto(quotedTableName)
 	return si
 }
 
+// Clone returns a deep copy of the statement, see SelectStatement.Clone.
+// The clone targets the same record.
+func (si *StructInsert) Clone() *StructInsert {
+	clone := *si
+	if si.insertStatement != nil {
+		clone.insertStatement = si.insertStatement.Clone()
+	}
+	clone.whiteList = slices.Clone(si.whiteList)
+	clone.blackList = slices.Clone(si.blackList)
+	return &clone
+}
+
 // Whitelist saves columns to be inserted from struct
 // It adds columns to list each time it is called
 // whitelist should not include auto key tagged columns
@@ -108,10 +124,13 @@ func (si *StructInsert) Do() error {
 		return si.error
 	}
 
+	// Work on a copy, the statement stays reusable
+	insertStatement := si.insertStatement.Clone()
+
 	// Columns names
 	var columns []string
 	if len(si.whiteList) > 0 {
-		columns = si.whiteList
+		columns = slices.Clone(si.whiteList)
 	} else {
 		columns = si.recordDescription.structMapping.GetNonAutoColumnsNames()
 	}
@@ -130,18 +149,21 @@ func (si *StructInsert) Do() error {
 
 	hasWB := (len(si.whiteList) + len(si.blackList)) > 0
 	if !hasWB {
-		si.insertStatement = si.insertStatement.Columns(si.insertStatement.db.quoteAll(columns)...)
+		insertStatement = insertStatement.Columns(insertStatement.db.quoteAll(columns)...)
 	}
 	// Values
 	var values []interface{}
-	len := si.recordDescription.len()
+	recordsCount := si.recordDescription.len()
 	wbColsSet := false
-	for i := 0; i < len; i++ {
+	for i := 0; i < recordsCount; i++ {
 		currentRecord := si.recordDescription.index(i)
+		if err := insertStatement.db.setTenantFieldValue(si.recordDescription, currentRecord); err != nil {
+			return err
+		}
 		if hasWB {
 			if !wbColsSet { // order of old columns list and current values list may not be same so, set here:
 				columns, values = si.recordDescription.structMapping.GetNonAutoFieldsValuesFiltered(currentRecord, columns, false)
-				si.insertStatement = si.insertStatement.Columns(si.insertStatement.db.quoteAll(columns)...)
+				insertStatement = insertStatement.Columns(insertStatement.db.quoteAll(columns)...)
 				wbColsSet = true
 			} else {
 				// as columns are already ordered, just get values in same order
@@ -150,14 +172,14 @@ func (si *StructInsert) Do() error {
 		} else {
 			values = si.recordDescription.structMapping.GetNonAutoFieldsValues(currentRecord)
 		}
-		si.insertStatement.Values(values...)
+		insertStatement.Values(values...)
 	}
 
 	// Use a RETURNING (or similar) clause ?
-	returningBuilder, ok := si.insertStatement.db.adapter.(adapters.ReturningBuilder)
-	if ok {
+	returningBuilder := returningBuilderOf(insertStatement.db.adapter, false)
+	if returningBuilder != nil {
 		autoColumns := si.recordDescription.structMapping.GetAutoColumnsNames()
-		si.insertStatement.Returning(returningBuilder.FormatForNewValues(autoColumns)...)
+		insertStatement.Returning(returningBuilder.FormatForNewValues(autoColumns)...)
 	}
 
 	// Run
@@ -167,29 +189,35 @@ func (si *StructInsert) Do() error {
 			pointers, err := si.recordDescription.structMapping.GetAutoFieldsPointers(record)
 			return pointers, err
 		}
-		_, err := si.insertStatement.doWithReturning(si.recordDescription, f)
-		return err
-	}
-
-	// Case for adapters not implenting ReturningSuffix(), we use the
-	// value given by LastInsertId() (through Do method)
-	insertedID, err := si.insertStatement.Do()
-	if err != nil {
+		_, err := insertStatement.doWithReturning(si.recordDescription, f)
 		return err
 	}
 
-	// Bulk insert don't update ids with this adater, the insert was done,
-	// without error, but the new ids are unknown.
-	if si.recordDescription.isSlice {
-		return nil
+	// Case for adapters not supporting RETURNING, the auto keys are set
+	// with the value given by LastInsertId() (through Do method), and the
+	// other auto columns are selected again in the same transaction.
+	// With an auto key, the rows of a bulk insert are inserted one by one
+	// in the transaction (see insertAndSetAutoKeys).
+	autoColumns := autoColumnsToRefresh(si.recordDescription)
+	if len(autoColumns) == 0 && !(si.recordDescription.isSlice && hasAutoKey(si.recordDescription)) {
+		return insertStatement.db.insertAndSetAutoKeys(insertStatement, si.recordDescription)
 	}
 
-	// Get the Id
-	pointerToID, err := si.recordDescription.structMapping.GetAutoKeyPointer(si.recordDescription.record)
-	if err != nil {
-		return err
-	}
+	return insertStatement.db.withTransaction(func(db *DB) error {
+		insertStatement.db = db
+		if err := db.insertAndSetAutoKeys(insertStatement, si.recordDescription); err != nil {
+			return err
+		}
+		if len(autoColumns) == 0 {
+			return nil
+		}
+		return db.refreshAutoColumns(insertStatement.intoTable, si.recordDescription, autoColumns)
+	})
+}
 
+// setAutoKey sets the auto key pointed by pointerToID (if any) with the
+// given id.
+func setAutoKey(pointerToID interface{}, insertedID int64) error {
 	if pointerToID != nil {
 		switch t := pointerToID.(type) {
 		default:
@@ -193,3 +193,28 @@ func TestBulkInsertDo(t *testing.T) {
 	})
 
 }
+
+func TestStructInsertClone(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Changes to the clone does not alter the original", func() {
+			dummy := Dummy{AText: "Clone", AnotherText: "Clone", AnInteger: 20}
+			q := db.Insert(&dummy).Blacklist("id")
+			clone := q.Clone().Blacklist("an_integer").Whitelist("a_text")
+			So(clone.blackList, ShouldResemble, []string{"id", "an_integer"})
+			So(q.blackList, ShouldResemble, []string{"id"})
+			So(q.whiteList, ShouldBeEmpty)
+			So(clone.insertStatement, ShouldNotPointTo, q.insertStatement)
+
+			err := q.Do()
+			So(err, ShouldBeNil)
+			retrieved := Dummy{}
+			err = db.Select(&retrieved).Where("id = ?", dummy.ID).Do()
+			So(err, ShouldBeNil)
+			So(retrieved.AText, ShouldEqual, "Clone")
+			So(retrieved.AnInteger, ShouldEqual, 20)
+		})
+	})
+}
@@ -11,6 +11,7 @@ type StructSelect struct {
 	error             error
 	selectStatement   *SelectStatement
 	recordDescription *recordDescription
+	unscoped          bool
 }
 
 // Select initializes a SELECT statement with the given pointer as
@@ -24,17 +25,31 @@ func (db *DB) Select(record interface{}) *StructSelect {
 		ss.error = err
 		return ss
 	}
-	quotedTableName := db.quote(db.defaultTableNamer(ss.recordDescription.getTableName()))
+	if err := db.registerTenantRecord(ss.recordDescription); err != nil {
+		ss.error = err
+		return ss
+	}
+	quotedTableName := db.quote(db.tableName(ss.recordDescription))
 	ss.selectStatement = db.SelectFrom(quotedTableName)
 	return ss
 }
 
+// Clone returns a deep copy of the statement, see SelectStatement.Clone.
+// The clone targets the same record.
+func (ss *StructSelect) Clone() *StructSelect {
+	clone := *ss
+	if ss.selectStatement != nil {
+		clone.selectStatement = ss.selectStatement.Clone()
+	}
+	return &clone
+}
+
 // Where adds a condition using string and arguments.
 func (ss *StructSelect) Where(sql string, args ...interface{}) *StructSelect {
 	if ss.error != nil {
 		return ss
 	}
-	ss.selectStatement = ss.selectStatement.WhereQ(Q(sql, args...))
+	ss.selectStatement = ss.selectStatement.WhereQ(ss.selectStatement.db.q(sql, args...))
 	return ss
 }
 
@@ -48,6 +63,16 @@ func (ss *StructSelect) WhereQ(condition *Condition) *StructSelect {
 	return ss
 }
 
+// WhereStruct adds an equality condition for each non zero field of the given
+// struct pointer (see SelectStatement.WhereStruct).
+func (ss *StructSelect) WhereStruct(record interface{}) *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement = ss.selectStatement.WhereStruct(record)
+	return ss
+}
+
 // OrderBy adds an expression for the ORDER BY clause.
 func (ss *StructSelect) OrderBy(orderBy string) *StructSelect {
 	if ss.error != nil {
@@ -82,16 +107,31 @@ func (ss *StructSelect) Do() error {
 		return ss.error
 	}
 
-	// Columns names
-	allColumns := ss.recordDescription.structMapping.GetAllColumnsNames()
-	ss.selectStatement = ss.selectStatement.Columns(ss.selectStatement.db.quoteAll(allColumns)...)
-
+	statement := ss.statementWithColumns()
 	f := func(record interface{}, columns []string) ([]interface{}, error) {
 		pointers := ss.recordDescription.structMapping.GetAllFieldsPointers(record)
 		return pointers, nil
 	}
 
-	return ss.selectStatement.do(ss.recordDescription, f)
+	return statement.do(ss.recordDescription, f)
+}
+
+// statementWithColumns returns a scoped copy of the select statement, with
+// all the columns of the struct.
+func (ss *StructSelect) statementWithColumns() *SelectStatement {
+	allColumns := ss.recordDescription.structMapping.GetAllColumnsNames()
+	return ss.scopedStatement().
+		Columns(ss.selectStatement.db.quoteAll(allColumns)...)
+}
+
+// scopedStatement returns a copy of the select statement, with the default
+// scopes of the struct applied (unless Unscoped was called).
+func (ss *StructSelect) scopedStatement() *SelectStatement {
+	statement := ss.selectStatement.Clone()
+	if ss.unscoped {
+		return statement
+	}
+	return statement.Scopes(ss.recordDescription.getDefaultScopes()...)
 }
 
 // Count run the request with COUNT(*) and returns the count
@@ -100,7 +140,18 @@ func (ss *StructSelect) Count() (int64, error) {
 		return 0, ss.error
 	}
 
-	return ss.selectStatement.Count()
+	return ss.scopedStatement().Count()
+}
+
+// DoPage counts all rows matching the statement, then fetches only the
+// rows of the given page (starting at 1) into the slice given to Select.
+// See SelectStatement.DoPage.
+func (ss *StructSelect) DoPage(page int, pageSize int) (*Page, error) {
+	if ss.error != nil {
+		return nil, ss.error
+	}
+
+	return ss.scopedStatement().DoPage(ss.recordDescription.record, page, pageSize)
 }
 
 // DoWithIterator executes the select query and returns an Iterator allowing
@@ -112,13 +163,10 @@ func (ss *StructSelect) DoWithIterator() (Iterator, error) {
 		return nil, ss.error
 	}
 
-	allColumns := ss.recordDescription.structMapping.GetAllColumnsNames()
-	ss.selectStatement = ss.selectStatement.Columns(ss.selectStatement.db.quoteAll(allColumns)...)
-
-	sqlQuery, args, err := ss.selectStatement.ToSQL()
+	sqlQuery, args, err := ss.statementWithColumns().ToSQL()
 	if err != nil {
 		return nil, err
 	}
 
-	return ss.selectStatement.db.doWithIterator(sqlQuery, args)
+	return ss.selectStatement.db.doWithIterator(ss.selectStatement.queryInfo(), sqlQuery, args)
 }
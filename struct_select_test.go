@@ -116,3 +116,25 @@ func TestSelectDoWithIteratorWithStruct(t *testing.T) {
 		})
 	})
 }
+
+func TestStructSelectClone(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Changes to the clone does not alter the original", func() {
+			dummies := make([]Dummy, 0)
+			q := db.Select(&dummies).Where("an_integer > ?", 10)
+			clone := q.Clone().Where("an_integer < ?", 12)
+
+			err := q.Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+
+			dummies = dummies[:0]
+			err = clone.Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 1)
+		})
+	})
+}
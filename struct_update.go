@@ -2,8 +2,7 @@ package godb
 
 import (
 	"fmt"
-
-	"github.com/samonzeweb/godb/adapters"
+	"slices"
 )
 
 // StructUpdate builds an UPDATE statement for the given object.
@@ -36,11 +35,27 @@ func (db *DB) Update(record interface{}) *StructUpdate {
 		return su
 	}
 
-	quotedTableName := db.quote(db.defaultTableNamer(su.recordDescription.getTableName()))
+	if err := db.registerTenantRecord(su.recordDescription); err != nil {
+		su.error = err
+		return su
+	}
+	quotedTableName := db.quote(db.tableName(su.recordDescription))
 	su.updateStatement = db.UpdateTable(quotedTableName)
 	return su
 }
 
+// Clone returns a deep copy of the statement, see SelectStatement.Clone.
+// The clone targets the same record.
+func (su *StructUpdate) Clone() *StructUpdate {
+	clone := *su
+	if su.updateStatement != nil {
+		clone.updateStatement = su.updateStatement.Clone()
+	}
+	clone.whiteList = slices.Clone(su.whiteList)
+	clone.blackList = slices.Clone(su.blackList)
+	return &clone
+}
+
 // Whitelist saves columns to be updated from struct
 //
 // whitelist should not include auto key tagged columns
@@ -85,16 +100,24 @@ func (su *StructUpdate) Do() error {
 		return su.error
 	}
 
+	// Work on a copy, the statement stays reusable
+	updateStatement := su.updateStatement.Clone()
+
 	// Which columns to update ?
 	var columnsToUpdate []string
 	if len(su.whiteList) > 0 {
-		columnsToUpdate = su.whiteList
+		columnsToUpdate = slices.Clone(su.whiteList)
 	} else {
 		columnsToUpdate = su.recordDescription.structMapping.GetNonAutoColumnsNames()
 	}
-	// Filter black listed columns
+	// Filter black listed columns, and the tenant column which can't be
+	// changed (see UpdateStatement)
+	blackList := su.blackList
+	if tenantColumn := su.recordDescription.structMapping.GetTenantSQLFieldName(); tenantColumn != "" && !updateStatement.withoutTenant {
+		blackList = append(blackList[:len(blackList):len(blackList)], tenantColumn)
+	}
 	i := 0
-	for _, c := range su.blackList {
+	for _, c := range blackList {
 		i = 0
 		for _, a := range columnsToUpdate {
 			if a != c {
@@ -105,10 +128,13 @@ func (su *StructUpdate) Do() error {
 		columnsToUpdate = columnsToUpdate[:i]
 	}
 
+	if err := updateStatement.db.setTenantFieldValue(su.recordDescription, su.recordDescription.record); err != nil {
+		return err
+	}
 	columns, values := su.recordDescription.structMapping.GetNonAutoFieldsValuesFiltered(su.recordDescription.record, columnsToUpdate, false)
 	for i, column := range columns {
-		quotedColumn := su.updateStatement.db.quote(column)
-		su.updateStatement = su.updateStatement.Set(quotedColumn, values[i])
+		quotedColumn := updateStatement.db.quote(column)
+		updateStatement = updateStatement.Set(quotedColumn, values[i])
 	}
 
 	// On which keys
@@ -118,8 +144,8 @@ func (su *StructUpdate) Do() error {
 		return fmt.Errorf("the object of type %T has no key : ", su.recordDescription.record)
 	}
 	for i, column := range keyColumns {
-		quotedColumn := su.updateStatement.db.quote(column)
-		su.updateStatement = su.updateStatement.Where(quotedColumn+" = ?", keyValues[i])
+		quotedColumn := updateStatement.db.quote(column)
+		updateStatement = updateStatement.Where(quotedColumn+" = ?", keyValues[i])
 	}
 
 	// Optimistic Locking
@@ -129,14 +155,14 @@ func (su *StructUpdate) Do() error {
 		if err != nil {
 			return err
 		}
-		su.updateStatement = su.updateStatement.Where(opLockColumn+" = ?", opLockValue)
+		updateStatement = updateStatement.Where(opLockColumn+" = ?", opLockValue)
 	}
 
 	// Use a RETURNING (or similar) clause ?
-	returningBuilder, ok := su.updateStatement.db.adapter.(adapters.ReturningBuilder)
-	if ok {
+	returningBuilder := returningBuilderOf(updateStatement.db.adapter, true)
+	if returningBuilder != nil {
 		autoColumns := su.recordDescription.structMapping.GetAutoColumnsNames()
-		su.updateStatement.Returning(returningBuilder.FormatForNewValues(autoColumns)...)
+		updateStatement.Returning(returningBuilder.FormatForNewValues(autoColumns)...)
 	}
 
 	var rowsAffected int64
@@ -149,10 +175,24 @@ func (su *StructUpdate) Do() error {
 			return pointers, err
 		}
 		// Case for adapters implenting ReturningSuffix()
-		rowsAffected, err = su.updateStatement.doWithReturning(su.recordDescription, f)
+		rowsAffected, err = updateStatement.doWithReturning(su.recordDescription, f)
+	} else if autoColumns := autoColumnsToRefresh(su.recordDescription); len(autoColumns) > 0 {
+		// Case for adapters not supporting RETURNING, the auto columns
+		// are selected again in the same transaction
+		err = updateStatement.db.withTransaction(func(db *DB) error {
+			updateStatement.db = db
+			rowsAffected, err = updateStatement.Do()
+			if err != nil || rowsAffected == 0 {
+				return err
+			}
+			return db.refreshAutoColumns(updateStatement.updateTable, su.recordDescription, autoColumns)
+		})
+		if err != nil {
+			return err
+		}
 	} else {
 		// Case for adapters not implenting ReturningSuffix()
-		rowsAffected, err = su.updateStatement.Do()
+		rowsAffected, err = updateStatement.Do()
 		if err != nil {
 			return err
 		}
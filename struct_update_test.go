@@ -107,3 +107,33 @@ func TestUpdateDo(t *testing.T) {
 
 	})
 }
+
+func TestStructUpdateClone(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Changes to the clone does not alter the original", func() {
+			dummy := Dummy{}
+			err := db.Select(&dummy).Where("an_integer = ?", 11).Do()
+			So(err, ShouldBeNil)
+
+			dummy.AText = "Cloned"
+			dummy.AnInteger = 1453
+			q := db.Update(&dummy).Blacklist("a_text")
+			clone := q.Clone().Blacklist("an_integer").Whitelist("a_text")
+			So(clone.GetBlacklist(), ShouldResemble, []string{"a_text", "an_integer"})
+			So(q.GetBlacklist(), ShouldResemble, []string{"a_text"})
+			So(q.GetWhitelist(), ShouldBeEmpty)
+			So(clone.updateStatement, ShouldNotPointTo, q.updateStatement)
+
+			err = q.Do()
+			So(err, ShouldBeNil)
+			retrieved := Dummy{}
+			err = db.Select(&retrieved).Where("id = ?", dummy.ID).Do()
+			So(err, ShouldBeNil)
+			So(retrieved.AText, ShouldEqual, "First")
+			So(retrieved.AnInteger, ShouldEqual, 1453)
+		})
+	})
+}
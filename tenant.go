@@ -0,0 +1,570 @@
+package godb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrMissingTenant is returned when a statement uses a tenant table with a DB
+// having no tenant (see WithTenant), unless the statement is run without
+// tenant (see WithoutTenant).
+var ErrMissingTenant = errors.New("missing tenant for a tenant table")
+
+// ErrTenantModelsNotRegistered is returned by the statements built once
+// WithTenant was called, until the tenant tables are registered with
+// RegisterTenantModels, unless they are run without tenant. Without it, the tenant
+// tables can't be known before being used with structs.
+var ErrTenantModelsNotRegistered = errors.New("the tenant models are not registered")
+
+// tenant is the tenant column and value of a DB.
+type tenant struct {
+	column string
+	value  interface{}
+}
+
+// tenantTables is the registry of tables having a tenant column. It is
+// shared by a DB and its clones, and is thread safe.
+// The tables names are normalized (see normalizeTableName).
+type tenantTables struct {
+	mutex  sync.RWMutex
+	tables map[string]bool
+	// True once WithTenant was called
+	inUse bool
+	// True once RegisterTenantModels was called
+	registered bool
+}
+
+// newTenantTables creates an empty tenant tables registry.
+func newTenantTables() *tenantTables {
+	return &tenantTables{tables: make(map[string]bool)}
+}
+
+// add registers the given table names.
+func (tt *tenantTables) add(tableNames ...string) {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+	for _, tableName := range tableNames {
+		tt.tables[normalizeTableName(tableName)] = true
+	}
+}
+
+// setInUse records that WithTenant was called.
+func (tt *tenantTables) setInUse() {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+	tt.inUse = true
+}
+
+// setRegistered records that RegisterTenantModels was called.
+func (tt *tenantTables) setRegistered() {
+	tt.mutex.Lock()
+	defer tt.mutex.Unlock()
+	tt.registered = true
+}
+
+// contains returns true if the given table is registered. It returns
+// ErrTenantModelsNotRegistered if the tenants are used but the tenant tables
+// were not registered.
+func (tt *tenantTables) contains(tableName string) (bool, error) {
+	if tt == nil {
+		return false, nil
+	}
+	tt.mutex.RLock()
+	defer tt.mutex.RUnlock()
+	if tt.inUse && !tt.registered {
+		return false, ErrTenantModelsNotRegistered
+	}
+	return tt.tables[normalizeTableName(tableName)], nil
+}
+
+// normalizeTableName returns the name used to match the tenant tables : the
+// table name without its schema and quotes, in lower case. Tables having the
+// same name in different schemas are considered as the same table.
+func normalizeTableName(tableName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '"', '`', '[', ']':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(tableName))
+	return strings.ToLower(name[strings.LastIndex(name, ".")+1:])
+}
+
+// normalizeColumnName returns a column name without its qualifier and
+// quotes, in lower case.
+func normalizeColumnName(column string) string {
+	return normalizeTableName(column)
+}
+
+// WithTenant returns a clone of the DB (see Clone) restricted to the given
+// tenant. With it, all statements on tenant tables get a column = value
+// predicate, and the inserted rows get the tenant value.
+//
+// A tenant table is the table of a struct having a field tagged with the
+// tenant option, like `db:"tenant_id,tenant"`. All of them have to be
+// registered with RegisterTenantModels, otherwise the statements return
+// ErrTenantModelsNotRegistered once WithTenant was called. The tables are
+// matched by name, whatever the case, quotes and schema.
+//
+// Statements on tenant tables return ErrMissingTenant if the DB has no
+// tenant, unless they are run without tenant. The tenant column can't be
+// changed by UPDATE statements.
+//
+// Example :
+//
+// 	err := db.RegisterTenantModels(&Book{}, &Author{})
+// 	...
+// 	tenantDB := db.WithTenant("tenant_id", tenantID)
+// 	err = tenantDB.Select(&books).Do()
+func (db *DB) WithTenant(column string, value interface{}) *DB {
+	if db.tenantTables == nil {
+		db.tenantTables = newTenantTables()
+	}
+	db.tenantTables.setInUse()
+	clone := db.Clone()
+	clone.tenant = &tenant{column: column, value: value}
+	return clone
+}
+
+// Tenant returns the tenant value of the DB, or nil if there is none.
+func (db *DB) Tenant() interface{} {
+	if db.tenant == nil {
+		return nil
+	}
+	return db.tenant.value
+}
+
+// RegisterTenantModels registers the tables of the given structs as tenant
+// tables, it has to be called with all of them before using the tenants (see
+// WithTenant). Each struct must have a tenant field.
+func (db *DB) RegisterTenantModels(records ...interface{}) error {
+	if db.tenantTables == nil {
+		db.tenantTables = newTenantTables()
+	}
+	defer db.tenantTables.setRegistered()
+
+	for _, record := range records {
+		recordInfo, err := buildRecordDescription(record)
+		if err != nil {
+			return err
+		}
+		if recordInfo.structMapping.GetTenantSQLFieldName() == "" {
+			return fmt.Errorf("the struct %s has no tenant field", recordInfo.structMapping.Name)
+		}
+		if err := db.registerTenantRecord(recordInfo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerTenantRecord registers the table of the given record if it has
+// a tenant field. It returns an error if the tenant field does not match
+// the tenant column of the DB.
+func (db *DB) registerTenantRecord(recordInfo *recordDescription) error {
+	tenantColumn := recordInfo.structMapping.GetTenantSQLFieldName()
+	if tenantColumn == "" {
+		return nil
+	}
+	if db.tenant != nil && db.tenant.column != tenantColumn {
+		return fmt.Errorf("the tenant field %s of the struct %s does not match the tenant column %s",
+			tenantColumn, recordInfo.structMapping.Name, db.tenant.column)
+	}
+
+	if db.tenantTables == nil {
+		db.tenantTables = newTenantTables()
+	}
+	db.tenantTables.add(db.tableName(recordInfo))
+	return nil
+}
+
+// isTenantTable returns true if the given table is a tenant table, and the
+// statement isn't run without tenant.
+func (db *DB) isTenantTable(tableName string, withoutTenant bool) (bool, error) {
+	if withoutTenant {
+		return false, nil
+	}
+	isTenantTable, err := db.tenantTables.contains(tableName)
+	if err != nil || !isTenantTable {
+		return false, err
+	}
+	if db.tenant == nil {
+		return false, ErrMissingTenant
+	}
+	return true, nil
+}
+
+// tenantCondition returns the tenant predicate for the given table (and its
+// alias if any). It returns nil if the table is not a tenant table or if
+// the statement is run without tenant.
+func (db *DB) tenantCondition(tableName string, alias string, withoutTenant bool) *Condition {
+	isTenantTable, err := db.isTenantTable(tableName, withoutTenant)
+	if err != nil {
+		return &Condition{err: err}
+	}
+	if !isTenantTable {
+		return nil
+	}
+
+	qualifier := tableName
+	if alias != "" {
+		qualifier = alias
+	}
+	return Q(qualifier+"."+db.quote(db.tenant.column)+" = ?", db.tenant.value)
+}
+
+// tablesTenantConditions returns the tenant predicates of the tables of the
+// given FROM parts, like "books", "books b", "books AS b" or a list of them
+// separated by commas.
+// If the tenants are used, a FROM part which can't be parsed (ie a subquery)
+// gives an error, unless the statement is run without tenant.
+func (db *DB) tablesTenantConditions(fromParts []string, withoutTenant bool) []*Condition {
+	if withoutTenant || db.tenantTables == nil {
+		return nil
+	}
+
+	var conditions []*Condition
+	for _, fromPart := range fromParts {
+		for _, from := range splitFromList(fromPart) {
+			tableName, alias, ok := splitTableAlias(from)
+			if !ok {
+				if db.tenant != nil || db.tenantTables.isInUse() {
+					return []*Condition{{err: fmt.Errorf("the tenant of %q can't be checked, use a join or WithoutTenant", from)}}
+				}
+				continue
+			}
+			if condition := db.tenantCondition(tableName, alias, withoutTenant); condition != nil {
+				conditions = append(conditions, condition)
+			}
+		}
+	}
+	return conditions
+}
+
+// isInUse returns true if WithTenant was called.
+func (tt *tenantTables) isInUse() bool {
+	tt.mutex.RLock()
+	defer tt.mutex.RUnlock()
+	return tt.inUse
+}
+
+// splitFromList splits a list of FROM parts separated by commas, ignoring
+// the commas in parentheses.
+func splitFromList(fromList string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range fromList {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, fromList[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, fromList[start:])
+}
+
+// splitTableAlias splits a FROM part like "books", "books b" or
+// "books AS b" into the table name and its alias. It returns false if the
+// FROM part isn't a table, like a subquery.
+func splitTableAlias(from string) (string, string, bool) {
+	parts := strings.Fields(from)
+	if len(parts) == 0 || strings.ContainsAny(parts[0], "()") {
+		return "", "", false
+	}
+	switch {
+	case len(parts) == 1:
+		return parts[0], "", true
+	case len(parts) == 2:
+		return parts[0], parts[1], true
+	case len(parts) == 3 && strings.EqualFold(parts[1], "AS"):
+		return parts[0], parts[2], true
+	default:
+		return "", "", false
+	}
+}
+
+// withTenantConditions returns the tenant conditions followed by the
+// given conditions. These are surrounded with parentheses, a raw condition
+// with an OR can't escape the tenant filtering.
+func withTenantConditions(tenantConditions []*Condition, conditions []*Condition) []*Condition {
+	if len(conditions) == 0 {
+		return tenantConditions
+	}
+	return append(tenantConditions, parenthesize(And(conditions...)))
+}
+
+// parenthesize surrounds the given condition with parentheses.
+func parenthesize(condition *Condition) *Condition {
+	if condition.err != nil {
+		return condition
+	}
+	return &Condition{
+		sql:  "(" + condition.sql + ")",
+		args: condition.args,
+	}
+}
+
+// WithoutTenant disables the tenant filtering of the statement.
+func (ss *SelectStatement) WithoutTenant() *SelectStatement {
+	ss.withoutTenant = true
+	return ss
+}
+
+// withTenant returns the statement, or a copy of it with the tenant
+// predicates for the tenant tables.
+func (ss *SelectStatement) withTenant() *SelectStatement {
+	where := ss.db.tablesTenantConditions(ss.fromTables, ss.withoutTenant)
+
+	var joins []*joinPart
+	for i, join := range ss.joins {
+		condition := ss.db.tenantCondition(join.tableName, join.as, ss.withoutTenant)
+		if condition == nil {
+			continue
+		}
+		if joins == nil {
+			joins = cloneJoins(ss.joins)
+		}
+		// In the ON clause, to keep the outer joins semantic
+		if joins[i].on == nil {
+			joins[i].on = condition
+		} else {
+			joins[i].on = And(parenthesize(joins[i].on), condition)
+		}
+	}
+
+	if where == nil && joins == nil {
+		return ss
+	}
+
+	statement := *ss
+	if where != nil {
+		statement.where = withTenantConditions(where, ss.where)
+	}
+	if joins != nil {
+		statement.joins = joins
+	}
+	return &statement
+}
+
+// WithoutTenant disables the tenant filtering of the statement.
+func (us *UpdateStatement) WithoutTenant() *UpdateStatement {
+	us.withoutTenant = true
+	return us
+}
+
+// tenantWhere returns the where conditions with the tenant predicates of the
+// updated table and the other ones if needed. The joins are inner joins,
+// their predicates are in the WHERE clause.
+func (us *UpdateStatement) tenantWhere() []*Condition {
+	conditions := us.db.tablesTenantConditions(append([]string{us.updateTable}, us.fromTables...), us.withoutTenant)
+	conditions = append(conditions, us.db.joinsTenantConditions(us.joins, us.withoutTenant)...)
+	if len(conditions) == 0 {
+		return us.where
+	}
+	return withTenantConditions(conditions, us.where)
+}
+
+// checkTenantSets returns an error if the tenant column of a tenant table is
+// changed, the rows can't be moved to another tenant.
+func (us *UpdateStatement) checkTenantSets() error {
+	if us.withoutTenant || us.db.tenant == nil {
+		return nil
+	}
+	tableName, _, _ := splitTableAlias(us.updateTable)
+	isTenantTable, err := us.db.isTenantTable(tableName, us.withoutTenant)
+	if err != nil || !isTenantTable {
+		return err
+	}
+
+	tenantColumn := normalizeColumnName(us.db.tenant.column)
+	for _, set := range us.sets {
+		column := set.column
+		if set.value == nil {
+			// Raw clause, like "tenant_id = 2"
+			column, _, _ = strings.Cut(column, "=")
+		}
+		if normalizeColumnName(column) == tenantColumn {
+			return fmt.Errorf("the tenant column %s can't be updated", us.db.tenant.column)
+		}
+	}
+	return nil
+}
+
+// WithoutTenant disables the tenant filtering of the statement.
+func (ds *DeleteStatement) WithoutTenant() *DeleteStatement {
+	ds.withoutTenant = true
+	return ds
+}
+
+// tenantWhere returns the where conditions with the tenant predicates of the
+// table and the other ones if needed (see UpdateStatement.tenantWhere).
+func (ds *DeleteStatement) tenantWhere() []*Condition {
+	conditions := ds.db.tablesTenantConditions(append([]string{ds.fromTable}, ds.usingTables...), ds.withoutTenant)
+	conditions = append(conditions, ds.db.joinsTenantConditions(ds.joins, ds.withoutTenant)...)
+	if len(conditions) == 0 {
+		return ds.where
+	}
+	return withTenantConditions(conditions, ds.where)
+}
+
+// joinsTenantConditions returns the tenant predicates of the joined tables.
+func (db *DB) joinsTenantConditions(joins []*joinPart, withoutTenant bool) []*Condition {
+	var conditions []*Condition
+	for _, join := range joins {
+		if condition := db.tenantCondition(join.tableName, join.as, withoutTenant); condition != nil {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+// WithoutTenant disables the tenant value enforcement of the statement.
+func (is *InsertStatement) WithoutTenant() *InsertStatement {
+	is.withoutTenant = true
+	return is
+}
+
+// tenantColumnsAndValues returns the columns and values to insert, with
+// the tenant column and value if needed. An existing tenant value is
+// replaced by the DB one.
+func (is *InsertStatement) tenantColumnsAndValues() ([]string, [][]interface{}, error) {
+	isTenantTable, err := is.db.isTenantTable(is.intoTable, is.withoutTenant)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isTenantTable {
+		return is.columns, is.values, nil
+	}
+
+	quotedColumn := is.db.quote(is.db.tenant.column)
+	tenantColumn := normalizeColumnName(is.db.tenant.column)
+	if err := is.checkTenantUpsert(tenantColumn); err != nil {
+		return nil, nil, err
+	}
+	index := -1
+	for i, column := range is.columns {
+		if normalizeColumnName(column) == tenantColumn {
+			index = i
+			break
+		}
+	}
+
+	columns := is.columns
+	if index == -1 {
+		index = len(columns)
+		columns = append(columns[:len(columns):len(columns)], quotedColumn)
+	}
+
+	values := make([][]interface{}, 0, len(is.values))
+	for _, rowValues := range is.values {
+		if len(rowValues) != len(is.columns) {
+			// Let the buffer report the mismatch
+			values = append(values, rowValues)
+			continue
+		}
+		tenantRowValues := make([]interface{}, len(columns))
+		copy(tenantRowValues, rowValues)
+		tenantRowValues[index] = is.db.tenant.value
+		values = append(values, tenantRowValues)
+	}
+
+	return columns, values, nil
+}
+
+// checkTenantUpsert returns an error if an upsert could change the tenant of
+// an existing row.
+func (is *InsertStatement) checkTenantUpsert(tenantColumn string) error {
+	if is.verb == "UPSERT " {
+		return fmt.Errorf("UPSERT INTO can't be used with a tenant table, use OnConflictUpdate")
+	}
+	if is.upsert == nil {
+		return nil
+	}
+	for _, column := range is.upsert.updateColumns {
+		if normalizeColumnName(column) == tenantColumn {
+			return fmt.Errorf("the tenant column %s can't be updated", is.db.tenant.column)
+		}
+	}
+	return nil
+}
+
+// tenantColumnsAndSelect returns the columns and the select statement of an
+// INSERT ... SELECT statement, with the tenant column and value if needed.
+// The tenant column can't be given, its value is set by the DB.
+func (is *InsertStatement) tenantColumnsAndSelect() ([]string, *SelectStatement, error) {
+	isTenantTable, err := is.db.isTenantTable(is.intoTable, is.withoutTenant)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isTenantTable {
+		return is.columns, is.fromSelect, nil
+	}
+
+	quotedColumn := is.db.quote(is.db.tenant.column)
+	tenantColumn := normalizeColumnName(is.db.tenant.column)
+	for _, column := range is.columns {
+		if normalizeColumnName(column) == tenantColumn {
+			return nil, nil, fmt.Errorf("the tenant column %s is set by the DB, don't insert it", is.db.tenant.column)
+		}
+	}
+
+	columns := append(is.columns[:len(is.columns):len(is.columns)], quotedColumn)
+	selectStatement := is.fromSelect.Clone().ColumnsQ(Q(Placeholder, is.db.tenant.value))
+	return columns, selectStatement, nil
+}
+
+// WithoutTenant disables the tenant filtering of the statement, the default
+// scopes of the struct are kept (see Unscoped).
+func (ss *StructSelect) WithoutTenant() *StructSelect {
+	if ss.error != nil {
+		return ss
+	}
+	ss.selectStatement.WithoutTenant()
+	return ss
+}
+
+// WithoutTenant disables the tenant filtering of the statement.
+func (si *StructInsert) WithoutTenant() *StructInsert {
+	if si.error != nil {
+		return si
+	}
+	si.insertStatement.WithoutTenant()
+	return si
+}
+
+// WithoutTenant disables the tenant filtering of the statement.
+func (su *StructUpdate) WithoutTenant() *StructUpdate {
+	if su.error != nil {
+		return su
+	}
+	su.updateStatement.WithoutTenant()
+	return su
+}
+
+// WithoutTenant disables the tenant filtering of the statement.
+func (sd *StructDelete) WithoutTenant() *StructDelete {
+	if sd.error != nil {
+		return sd
+	}
+	sd.deleteStatement.WithoutTenant()
+	return sd
+}
+
+// setTenantFieldValue sets the tenant field of the given record to the
+// tenant value of the DB, if both exist.
+func (db *DB) setTenantFieldValue(recordInfo *recordDescription, record interface{}) error {
+	if db.tenant == nil || recordInfo.structMapping.GetTenantSQLFieldName() == "" {
+		return nil
+	}
+	return recordInfo.structMapping.SetTenantFieldValue(record, db.tenant.value)
+}
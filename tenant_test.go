@@ -0,0 +1,341 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type TenantDummy struct {
+	ID       int    `db:"id,key,auto"`
+	TenantID int    `db:"tenant_id,tenant"`
+	AText    string `db:"a_text"`
+}
+
+func (*TenantDummy) TableName() string {
+	return "tenantdummies"
+}
+
+func tenantFixturesSetup(t *testing.T) *DB {
+	db := createInMemoryConnection(t)
+
+	createTable :=
+		`create table tenantdummies (
+		id                  integer not null primary key autoincrement,
+		tenant_id           integer not null,
+		a_text              text not null);
+	`
+	if _, err := db.CurrentDB().Exec(createTable); err != nil {
+		t.Fatal(err)
+	}
+
+	insertRows :=
+		`insert into tenantdummies (tenant_id, a_text) values (1, 'First');
+		insert into tenantdummies (tenant_id, a_text) values (1, 'Second');
+		insert into tenantdummies (tenant_id, a_text) values (2, 'Third');
+	`
+	if _, err := db.CurrentDB().Exec(insertRows); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestTenantStatements(t *testing.T) {
+	Convey("Given a DB with a tenant and a registered tenant table", t, func() {
+		db := Wrap(sqlite.Adapter, nil)
+		So(db.RegisterTenantModels(&TenantDummy{}), ShouldBeNil)
+		tenantDB := db.WithTenant("tenant_id", 1)
+
+		Convey("WithTenant returns a clone sharing the tenant tables", func() {
+			So(tenantDB, ShouldNotEqual, db)
+			So(tenantDB.Tenant(), ShouldEqual, 1)
+			So(db.Tenant(), ShouldBeNil)
+		})
+
+		Convey("SelectStatement gets the tenant predicate", func() {
+			sql, args, err := tenantDB.SelectFrom("tenantdummies").
+				Columns("id").
+				Where("a_text = ?", "foo").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM tenantdummies WHERE tenantdummies."tenant_id" = ? AND (a_text = ?)`)
+			So(args, ShouldResemble, []interface{}{1, "foo"})
+		})
+
+		Convey("SelectStatement uses the table alias", func() {
+			sql, _, err := tenantDB.SelectFrom("tenantdummies t").Columns("t.id").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT t.id FROM tenantdummies t WHERE t."tenant_id" = ?`)
+		})
+
+		Convey("SelectStatement adds the tenant predicate to the joins", func() {
+			sql, args, err := tenantDB.SelectFrom("others o").
+				Columns("o.id").
+				LeftJoin("tenantdummies", "t", Q("t.id = o.dummy_id")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT o.id FROM others o LEFT JOIN tenantdummies AS t ON (t.id = o.dummy_id) AND t."tenant_id" = ?`)
+			So(args, ShouldResemble, []interface{}{1})
+		})
+
+		Convey("Conditions with OR can't escape the tenant predicate", func() {
+			sql, args, err := tenantDB.SelectFrom("tenantdummies").
+				Columns("id").
+				Where("id = ? OR id = ?", 1, 2).
+				Where("a_text = ?", "foo").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT id FROM tenantdummies WHERE tenantdummies."tenant_id" = ? AND (id = ? OR id = ? AND a_text = ?)`)
+			So(args, ShouldResemble, []interface{}{1, 1, 2, "foo"})
+		})
+
+		Convey("UpdateStatement gets the tenant predicate", func() {
+			sql, args, err := tenantDB.UpdateTable("tenantdummies").Set("a_text", "foo").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `UPDATE tenantdummies SET a_text=? WHERE tenantdummies."tenant_id" = ?`)
+			So(args, ShouldResemble, []interface{}{"foo", 1})
+		})
+
+		Convey("DeleteStatement gets the tenant predicate", func() {
+			sql, _, err := tenantDB.DeleteFrom(`"tenantdummies"`).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `DELETE FROM "tenantdummies" WHERE "tenantdummies"."tenant_id" = ?`)
+		})
+
+		Convey("InsertStatement adds the tenant column", func() {
+			sql, args, err := tenantDB.InsertInto("tenantdummies").
+				Columns("a_text").
+				Values("foo").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `INSERT INTO tenantdummies (a_text, "tenant_id") VALUES (?, ?)`)
+			So(args, ShouldResemble, []interface{}{"foo", 1})
+		})
+
+		Convey("InsertStatement replaces the tenant value", func() {
+			_, args, err := tenantDB.InsertInto("tenantdummies").
+				Columns("tenant_id", "a_text").
+				Values(2, "foo").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(args, ShouldResemble, []interface{}{1, "foo"})
+		})
+
+		Convey("InsertStatement adds the tenant value to the selected rows", func() {
+			sql, args, err := tenantDB.InsertInto("tenantdummies").
+				Columns("a_text").
+				FromSelect(tenantDB.SelectFrom("tenantdummies").Columns("a_text").Where("id = ?", 3)).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `INSERT INTO tenantdummies (a_text, "tenant_id") SELECT a_text, ? FROM tenantdummies WHERE tenantdummies."tenant_id" = ? AND (id = ?)`)
+			So(args, ShouldResemble, []interface{}{1, 1, 3})
+
+			_, _, err = tenantDB.InsertInto("tenantdummies").
+				Columns("tenant_id", "a_text").
+				FromSelect(tenantDB.SelectFrom("tenantdummies").Columns("tenant_id", "a_text")).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Other tables are not filtered", func() {
+			sql, _, err := tenantDB.SelectFrom("others").Columns("id").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM others")
+		})
+
+		Convey("Statements on tenant tables return an error without tenant", func() {
+			_, _, err := db.SelectFrom("tenantdummies").Columns("id").ToSQL()
+			So(err, ShouldEqual, ErrMissingTenant)
+			_, _, err = db.UpdateTable("tenantdummies").Set("a_text", "foo").ToSQL()
+			So(err, ShouldEqual, ErrMissingTenant)
+			_, _, err = db.DeleteFrom("tenantdummies").ToSQL()
+			So(err, ShouldEqual, ErrMissingTenant)
+			_, _, err = db.InsertInto("tenantdummies").Columns("a_text").Values("foo").ToSQL()
+			So(err, ShouldEqual, ErrMissingTenant)
+		})
+
+		Convey("The statements without tenant are not filtered", func() {
+			sql, _, err := db.SelectFrom("tenantdummies").Columns("id").WithoutTenant().ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id FROM tenantdummies")
+			sql, _, err = tenantDB.DeleteFrom("tenantdummies").WithoutTenant().ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "DELETE FROM tenantdummies")
+		})
+
+		Convey("RegisterTenantModels needs a tenant field", func() {
+			So(db.RegisterTenantModels(&Dummy{}), ShouldNotBeNil)
+		})
+
+		Convey("Struct operations need a matching tenant column", func() {
+			otherDB := db.WithTenant("organization_id", 1)
+			err := otherDB.Select(&TenantDummy{}).Do()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Tables are matched whatever the case, quotes and schema", func() {
+			for _, table := range []string{`"TenantDummies"`, "billing.tenantdummies", "[billing].[tenantdummies]"} {
+				_, args, err := tenantDB.SelectFrom(table).Columns("id").ToSQL()
+				So(err, ShouldBeNil)
+				So(args, ShouldResemble, []interface{}{1})
+			}
+		})
+
+		Convey("All tables of a FROM list get the tenant predicate", func() {
+			sql, _, err := tenantDB.SelectFrom("others o, tenantdummies AS t").Columns("o.id").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT o.id FROM others o, tenantdummies AS t WHERE t."tenant_id" = ?`)
+		})
+
+		Convey("Subqueries in FROM return an error unless run without tenant", func() {
+			_, _, err := tenantDB.SelectFrom("(SELECT * FROM tenantdummies) t").Columns("t.id").ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = tenantDB.SelectFrom("(SELECT * FROM tenantdummies) t").Columns("t.id").WithoutTenant().ToSQL()
+			So(err, ShouldBeNil)
+		})
+
+		Convey("The other tables of UPDATE and DELETE statements get the tenant predicate", func() {
+			sql, args, err := tenantDB.UpdateTable("others").
+				SetRaw("a_text = t.a_text").
+				Join("tenantdummies", "t", Q("t.id = others.dummy_id")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `UPDATE others SET a_text = t.a_text WHERE EXISTS (SELECT 1 FROM tenantdummies AS t WHERE (t.id = others.dummy_id) AND (t."tenant_id" = ?))`)
+			So(args, ShouldResemble, []interface{}{1})
+
+			sql, _, err = tenantDB.DeleteFrom("others").
+				Using("tenantdummies t").
+				Where("t.id = others.dummy_id").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `DELETE FROM others WHERE EXISTS (SELECT 1 FROM tenantdummies t WHERE (t."tenant_id" = ? AND (t.id = others.dummy_id)))`)
+		})
+
+		Convey("The tenant column can't be updated", func() {
+			_, _, err := tenantDB.UpdateTable("tenantdummies").Set(`"tenant_id"`, 2).ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = tenantDB.UpdateTable("tenantdummies").SetRaw("tenant_id = 2").ToSQL()
+			So(err, ShouldNotBeNil)
+			_, _, err = tenantDB.UpdateTable("tenantdummies").SetRaw("tenant_id = 2").WithoutTenant().ToSQL()
+			So(err, ShouldBeNil)
+
+			_, _, err = tenantDB.InsertInto("tenantdummies").
+				Columns("id", "a_text").
+				Values(1, "foo").
+				OnConflictUpdate([]string{"id"}, "a_text", "tenant_id").
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestTenantRegistration(t *testing.T) {
+	Convey("Given a DB with a tenant but no registered tenant table", t, func() {
+		db := Wrap(sqlite.Adapter, nil)
+		tenantDB := db.WithTenant("tenant_id", 1)
+
+		Convey("Statements return an error unless run without tenant", func() {
+			_, _, err := tenantDB.SelectFrom("tenantdummies").Columns("id").ToSQL()
+			So(err, ShouldEqual, ErrTenantModelsNotRegistered)
+			_, _, err = tenantDB.UpdateTable("tenantdummies").Set("a_text", "foo").ToSQL()
+			So(err, ShouldEqual, ErrTenantModelsNotRegistered)
+			_, _, err = tenantDB.DeleteFrom("tenantdummies").ToSQL()
+			So(err, ShouldEqual, ErrTenantModelsNotRegistered)
+			_, _, err = db.InsertInto("tenantdummies").Columns("a_text").Values("foo").ToSQL()
+			So(err, ShouldEqual, ErrTenantModelsNotRegistered)
+
+			_, _, err = tenantDB.SelectFrom("tenantdummies").Columns("id").WithoutTenant().ToSQL()
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Statements get the tenant predicate once the models are registered", func() {
+			So(db.RegisterTenantModels(&TenantDummy{}), ShouldBeNil)
+			_, args, err := tenantDB.SelectFrom("tenantdummies").Columns("id").ToSQL()
+			So(err, ShouldBeNil)
+			So(args, ShouldResemble, []interface{}{1})
+		})
+
+		Convey("Tables registered with a schema match the unqualified names", func() {
+			db.SetSchema("billing")
+			So(db.RegisterTenantModels(&TenantDummy{}), ShouldBeNil)
+			_, args, err := tenantDB.SelectFrom("tenantdummies").Columns("id").ToSQL()
+			So(err, ShouldBeNil)
+			So(args, ShouldResemble, []interface{}{1})
+		})
+	})
+}
+
+func TestTenantStructOperations(t *testing.T) {
+	Convey("Given a test database with tenant rows", t, func() {
+		db := tenantFixturesSetup(t)
+		defer db.Close()
+		So(db.RegisterTenantModels(&TenantDummy{}), ShouldBeNil)
+		tenantDB := db.WithTenant("tenant_id", 1)
+
+		Convey("Select returns only the rows of the tenant", func() {
+			dummies := make([]TenantDummy, 0)
+			err := tenantDB.Select(&dummies).Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+
+			count, err := tenantDB.Select(&dummies).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Unscoped keeps the tenant filtering", func() {
+			dummies := make([]TenantDummy, 0)
+			err := tenantDB.Select(&dummies).Unscoped().Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 2)
+			for _, dummy := range dummies {
+				So(dummy.TenantID, ShouldEqual, 1)
+			}
+
+			err = db.Select(&dummies).Unscoped().Do()
+			So(err, ShouldEqual, ErrMissingTenant)
+		})
+
+		Convey("Select returns an error without tenant unless run without tenant", func() {
+			dummies := make([]TenantDummy, 0)
+			err := db.Select(&dummies).Do()
+			So(err, ShouldEqual, ErrMissingTenant)
+
+			err = db.Select(&dummies).WithoutTenant().Do()
+			So(err, ShouldBeNil)
+			So(len(dummies), ShouldEqual, 3)
+		})
+
+		Convey("Insert fills the tenant field", func() {
+			dummy := TenantDummy{TenantID: 2, AText: "Fourth"}
+			err := tenantDB.Insert(&dummy).Do()
+			So(err, ShouldBeNil)
+			So(dummy.TenantID, ShouldEqual, 1)
+
+			count, err := tenantDB.SelectFrom("tenantdummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Update and Delete can't change rows of another tenant", func() {
+			dummy := TenantDummy{}
+			err := db.Select(&dummy).WithoutTenant().Where("tenant_id = ?", 2).Do()
+			So(err, ShouldBeNil)
+
+			dummy.AText = "Changed"
+			err = tenantDB.Update(&dummy).Do()
+			So(err, ShouldBeNil)
+			deleted, err := tenantDB.Delete(&dummy).Do()
+			So(err, ShouldBeNil)
+			So(deleted, ShouldEqual, 0)
+
+			other := TenantDummy{}
+			err = db.Select(&other).WithoutTenant().Where("id = ?", dummy.ID).Do()
+			So(err, ShouldBeNil)
+			So(other.AText, ShouldEqual, "Third")
+			So(other.TenantID, ShouldEqual, 2)
+		})
+	})
+}
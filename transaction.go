@@ -2,8 +2,13 @@ package godb
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/samonzeweb/godb/adapters"
+	"github.com/samonzeweb/godb/dberror"
 )
 
 // preparableAndQueryable represents either a Tx or DB.
@@ -28,7 +33,7 @@ func (db *DB) Begin() error {
 	db.logExecution(consumedTime, "BEGIN")
 	if err != nil {
 		db.logExecutionErr(err, "BEGIN")
-		return err
+		return db.queryError(queryInfo{op: "BEGIN"}, "BEGIN", err)
 	}
 
 	db.sqlTx = tx
@@ -52,7 +57,7 @@ func (db *DB) Commit() error {
 	if err!=nil {
 		db.logExecutionErr(err, "COMMIT")
 	}
-	return err
+	return db.queryError(queryInfo{op: "COMMIT"}, "COMMIT", err)
 }
 
 // Rollback rollbacks an existing transaction, fails if none exists.
@@ -71,7 +76,7 @@ func (db *DB) Rollback() error {
 		db.logExecutionErr(err, "ROLLBACK")
 	}
 	db.sqlTx = nil
-	return err
+	return db.queryError(queryInfo{op: "ROLLBACK"}, "ROLLBACK", err)
 }
 
 // CurrentTx returns the current Tx (or nil). Don't commit or rollback it
@@ -79,3 +84,283 @@ func (db *DB) Rollback() error {
 func (db *DB) CurrentTx() *sql.Tx {
 	return db.sqlTx
 }
+
+// Tx is a transaction started with BeginTx. It wraps a clone of the DB using
+// the transaction, all statements built with it run in the transaction. It
+// only gives the builders, the savepoints and the end of the transaction,
+// the configuration of the DB can't be changed. A Tx has to be used by a
+// single goroutine.
+type Tx struct {
+	db     *DB
+	parent *DB
+}
+
+// BeginTx starts a new transaction and returns it, the DB itself is left
+// untouched. Unlike Begin, it can be called on a DB shared by multiple
+// goroutines. The time consumed by the transaction is added to the DB when
+// it ends.
+//
+// Example :
+//
+// 	tx, err := db.BeginTx()
+// 	if err != nil {
+// 		return err
+// 	}
+// 	defer tx.Rollback()
+// 	if err := tx.Insert(&book).Do(); err != nil {
+// 		return err
+// 	}
+// 	return tx.Commit()
+func (db *DB) BeginTx() (*Tx, error) {
+	txDB := db.Clone()
+	if err := txDB.Begin(); err != nil {
+		db.addConsumedTime(txDB.ConsumedTime())
+		return nil, err
+	}
+	return &Tx{db: txDB, parent: db}, nil
+}
+
+// Commit commits the transaction, fails if it has already ended.
+func (tx *Tx) Commit() error {
+	if tx.CurrentTx() == nil {
+		return tx.db.Commit()
+	}
+	defer tx.end()
+	return tx.db.Commit()
+}
+
+// Rollback rollbacks the transaction, fails if it has already ended. It can
+// be deferred just after BeginTx, the error is meaningless after Commit.
+func (tx *Tx) Rollback() error {
+	if tx.CurrentTx() == nil {
+		return tx.db.Rollback()
+	}
+	defer tx.end()
+	return tx.db.Rollback()
+}
+
+// end adds the consumed time to the DB which started the transaction, and
+// frees the resources of the transaction.
+func (tx *Tx) end() {
+	tx.parent.addConsumedTime(tx.ConsumedTime())
+	tx.db.Clear()
+}
+
+// CurrentTx returns the sql.Tx of the transaction, or nil once it has ended.
+// Don't commit or rollback it directly !
+func (tx *Tx) CurrentTx() *sql.Tx {
+	return tx.db.CurrentTx()
+}
+
+// ConsumedTime returns the time consumed by the transaction.
+func (tx *Tx) ConsumedTime() time.Duration {
+	return tx.db.ConsumedTime()
+}
+
+// Capabilities returns the description of what the database supports.
+func (tx *Tx) Capabilities() adapters.Capabilities {
+	return tx.db.Capabilities()
+}
+
+// ReturningBuilder is DB.ReturningBuilder for the transaction.
+func (tx *Tx) ReturningBuilder(update bool) adapters.ReturningBuilder {
+	return tx.db.ReturningBuilder(update)
+}
+
+// SelectFrom is DB.SelectFrom running in the transaction.
+func (tx *Tx) SelectFrom(tableNames ...string) *SelectStatement {
+	return tx.db.SelectFrom(tableNames...)
+}
+
+// InsertInto is DB.InsertInto running in the transaction.
+func (tx *Tx) InsertInto(tableName string) *InsertStatement {
+	return tx.db.InsertInto(tableName)
+}
+
+// UpsertInto is DB.UpsertInto running in the transaction.
+func (tx *Tx) UpsertInto(tableName string) *InsertStatement {
+	return tx.db.UpsertInto(tableName)
+}
+
+// UpdateTable is DB.UpdateTable running in the transaction.
+func (tx *Tx) UpdateTable(tableName string) *UpdateStatement {
+	return tx.db.UpdateTable(tableName)
+}
+
+// DeleteFrom is DB.DeleteFrom running in the transaction.
+func (tx *Tx) DeleteFrom(tableName string) *DeleteStatement {
+	return tx.db.DeleteFrom(tableName)
+}
+
+// Select is DB.Select running in the transaction.
+func (tx *Tx) Select(record interface{}) *StructSelect {
+	return tx.db.Select(record)
+}
+
+// Insert is DB.Insert running in the transaction.
+func (tx *Tx) Insert(record interface{}) *StructInsert {
+	return tx.db.Insert(record)
+}
+
+// BulkInsert is DB.BulkInsert running in the transaction.
+func (tx *Tx) BulkInsert(record interface{}) *StructInsert {
+	return tx.db.BulkInsert(record)
+}
+
+// Update is DB.Update running in the transaction.
+func (tx *Tx) Update(record interface{}) *StructUpdate {
+	return tx.db.Update(record)
+}
+
+// Delete is DB.Delete running in the transaction.
+func (tx *Tx) Delete(record interface{}) *StructDelete {
+	return tx.db.Delete(record)
+}
+
+// RawSQL is DB.RawSQL running in the transaction.
+func (tx *Tx) RawSQL(sql string, args ...interface{}) *RawSQL {
+	return tx.db.RawSQL(sql, args...)
+}
+
+// RawSQLNamed is DB.RawSQLNamed running in the transaction.
+func (tx *Tx) RawSQLNamed(sql string, params interface{}) *RawSQL {
+	return tx.db.RawSQLNamed(sql, params)
+}
+
+// Savepoint creates a savepoint in the transaction (see DB.Savepoint).
+func (tx *Tx) Savepoint(name string) error {
+	return tx.db.Savepoint(name)
+}
+
+// RollbackToSavepoint rollbacks the transaction to the given savepoint (see
+// DB.RollbackToSavepoint).
+func (tx *Tx) RollbackToSavepoint(name string) error {
+	return tx.db.RollbackToSavepoint(name)
+}
+
+// ReleaseSavepoint releases the given savepoint (see DB.ReleaseSavepoint).
+func (tx *Tx) ReleaseSavepoint(name string) error {
+	return tx.db.ReleaseSavepoint(name)
+}
+
+// Savepoint creates a savepoint in the current transaction, fails if there is
+// no transaction or if the database does not support savepoints (see
+// adapters.Capabilities).
+func (db *DB) Savepoint(name string) error {
+	switch db.Capabilities().Savepoints {
+	case adapters.SavepointsStandard:
+		return db.execSavepoint("SAVEPOINT", name)
+	case adapters.SavepointsSQLServer:
+		return db.execSavepoint("SAVE TRANSACTION", name)
+	default:
+		return &UnsupportedError{Feature: "SAVEPOINT"}
+	}
+}
+
+// RollbackToSavepoint rollbacks the current transaction to the given
+// savepoint, the transaction continues.
+func (db *DB) RollbackToSavepoint(name string) error {
+	switch db.Capabilities().Savepoints {
+	case adapters.SavepointsStandard:
+		return db.execSavepoint("ROLLBACK TO SAVEPOINT", name)
+	case adapters.SavepointsSQLServer:
+		return db.execSavepoint("ROLLBACK TRANSACTION", name)
+	default:
+		return &UnsupportedError{Feature: "SAVEPOINT"}
+	}
+}
+
+// ReleaseSavepoint removes the given savepoint, the changes made since are
+// kept. It does nothing with SQL Server, which can't release savepoints.
+func (db *DB) ReleaseSavepoint(name string) error {
+	switch db.Capabilities().Savepoints {
+	case adapters.SavepointsStandard:
+		return db.execSavepoint("RELEASE SAVEPOINT", name)
+	case adapters.SavepointsSQLServer:
+		return db.checkSavepoint(name)
+	default:
+		return &UnsupportedError{Feature: "SAVEPOINT"}
+	}
+}
+
+// checkSavepoint returns an error if there is no transaction or if the
+// savepoint name is invalid.
+func (db *DB) checkSavepoint(name string) error {
+	if db.sqlTx == nil {
+		return fmt.Errorf("savepoints can't be used without existing sql transaction")
+	}
+	if strings.Contains(name, ".") {
+		return fmt.Errorf("invalid savepoint name : %q", name)
+	}
+	return checkIdentifier(name)
+}
+
+// execSavepoint executes a savepoint command with the given savepoint name.
+func (db *DB) execSavepoint(command string, name string) error {
+	if err := db.checkSavepoint(name); err != nil {
+		return err
+	}
+
+	query := command + " " + db.adapter.Quote(name)
+	startTime := time.Now()
+	_, err := db.sqlTx.Exec(query)
+	consumedTime := timeElapsedSince(startTime)
+	db.addConsumedTime(consumedTime)
+	db.logExecution(consumedTime, query)
+	if err != nil {
+		db.logExecutionErr(err, query)
+	}
+	return db.queryError(queryInfo{op: command}, query, err)
+}
+
+// defaultMaxTransactionRetries is the default count of retries of the
+// transactions run by RunInTransaction.
+const defaultMaxTransactionRetries = 3
+
+// SetMaxTransactionRetries sets how many times RunInTransaction retries a
+// transaction failing with a serialization failure (3 by default).
+func (db *DB) SetMaxTransactionRetries(retries int) {
+	db.maxTransactionRetries = retries
+}
+
+// RunInTransaction calls f in a new transaction, committed if f succeeds,
+// rolled back otherwise. If f or the commit fail with an error classified as
+// dberror.SerializationFailure by the adapter (ie a 40001 error with
+// CockroachDB), the whole transaction is retried (see SetMaxTransactionRetries).
+// As f could be called many times, it should only have effects through the
+// given DB.
+// If there is already a transaction, f is called with it, without retries.
+//
+// Example :
+//
+// 	err := db.RunInTransaction(func(tx *godb.DB) error {
+// 		if err := tx.Select(&account).Where("id = ?", id).Do(); err != nil {
+// 			return err
+// 		}
+// 		account.Balance -= amount
+// 		return tx.Update(&account).Do()
+// 	})
+func (db *DB) RunInTransaction(f func(db *DB) error) error {
+	if db.CurrentTx() != nil {
+		return f(db)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := db.withTransaction(f)
+		if err == nil || attempt >= db.maxTransactionRetries || !db.isSerializationFailure(err) {
+			return err
+		}
+		db.logPrintln("Retry the transaction after a serialization failure")
+	}
+}
+
+// isSerializationFailure returns true if the given error, parsed by the
+// adapter, is a dberror.SerializationFailure.
+func (db *DB) isSerializationFailure(err error) bool {
+	var serializationFailure dberror.SerializationFailure
+	if errors.As(err, &serializationFailure) {
+		return true
+	}
+	// The error of the driver, not parsed if UseErrorParser wasn't called
+	return errors.As(db.adapter.ParseError(err), &serializationFailure)
+}
@@ -1,8 +1,12 @@
 package godb
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	"github.com/samonzeweb/godb/dberror"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -60,6 +64,78 @@ func TestRollback(t *testing.T) {
 	})
 }
 
+func TestBeginTx(t *testing.T) {
+	Convey("Given an existing DB", t, func() {
+		db := createFileConnection(t)
+		defer db.Close()
+
+		Convey("BeginTx returns a transaction, the DB is left untouched", func() {
+			tx, err := db.BeginTx()
+			So(err, ShouldBeNil)
+			So(tx.CurrentTx(), ShouldNotBeNil)
+			So(db.CurrentTx(), ShouldBeNil)
+			So(tx.Rollback(), ShouldBeNil)
+		})
+
+		Convey("The statements built with the transaction run in it", func() {
+			tx, err := db.BeginTx()
+			So(err, ShouldBeNil)
+			dummy := Dummy{AText: "Foo", AnotherText: "Bar", AnInteger: 1}
+			So(tx.Insert(&dummy).Do(), ShouldBeNil)
+
+			count, err := tx.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			So(tx.Commit(), ShouldBeNil)
+			count, err = db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Rollback cancels the changes, and fails after Commit", func() {
+			tx, err := db.BeginTx()
+			So(err, ShouldBeNil)
+			dummy := Dummy{AText: "Foo", AnotherText: "Bar", AnInteger: 1}
+			So(tx.Insert(&dummy).Do(), ShouldBeNil)
+			So(tx.Rollback(), ShouldBeNil)
+
+			count, err := db.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+
+			tx, err = db.BeginTx()
+			So(err, ShouldBeNil)
+			So(tx.Commit(), ShouldBeNil)
+			So(tx.Rollback(), ShouldNotBeNil)
+		})
+
+		Convey("The transaction doesn't give the methods changing the DB", func() {
+			tx, err := db.BeginTx()
+			So(err, ShouldBeNil)
+			defer tx.Rollback()
+			txType := reflect.TypeOf(tx)
+			for _, name := range []string{"Close", "Begin", "BeginTx", "Clone", "SetSchema", "UseErrorParser"} {
+				_, ok := txType.MethodByName(name)
+				So(ok, ShouldBeFalse)
+			}
+		})
+
+		Convey("The time consumed by the transaction is added to the DB when it ends", func() {
+			db.ResetConsumedTime()
+			tx, err := db.BeginTx()
+			So(err, ShouldBeNil)
+			_, err = tx.SelectFrom("dummies").Count()
+			So(err, ShouldBeNil)
+			consumedTime := db.ConsumedTime()
+
+			So(tx.Commit(), ShouldBeNil)
+			So(db.ConsumedTime(), ShouldBeGreaterThan, consumedTime)
+			So(db.ConsumedTime(), ShouldBeGreaterThanOrEqualTo, tx.ConsumedTime())
+		})
+	})
+}
+
 func TestCurrentTx(t *testing.T) {
 	Convey("Given an existing connexion", t, func() {
 		db := createInMemoryConnection(t)
@@ -75,3 +151,97 @@ func TestCurrentTx(t *testing.T) {
 		})
 	})
 }
+
+// errTestConflict is parsed as a serialization failure by retryingAdapter.
+var errTestConflict = errors.New("conflict")
+
+// retryingAdapter is the SQLite adapter, parsing errTestConflict as a
+// serialization failure.
+type retryingAdapter struct {
+	sqlite.SQLite
+}
+
+func (a retryingAdapter) ParseError(err error) error {
+	if err == errTestConflict {
+		return dberror.SerializationFailure{Message: err.Error(), Err: err}
+	}
+	return a.SQLite.ParseError(err)
+}
+
+func TestRunInTransaction(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+		db.adapter = retryingAdapter{}
+
+		insertDummy := func(tx *DB) error {
+			_, err := tx.InsertInto("dummies").
+				Columns("a_text", "another_text", "an_integer").
+				Values("Retried", "Retried", 42).
+				Do()
+			return err
+		}
+		countDummies := func() int64 {
+			count, err := db.SelectFrom("dummies").Where("a_text = ?", "Retried").Count()
+			So(err, ShouldBeNil)
+			return count
+		}
+
+		Convey("The transaction is retried after serialization failures", func() {
+			attempts := 0
+			err := db.RunInTransaction(func(tx *DB) error {
+				attempts++
+				So(tx.CurrentTx(), ShouldNotBeNil)
+				if err := insertDummy(tx); err != nil {
+					return err
+				}
+				if attempts < 3 {
+					return errTestConflict
+				}
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(attempts, ShouldEqual, 3)
+			So(countDummies(), ShouldEqual, 1)
+			So(db.CurrentTx(), ShouldBeNil)
+		})
+
+		Convey("The retries are limited", func() {
+			db.SetMaxTransactionRetries(1)
+			attempts := 0
+			err := db.RunInTransaction(func(tx *DB) error {
+				attempts++
+				return errTestConflict
+			})
+			So(err, ShouldEqual, errTestConflict)
+			So(attempts, ShouldEqual, 2)
+		})
+
+		Convey("Other errors are not retried", func() {
+			attempts := 0
+			err := db.RunInTransaction(func(tx *DB) error {
+				attempts++
+				if err := insertDummy(tx); err != nil {
+					return err
+				}
+				return errors.New("failure")
+			})
+			So(err, ShouldNotBeNil)
+			So(attempts, ShouldEqual, 1)
+			So(countDummies(), ShouldEqual, 0)
+		})
+
+		Convey("The current transaction is used without retries", func() {
+			So(db.Begin(), ShouldBeNil)
+			attempts := 0
+			err := db.RunInTransaction(func(tx *DB) error {
+				attempts++
+				So(tx, ShouldEqual, db)
+				return errTestConflict
+			})
+			So(err, ShouldEqual, errTestConflict)
+			So(attempts, ShouldEqual, 1)
+			So(db.Rollback(), ShouldBeNil)
+		})
+	})
+}
@@ -1,6 +1,10 @@
 package godb
 
-import "github.com/samonzeweb/godb/adapters"
+import (
+	"slices"
+
+	"github.com/samonzeweb/godb/adapters"
+)
 
 // UpdateStatement will contains all parts needed to build an UPDATE statement.
 // Initialize it with the UpdateTable method.
@@ -10,14 +14,27 @@ import "github.com/samonzeweb/godb/adapters"
 // 		Set("foo", 1).
 // 		Where("foo = ?", 2).
 // 		Do()
+//
+// Other tables are used with From and Join, the statement is written
+// according to the database (see adapters.MultiTableStyle) :
+// 	count, err := db.UpdateTable("books").
+// 		SetRaw("stock = inventories.counting").
+// 		Join("inventories", "", godb.Q("inventories.book_id = books.id")).
+// 		Do()
 type UpdateStatement struct {
-	db *DB
+	db    *DB
+	error error
 
 	updateTable      string
 	sets             []*setPart
+	fromTables       []string
+	joins            []*joinPart
 	where            []*Condition
+	orderBy          []string
+	limit            *int
 	returningColumns []string
 	suffixes         []string
+	withoutTenant    bool
 }
 
 // setPart contains elements for a single SET clause.
@@ -37,6 +54,26 @@ func (db *DB) UpdateTable(tableName string) *UpdateStatement {
 	return us
 }
 
+// Clone returns a deep copy of the statement.
+func (us *UpdateStatement) Clone() *UpdateStatement {
+	clone := *us
+	clone.sets = make([]*setPart, 0, len(us.sets))
+	for _, set := range us.sets {
+		setClone := *set
+		clone.sets = append(clone.sets, &setClone)
+	}
+	clone.fromTables = slices.Clone(us.fromTables)
+	clone.joins = cloneJoins(us.joins)
+	clone.where = cloneConditions(us.where)
+	clone.orderBy = slices.Clone(us.orderBy)
+	if us.limit != nil {
+		clone.Limit(*us.limit)
+	}
+	clone.returningColumns = slices.Clone(us.returningColumns)
+	clone.suffixes = slices.Clone(us.suffixes)
+	return &clone
+}
+
 // Set adds a part of SET clause to the query.
 func (us *UpdateStatement) Set(column string, value interface{}) *UpdateStatement {
 	setClause := &setPart{
@@ -57,15 +94,49 @@ func (us *UpdateStatement) SetRaw(rawSQL string) *UpdateStatement {
 	return us
 }
 
+// From adds other tables to the statement, the conditions using them are
+// given with Where. With SQLite the SET clause can't use the other tables.
+func (us *UpdateStatement) From(tableNames ...string) *UpdateStatement {
+	us.fromTables = append(us.fromTables, tableNames...)
+	return us
+}
+
+// Join adds another table to the statement with an INNER JOIN condition (see
+// From).
+func (us *UpdateStatement) Join(tableName string, as string, on *Condition) *UpdateStatement {
+	us.joins = append(us.joins, &joinPart{
+		joinType:  "INNER JOIN",
+		tableName: tableName,
+		as:        as,
+		on:        on.withSyntax(us.db.syntax()),
+	})
+	return us
+}
+
 // Where adds a condition using string and arguments.
 func (us *UpdateStatement) Where(sql string, args ...interface{}) *UpdateStatement {
-	return us.WhereQ(Q(sql, args...))
+	return us.WhereQ(us.db.q(sql, args...))
 }
 
 // WhereQ adds a simple or complex predicate generated with Q and
 // confunctions.
 func (us *UpdateStatement) WhereQ(condition *Condition) *UpdateStatement {
-	us.where = append(us.where, condition)
+	us.where = append(us.where, condition.withSyntax(us.db.syntax()))
+	return us
+}
+
+// OrderBy adds an expression for the ORDER BY clause. Use it with MySQL and
+// SQLite.
+func (us *UpdateStatement) OrderBy(orderBy string) *UpdateStatement {
+	us.orderBy = append(us.orderBy, orderBy)
+	return us
+}
+
+// Limit specifies the value for the LIMIT clause. Use it with MySQL and
+// SQLite.
+func (us *UpdateStatement) Limit(limit int) *UpdateStatement {
+	us.limit = new(int)
+	*us.limit = limit
 	return us
 }
 
@@ -108,7 +179,20 @@ func (us *UpdateStatement) approximateSetLength() int {
 // ToSQL returns a string with the SQL statement (containing placeholders),
 // the arguments slices, and an error.
 func (us *UpdateStatement) ToSQL() (string, []interface{}, error) {
-	sqlWhereLength, argsWhereLength, err := sumOfConditionsLengths(us.where)
+	if us.error != nil {
+		return "", nil, us.error
+	}
+
+	multiTable := len(us.fromTables) > 0 || len(us.joins) > 0
+	if err := us.db.checkUpdateDeleteLimit(us.orderBy, us.limit, multiTable); err != nil {
+		return "", nil, err
+	}
+	if err := us.checkTenantSets(); err != nil {
+		return "", nil, err
+	}
+
+	where := us.tenantWhere()
+	sqlWhereLength, argsWhereLength, err := sumOfConditionsLengths(where)
 	if err != nil {
 		return "", nil, err
 	}
@@ -119,17 +203,43 @@ func (us *UpdateStatement) ToSQL() (string, []interface{}, error) {
 		argsWhereLength,
 	)
 
+	style := us.db.multiTableStyle()
 	sqlBuffer.Write("UPDATE ")
-	sqlBuffer.Write(us.updateTable)
+	if multiTable && style == adapters.MultiTableSubquery {
+		sqlBuffer.Write(subqueryTarget(us.updateTable))
+	} else {
+		sqlBuffer.Write(us.updateTable)
+	}
+	if multiTable && style == adapters.MultiTableMySQL {
+		sqlBuffer.writeMultiTableJoins(us.fromTables, us.joins)
+	}
 	sqlBuffer.writeSets(us.sets).
-		writeReturningForPosition(us.returningColumns, adapters.ReturningSQLServer).
-		writeWhere(us.where).
-		writeReturningForPosition(us.returningColumns, adapters.ReturningPostgreSQL).
+		writeReturningForPosition(us.returningColumns, adapters.ReturningSQLServer, true)
+	if multiTable {
+		switch style {
+		case adapters.MultiTablePostgreSQL, adapters.MultiTableSQLServer:
+			sqlBuffer.writeFrom(otherTables(us.fromTables, us.joins)...)
+			where = multiTableWhere(us.joins, where)
+		case adapters.MultiTableMySQL:
+			// Already written as joins
+		default:
+			where = []*Condition{existsCondition(us.fromTables, us.joins, where)}
+		}
+	}
+	sqlBuffer.writeWhere(where).
+		writeReturningForPosition(us.returningColumns, adapters.ReturningPostgreSQL, true).
+		writeOrderBy(us.orderBy).
+		writeLimit(us.limit).
 		writeStringsWithSpaces(us.suffixes)
 
 	return sqlBuffer.SQL(), sqlBuffer.Arguments(), sqlBuffer.Err()
 }
 
+// queryInfo describes the statement for the errors.
+func (us *UpdateStatement) queryInfo() queryInfo {
+	return queryInfo{op: "UPDATE", table: us.updateTable}
+}
+
 // Do executes the builded query, and return RowsAffected()
 func (us *UpdateStatement) Do() (int64, error) {
 	query, args, err := us.ToSQL()
@@ -137,7 +247,7 @@ func (us *UpdateStatement) Do() (int64, error) {
 		return 0, err
 	}
 
-	result, err := us.db.do(query, args)
+	result, err := us.db.do(us.queryInfo(), query, args)
 	if err != nil {
 		return 0, err
 	}
@@ -171,5 +281,5 @@ func (us *UpdateStatement) doWithReturning(recordDescription *recordDescription,
 		return 0, err
 	}
 
-	return us.db.doSelectOrWithReturning(query, args, recordDescription, pointersGetter)
+	return us.db.doSelectOrWithReturning(us.queryInfo(), query, args, recordDescription, pointersGetter)
 }
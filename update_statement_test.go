@@ -3,6 +3,10 @@ package godb
 import (
 	"testing"
 
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -74,7 +78,8 @@ func TestUpdateWhereQ(t *testing.T) {
 			qc := Q("id = ?", 123)
 			q.WhereQ(qc)
 			So(len(q.where), ShouldEqual, 1)
-			So(q.where[0], ShouldEqual, qc)
+			So(q.where[0].sql, ShouldEqual, qc.sql)
+			So(q.where[0].args, ShouldResemble, qc.args)
 		})
 	})
 }
@@ -163,3 +168,141 @@ func TestDoUpdate(t *testing.T) {
 		})
 	})
 }
+
+func TestUpdateClone(t *testing.T) {
+	Convey("Given an update statement", t, func() {
+		db := &DB{}
+		q := db.UpdateTable("dummies").
+			Set("foo", 1).
+			Where("id = ?", 2)
+
+		Convey("Changes to the clone does not alter the original", func() {
+			q.Clone().Set("bar", 3).Where("baz = ?", 4)
+			sql, args, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET foo=? WHERE id = ?")
+			So(args, ShouldResemble, []interface{}{1, 2})
+		})
+	})
+}
+
+func TestUpdateMultiTable(t *testing.T) {
+	Convey("Given an update statement with other tables", t, func() {
+		build := func(db *DB) *UpdateStatement {
+			return db.UpdateTable("dummies").
+				SetRaw("a_text = r.a_text").
+				Set("an_integer", 1).
+				From("others").
+				Join("relatedtodummies", "r", Q("r.dummies_id = dummies.id")).
+				Where("others.id = ? OR others.id = ?", 2, 3)
+		}
+
+		Convey("PostgreSQL uses UPDATE ... FROM", func() {
+			sql, args, err := build(&DB{adapter: postgresql.Adapter}).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET a_text = r.a_text, an_integer=? FROM others, relatedtodummies AS r WHERE (r.dummies_id = dummies.id) AND (others.id = ? OR others.id = ?)")
+			So(args, ShouldResemble, []interface{}{1, 2, 3})
+		})
+
+		Convey("SQL Server uses UPDATE ... FROM after the OUTPUT clause", func() {
+			sql, _, err := build(&DB{adapter: mssql.Adapter}).Returning("INSERTED.id").ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET a_text = r.a_text, an_integer=? OUTPUT INSERTED.id  FROM others, relatedtodummies AS r WHERE (r.dummies_id = dummies.id) AND (others.id = ? OR others.id = ?)")
+		})
+
+		Convey("MySQL uses joins", func() {
+			sql, args, err := build(&DB{adapter: mysql.Adapter}).ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies CROSS JOIN others INNER JOIN relatedtodummies AS r ON r.dummies_id = dummies.id SET a_text = r.a_text, an_integer=? WHERE others.id = ? OR others.id = ?")
+			So(args, ShouldResemble, []interface{}{1, 2, 3})
+		})
+
+		Convey("MySQL can't order or limit with other tables", func() {
+			_, _, err := build(&DB{adapter: mysql.Adapter}).Limit(10).ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("SQLite uses a correlated subquery", func() {
+			sql, args, err := (&DB{adapter: sqlite.Adapter}).UpdateTable("dummies").
+				Set("an_integer", 1).
+				Join("relatedtodummies", "r", Q("r.dummies_id = dummies.id")).
+				Where("r.a_text = ?", "REL_First").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET an_integer=? WHERE EXISTS (SELECT 1 FROM relatedtodummies AS r WHERE (r.dummies_id = dummies.id) AND (r.a_text = ?))")
+			So(args, ShouldResemble, []interface{}{1, "REL_First"})
+		})
+
+		Convey("Clone copies the other tables", func() {
+			db := &DB{adapter: postgresql.Adapter}
+			q := db.UpdateTable("dummies").Set("an_integer", 1).From("others")
+			q.Clone().From("more").Join("other", "", Q("a = b"))
+			sql, _, err := q.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET an_integer=? FROM others")
+		})
+	})
+
+	Convey("Given an update statement with ORDER BY and LIMIT", t, func() {
+		Convey("MySQL writes the clauses", func() {
+			sql, args, err := (&DB{adapter: mysql.Adapter}).UpdateTable("dummies").
+				Set("an_integer", 1).
+				Where("a_text = ?", "foo").
+				OrderBy("id DESC").
+				Limit(10).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "UPDATE dummies SET an_integer=? WHERE a_text = ? ORDER BY id DESC LIMIT ?")
+			So(args, ShouldResemble, []interface{}{1, "foo", 10})
+		})
+
+		Convey("Other databases return an error", func() {
+			_, _, err := (&DB{adapter: postgresql.Adapter}).UpdateTable("dummies").
+				Set("an_integer", 1).
+				Limit(10).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Do updates the rows matching the other tables", func() {
+			count, err := db.UpdateTable("dummies").
+				Set("an_integer", 100).
+				Join("relatedtodummies", "r", Q("r.dummies_id = dummies.id")).
+				Where("r.a_text IN (?)", []string{"REL_First", "REL_Third"}).
+				Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+
+			count, err = db.SelectFrom("dummies").Where("an_integer = ?", 100).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+
+		Convey("Do updates the rows of an aliased table matching the other tables", func() {
+			count, err := db.UpdateTable("dummies d").
+				Set("an_integer", 100).
+				Join("relatedtodummies", "r", Q("r.dummies_id = d.id")).
+				Where("r.a_text = ?", "REL_First").
+				Do()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			count, err = db.SelectFrom("dummies").Where("an_integer = ?", 100).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Do returns an error with ORDER BY and LIMIT", func() {
+			_, err := db.UpdateTable("dummies").Set("an_integer", 100).OrderBy("id").Limit(1).Do()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+			count, err := db.SelectFrom("dummies").Where("an_integer = ?", 100).Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package godb
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// UpsertInto initializes an UPSERT statement builder, inserting the rows or
+// replacing the existing ones having the same primary key. It's supported by
+// CockroachDB (see adapters.Capabilities), use OnConflictUpdate with the
+// other databases.
+//
+// Example :
+//
+//	_, err := db.UpsertInto("stocks").
+//		Columns("product_id", "quantity").
+//		Values(42, 10).
+//		Do()
+func (db *DB) UpsertInto(tableName string) *InsertStatement {
+	is := db.InsertInto(tableName)
+	if !db.Capabilities().UpsertStatement {
+		is.error = &UnsupportedError{Feature: "UPSERT"}
+		return is
+	}
+	is.verb = "UPSERT "
+	return is
+}
+
+// upsertPart describes the conflict handling of an INSERT statement.
+type upsertPart struct {
+	conflictColumns []string
+	updateColumns   []string
+}
+
+// OnConflictUpdate makes the statement an upsert : if a row having the same
+// values for the conflictColumns (a unique key) exists, the given
+// updateColumns are updated with the inserted values.
+// MySQL ignores conflictColumns (any unique key is used), but they're needed
+// by PostgreSQL and SQLite. It's not supported by all databases (see
+// adapters.Capabilities), and can't be used with FromSelect.
+// The column names are checked and quoted.
+//
+// Example :
+//
+//	_, err := db.InsertInto("stocks").
+//		Columns("product_id", "quantity").
+//		Values(42, 10).
+//		OnConflictUpdate([]string{"product_id"}, "quantity").
+//		Do()
+func (is *InsertStatement) OnConflictUpdate(conflictColumns []string, updateColumns ...string) *InsertStatement {
+	if is.verb == "UPSERT " {
+		is.error = fmt.Errorf("you can't mix UpsertInto and OnConflictUpdate to build an upsert query")
+		return is
+	}
+	if is.db.Capabilities().Upsert == adapters.UpsertNone {
+		is.error = &UnsupportedError{Feature: "upsert"}
+		return is
+	}
+
+	for _, column := range slices.Concat(conflictColumns, updateColumns) {
+		if err := checkIdentifier(column); err != nil {
+			is.error = err
+			return is
+		}
+	}
+
+	is.upsert = &upsertPart{
+		conflictColumns: is.db.quoteAll(conflictColumns),
+		updateColumns:   is.db.quoteAll(updateColumns),
+	}
+	return is
+}
+
+// OnConflictDoNothing makes the statement ignore the inserted rows having the
+// same values for the conflictColumns than existing rows, see
+// OnConflictUpdate.
+func (is *InsertStatement) OnConflictDoNothing(conflictColumns ...string) *InsertStatement {
+	return is.OnConflictUpdate(conflictColumns)
+}
+
+// writeUpsert writes the conflict handling clause of an INSERT statement.
+func (b *sqlBuffer) writeUpsert(adapter adapters.Adapter, upsert *upsertPart) *sqlBuffer {
+	if b.Err() != nil || upsert == nil {
+		return b
+	}
+
+	var clause string
+	var err error
+	switch capabilitiesOf(adapter).Upsert {
+	case adapters.UpsertOnConflict:
+		clause, err = adapters.BuildOnConflictUpsert(upsert.conflictColumns, upsert.updateColumns)
+	case adapters.UpsertOnDuplicateKey:
+		clause, err = adapters.BuildOnDuplicateKeyUpsert(upsert.conflictColumns, upsert.updateColumns, "")
+	case adapters.UpsertOnDuplicateKeyRowAlias:
+		clause, err = adapters.BuildOnDuplicateKeyUpsert(upsert.conflictColumns, upsert.updateColumns, "new")
+	default:
+		err = &UnsupportedError{Feature: "upsert"}
+	}
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.Write(" ").
+		Write(clause)
+	return b
+}
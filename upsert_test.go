@@ -0,0 +1,145 @@
+package godb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/cockroachdb"
+	"github.com/samonzeweb/godb/adapters/mssql"
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/postgresql"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpsert(t *testing.T) {
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("OnConflictUpdate updates the existing row", func() {
+			_, err := db.InsertInto("dummies").
+				Columns("id", "a_text", "another_text", "an_integer").
+				Values(1, "Updated", "Other", 42).
+				OnConflictUpdate([]string{"id"}, "a_text", "an_integer").
+				Do()
+			So(err, ShouldBeNil)
+
+			dummy := Dummy{}
+			err = db.Select(&dummy).Where("id = ?", 1).Do()
+			So(err, ShouldBeNil)
+			So(dummy.AText, ShouldEqual, "Updated")
+			So(dummy.AnotherText, ShouldEqual, "Premier")
+			So(dummy.AnInteger, ShouldEqual, 42)
+		})
+
+		Convey("OnConflictDoNothing leaves the existing row unchanged", func() {
+			_, err := db.InsertInto("dummies").
+				Columns("id", "a_text", "another_text", "an_integer").
+				Values(1, "Updated", "Other", 42).
+				OnConflictDoNothing("id").
+				Do()
+			So(err, ShouldBeNil)
+
+			count, err := db.SelectFrom("dummies").Where("a_text = ?", "First").Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Upserts can't be mixed with FromSelect", func() {
+			_, _, err := db.InsertInto("dummies").
+				Columns("a_text").
+				FromSelect(db.SelectFrom("dummies").Columns("a_text")).
+				OnConflictDoNothing().
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Invalid columns are rejected", func() {
+			_, _, err := db.InsertInto("dummies").
+				Columns("a_text").
+				Values("foo").
+				OnConflictUpdate([]string{"id"}, "a_text = 1").
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a postgresql adapter", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+
+		Convey("The ON CONFLICT clause is before RETURNING", func() {
+			sql, _, err := db.InsertInto("stocks").
+				Columns("product_id", "quantity").
+				Values(42, 10).
+				OnConflictUpdate([]string{"product_id"}, "quantity").
+				Returning("id").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `INSERT INTO stocks (product_id, quantity) VALUES (?, ?) ON CONFLICT ("product_id") DO UPDATE SET "quantity" = EXCLUDED."quantity" RETURNING id `)
+		})
+	})
+
+	Convey("Given a mysql 8 server adapter", t, func() {
+		adapter, err := mysql.NewServer("8.0.32")
+		So(err, ShouldBeNil)
+		db := &DB{adapter: adapter}
+
+		Convey("The ON DUPLICATE KEY UPDATE clause uses a row alias", func() {
+			sql, _, err := db.InsertInto("stocks").
+				Columns("product_id", "quantity").
+				Values(42, 10).
+				OnConflictUpdate([]string{"product_id"}, "quantity").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "INSERT INTO stocks (product_id, quantity) VALUES (?, ?) AS new ON DUPLICATE KEY UPDATE `quantity` = new.`quantity`")
+		})
+	})
+
+	Convey("Given a mssql adapter", t, func() {
+		db := &DB{adapter: mssql.Adapter}
+
+		Convey("Upserts are not supported", func() {
+			_, _, err := db.InsertInto("stocks").
+				Columns("product_id").
+				Values(42).
+				OnConflictDoNothing("product_id").
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUpsertInto(t *testing.T) {
+	Convey("Given a cockroachdb adapter", t, func() {
+		db := &DB{adapter: cockroachdb.Adapter}
+
+		Convey("UpsertInto builds an UPSERT statement", func() {
+			sql, args, err := db.UpsertInto("stocks").
+				Columns("product_id", "quantity").
+				Values(42, 10).
+				Returning("id").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `UPSERT INTO stocks (product_id, quantity) VALUES (?, ?) RETURNING id `)
+			So(args, ShouldResemble, []interface{}{42, 10})
+		})
+
+		Convey("UpsertInto can't be mixed with OnConflictUpdate", func() {
+			_, _, err := db.UpsertInto("stocks").
+				Columns("product_id", "quantity").
+				Values(42, 10).
+				OnConflictUpdate([]string{"product_id"}, "quantity").
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a postgresql adapter", t, func() {
+		db := &DB{adapter: postgresql.Adapter}
+
+		Convey("UpsertInto is not supported", func() {
+			_, _, err := db.UpsertInto("stocks").Columns("product_id").Values(42).ToSQL()
+			So(errors.Is(err, errors.ErrUnsupported), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,243 @@
+package godb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samonzeweb/godb/adapters"
+)
+
+// sqlFeatures is a set of SQL features used by a condition, which are not
+// supported by all databases. The statements check them when the condition
+// is added.
+type sqlFeatures int
+
+const (
+	windowFunctionsFeature sqlFeatures = 1 << iota
+	aggregateFilterFeature
+)
+
+// checkFeatures returns an UnsupportedError if one of the given features is
+// not supported by the database.
+func (db *DB) checkFeatures(features sqlFeatures) error {
+	capabilities := db.Capabilities()
+	if features&windowFunctionsFeature != 0 && !capabilities.WindowFunctions {
+		return &UnsupportedError{Feature: "window functions"}
+	}
+	if features&aggregateFilterFeature != 0 && !capabilities.AggregateFilter {
+		return &UnsupportedError{Feature: "FILTER"}
+	}
+	return nil
+}
+
+// WindowSpec is a window specification, used with the OVER clause of window
+// functions, or to define a named window with SelectStatement.Window.
+// Initialize it with the NewWindow function.
+//
+// Example :
+//
+// 	byDepartment := godb.NewWindow().
+// 		PartitionBy("department_id").
+// 		OrderBy("salary DESC")
+// 	err := db.SelectFrom("employees").
+// 		Columns("id", "name").
+// 		ColumnsQ(godb.RowNumber().Over(byDepartment).As("position")).
+// 		Do(&employees)
+type WindowSpec struct {
+	base        string
+	partitionBy []string
+	orderBy     []string
+	frame       string
+}
+
+// NewWindow initializes an empty window specification.
+func NewWindow() *WindowSpec {
+	return &WindowSpec{}
+}
+
+// From makes the specification extend an existing named window.
+func (ws *WindowSpec) From(name string) *WindowSpec {
+	ws.base = name
+	return ws
+}
+
+// PartitionBy adds expressions to the PARTITION BY part of the window.
+func (ws *WindowSpec) PartitionBy(expressions ...string) *WindowSpec {
+	ws.partitionBy = append(ws.partitionBy, expressions...)
+	return ws
+}
+
+// OrderBy adds expressions to the ORDER BY part of the window.
+func (ws *WindowSpec) OrderBy(expressions ...string) *WindowSpec {
+	ws.orderBy = append(ws.orderBy, expressions...)
+	return ws
+}
+
+// Frame sets the frame clause of the window, like
+// "ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW".
+func (ws *WindowSpec) Frame(frame string) *WindowSpec {
+	ws.frame = frame
+	return ws
+}
+
+// sql returns the window specification, without the parentheses.
+func (ws *WindowSpec) sql() string {
+	parts := make([]string, 0, 4)
+	if ws.base != "" {
+		parts = append(parts, ws.base)
+	}
+	if len(ws.partitionBy) > 0 {
+		parts = append(parts, "PARTITION BY "+strings.Join(ws.partitionBy, ", "))
+	}
+	if len(ws.orderBy) > 0 {
+		parts = append(parts, "ORDER BY "+strings.Join(ws.orderBy, ", "))
+	}
+	if ws.frame != "" {
+		parts = append(parts, ws.frame)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Function is an aggregate or window function call, used as a selected column.
+// Initialize it with Fn, or with helpers like RowNumber and Lag, and add it
+// to a select statement with As and SelectStatement.ColumnsQ.
+//
+// Example :
+//
+// 	err := db.SelectFrom("orders").
+// 		Columns("customer_id").
+// 		ColumnsQ(
+// 			godb.Fn("COUNT", "*").As("orders_count"),
+// 			godb.Fn("SUM", "amount").Filter(godb.Q("status = ?", "paid")).As("paid"),
+// 		).
+// 		GroupBy("customer_id").
+// 		Do(&totals)
+type Function struct {
+	name      string
+	arguments []string
+	args      []interface{}
+	filter    *Condition
+	over      string
+}
+
+// Fn initializes a function call with the given name and SQL arguments,
+// like Fn("SUM", "amount").
+func Fn(name string, arguments ...string) *Function {
+	return &Function{name: name, arguments: arguments}
+}
+
+// RowNumber initializes a ROW_NUMBER() window function call.
+func RowNumber() *Function {
+	return Fn("ROW_NUMBER")
+}
+
+// Rank initializes a RANK() window function call.
+func Rank() *Function {
+	return Fn("RANK")
+}
+
+// DenseRank initializes a DENSE_RANK() window function call.
+func DenseRank() *Function {
+	return Fn("DENSE_RANK")
+}
+
+// Lag initializes a LAG(column, offset, default) window function call. The
+// default value is given as an argument, it's omitted if nil.
+func Lag(column string, offset int, defaultValue interface{}) *Function {
+	return offsetFunction("LAG", column, offset, defaultValue)
+}
+
+// Lead initializes a LEAD(column, offset, default) window function call. The
+// default value is given as an argument, it's omitted if nil.
+func Lead(column string, offset int, defaultValue interface{}) *Function {
+	return offsetFunction("LEAD", column, offset, defaultValue)
+}
+
+// offsetFunction builds the LAG and LEAD function calls.
+func offsetFunction(name string, column string, offset int, defaultValue interface{}) *Function {
+	f := Fn(name, column, fmt.Sprintf("%d", offset))
+	if defaultValue != nil {
+		f.arguments = append(f.arguments, Placeholder)
+		f.args = append(f.args, defaultValue)
+	}
+	return f
+}
+
+// Filter adds a FILTER (WHERE condition) clause to an aggregate function.
+// Calling it again combines the conditions with AND.
+func (f *Function) Filter(condition *Condition) *Function {
+	if f.filter != nil {
+		condition = And(f.filter, condition)
+	}
+	f.filter = condition
+	return f
+}
+
+// Over adds an OVER clause with the given window specification.
+func (f *Function) Over(spec *WindowSpec) *Function {
+	f.over = "(" + spec.sql() + ")"
+	return f
+}
+
+// OverWindow adds an OVER clause referencing a window defined with
+// SelectStatement.Window.
+func (f *Function) OverWindow(name string) *Function {
+	f.over = name
+	return f
+}
+
+// Q returns the function call, without alias, as a condition holding its
+// arguments.
+func (f *Function) Q() *Condition {
+	condition := f.build()
+	if f.filter != nil && f.filter.rebuild != nil {
+		function := *f
+		condition.rebuild = func(syntax adapters.Syntax) *Condition {
+			rebuilt := function
+			rebuilt.filter = function.filter.withSyntax(syntax)
+			return rebuilt.build()
+		}
+	}
+	return condition
+}
+
+// build builds the condition of the function call.
+func (f *Function) build() *Condition {
+	sql := f.name + "(" + strings.Join(f.arguments, ", ") + ")"
+	args := f.args
+	var features sqlFeatures
+	if f.filter != nil {
+		if f.filter.err != nil {
+			return &Condition{err: f.filter.err}
+		}
+		sql += " FILTER (WHERE " + f.filter.sql + ")"
+		args = append(args[:len(args):len(args)], f.filter.args...)
+		features |= f.filter.features | aggregateFilterFeature
+	}
+	if f.over != "" {
+		sql += " OVER " + f.over
+		features |= windowFunctionsFeature
+	}
+	return &Condition{sql: sql, args: args, features: features}
+}
+
+// As returns the function call with an alias, to use it with
+// SelectStatement.ColumnsQ.
+func (f *Function) As(alias string) *Condition {
+	condition := f.Q()
+	if rebuild := condition.rebuild; rebuild != nil {
+		condition.rebuild = func(syntax adapters.Syntax) *Condition {
+			return withAlias(rebuild(syntax), alias)
+		}
+	}
+	return withAlias(condition, alias)
+}
+
+// withAlias adds an alias to the given condition.
+func withAlias(condition *Condition, alias string) *Condition {
+	if condition.err != nil {
+		return condition
+	}
+	condition.sql += " AS " + alias
+	return condition
+}
@@ -0,0 +1,204 @@
+package godb
+
+import (
+	"testing"
+
+	"github.com/samonzeweb/godb/adapters/mysql"
+	"github.com/samonzeweb/godb/adapters/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	Convey("Given a select statement with a sqlite adapter", t, func() {
+		db := &DB{adapter: sqlite.Adapter}
+
+		Convey("Window functions are written with their window specification", func() {
+			spec := NewWindow().
+				PartitionBy("a_text").
+				OrderBy("an_integer DESC", "id").
+				Frame("ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW")
+			sql, args, err := db.SelectFrom("dummies").
+				Columns("id").
+				ColumnsQ(RowNumber().Over(spec).As("position")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id, ROW_NUMBER() OVER (PARTITION BY a_text ORDER BY an_integer DESC, id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS position FROM dummies")
+			So(len(args), ShouldEqual, 0)
+		})
+
+		Convey("Named windows are written in the WINDOW clause", func() {
+			sql, _, err := db.SelectFrom("dummies").
+				Columns("id").
+				ColumnsQ(
+					Rank().OverWindow("w").As("r"),
+					DenseRank().Over(NewWindow().From("w").Frame("ROWS UNBOUNDED PRECEDING")).As("dr"),
+				).
+				Window("w", NewWindow().OrderBy("an_integer")).
+				OrderBy("id").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id, RANK() OVER w AS r, DENSE_RANK() OVER (w ROWS UNBOUNDED PRECEDING) AS dr FROM dummies WINDOW w AS (ORDER BY an_integer) ORDER BY id")
+		})
+
+		Convey("Columns arguments are before the other arguments", func() {
+			sql, args, err := db.SelectFrom("dummies").
+				Columns("id").
+				ColumnsQ(Lag("an_integer", 1, 0).OverWindow("w").As("previous")).
+				Where("a_text <> ?", "foo").
+				Window("w", NewWindow().OrderBy("id")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT id, LAG(an_integer, 1, ?) OVER w AS previous FROM dummies WHERE a_text <> ? WINDOW w AS (ORDER BY id)")
+			So(args, ShouldResemble, []interface{}{0, "foo"})
+		})
+
+		Convey("Lead omits a nil default value", func() {
+			sql, args, err := db.SelectFrom("dummies").
+				ColumnsQ(Lead("an_integer", 2, nil).OverWindow("w").As("next")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT LEAD(an_integer, 2) OVER w AS next FROM dummies")
+			So(len(args), ShouldEqual, 0)
+		})
+
+		Convey("Aggregates accept FILTER clauses", func() {
+			sql, args, err := db.SelectFrom("dummies").
+				Columns("a_text").
+				ColumnsQ(
+					Fn("COUNT", "*").As("total"),
+					Fn("SUM", "an_integer").
+						Filter(Col("an_integer").Gt(10)).
+						Filter(Q("another_text <> ?", "")).
+						As("filtered"),
+				).
+				GroupBy("a_text").
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, `SELECT a_text, COUNT(*) AS total, SUM(an_integer) FILTER (WHERE "an_integer" > ? AND another_text <> ?) AS filtered FROM dummies GROUP BY a_text`)
+			So(args, ShouldResemble, []interface{}{10, ""})
+		})
+
+		Convey("Clone copies the columns arguments and the windows", func() {
+			base := db.SelectFrom("dummies").
+				ColumnsQ(Lag("an_integer", 1, 0).OverWindow("w").As("previous")).
+				Window("w", NewWindow().OrderBy("id"))
+			clone := base.Clone()
+			clone.ColumnsQ(Lead("an_integer", 1, 99).OverWindow("w").As("next")).
+				Window("w2", NewWindow().OrderBy("a_text"))
+
+			sql, args, err := base.ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT LAG(an_integer, 1, ?) OVER w AS previous FROM dummies WINDOW w AS (ORDER BY id)")
+			So(args, ShouldResemble, []interface{}{0})
+		})
+
+		Convey("Filter errors are reported", func() {
+			_, _, err := db.SelectFrom("dummies").
+				ColumnsQ(Fn("COUNT", "*").Filter(Q("a = ?")).As("total")).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("ColumnsQ can't be mixed with ColumnsFromStruct", func() {
+			_, _, err := db.SelectFrom("dummies").
+				ColumnsFromStruct(&Dummy{}).
+				ColumnsQ(RowNumber().OverWindow("w").As("position")).
+				ToSQL()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a select statement with a MySQL 5.7 adapter", t, func() {
+		server, err := mysql.NewServer("5.7.42")
+		So(err, ShouldBeNil)
+		db := &DB{adapter: server}
+
+		Convey("Window functions are rejected", func() {
+			_, _, err := db.SelectFrom("dummies").
+				ColumnsQ(RowNumber().Over(NewWindow().OrderBy("id")).As("position")).
+				ToSQL()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+
+			_, _, err = db.SelectFrom("dummies").
+				OrderByQ(Rank().OverWindow("w").Q()).
+				ToSQL()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+		})
+
+		Convey("Named windows are rejected", func() {
+			_, _, err := db.SelectFrom("dummies").
+				Columns("id").
+				Window("w", NewWindow().OrderBy("id")).
+				ToSQL()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+		})
+	})
+
+	Convey("Given a select statement with a MySQL 8 adapter", t, func() {
+		server, err := mysql.NewServer("8.0.32")
+		So(err, ShouldBeNil)
+		db := &DB{adapter: server}
+
+		Convey("Window functions are accepted", func() {
+			sql, _, err := db.SelectFrom("dummies").
+				ColumnsQ(RowNumber().OverWindow("w").As("position")).
+				Window("w", NewWindow().OrderBy("id")).
+				ToSQL()
+			So(err, ShouldBeNil)
+			So(sql, ShouldEqual, "SELECT ROW_NUMBER() OVER w AS position FROM dummies WINDOW w AS (ORDER BY id)")
+		})
+
+		Convey("FILTER clauses are rejected", func() {
+			_, _, err := db.SelectFrom("dummies").
+				ColumnsQ(Fn("COUNT", "*").Filter(Q("an_integer > ?", 10)).As("total")).
+				ToSQL()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+
+			_, _, err = db.SelectFrom("dummies").
+				Columns("a_text").
+				GroupBy("a_text").
+				HavingQ(And(Q("COUNT(*) > ?", 1), Fn("COUNT", "*").Filter(Q("an_integer > ?", 10)).Q())).
+				ToSQL()
+			So(err, ShouldHaveSameTypeAs, &UnsupportedError{})
+		})
+	})
+
+	Convey("Given a test database", t, func() {
+		db := fixturesSetup(t)
+		defer db.Close()
+
+		Convey("Window functions fill the aliased fields", func() {
+			type positioned struct {
+				AText    string `db:"a_text"`
+				Position int    `db:"position"`
+				Previous int    `db:"previous"`
+			}
+			rows := make([]positioned, 0)
+			err := db.SelectFrom("dummies").
+				Columns("a_text").
+				ColumnsQ(
+					RowNumber().OverWindow("w").As("position"),
+					Lag("an_integer", 1, -1).OverWindow("w").As("previous"),
+				).
+				Window("w", NewWindow().OrderBy("an_integer DESC")).
+				OrderBy("an_integer").
+				Do(&rows)
+			So(err, ShouldBeNil)
+			So(rows, ShouldResemble, []positioned{
+				{AText: "First", Position: 3, Previous: 12},
+				{AText: "Second", Position: 2, Previous: 13},
+				{AText: "Third", Position: 1, Previous: -1},
+			})
+		})
+
+		Convey("Count ignores the window columns", func() {
+			count, err := db.SelectFrom("dummies").
+				ColumnsQ(RowNumber().OverWindow("w").As("position")).
+				Window("w", NewWindow().OrderBy("id")).
+				Where("an_integer > ?", 11).
+				Count()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+		})
+	})
+}